@@ -0,0 +1,447 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package agro computes agricultural indices from Open-Meteo weather data:
+// growing degree days, chill hours and units, frost windows, a daily water
+// balance and daily soil conditions.
+//
+// Daily indices are returned as a Series with a season-to-date total. To
+// cover a whole season, compute a Series from archive data and another from
+// a forecast, then Join them.
+package agro
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/tpryan/openmeteogo"
+)
+
+// Series is a daily agricultural index.
+type Series struct {
+	// Name is the snake_case name of the index, e.g. "growing_degree_days".
+	Name string
+	// Unit is the unit of Values and ToDate.
+	Unit openmeteogo.Unit
+	// Time holds the dates of Values, formatted as YYYY-MM-DD.
+	Time []string
+	// Values holds the index for each day.
+	Values []float64
+	// ToDate holds the total of Values from the season start to each day.
+	// It is zero for days before the season start.
+	ToDate []float64
+
+	seasonStart string
+	floor       bool
+}
+
+// accumulate fills in ToDate. If floor is set, the running total is never
+// allowed to drop below zero, as chill unit models require.
+func (s *Series) accumulate() {
+	s.ToDate = make([]float64, len(s.Values))
+	total := 0.0
+	for i, v := range s.Values {
+		if s.Time[i] < s.seasonStart {
+			continue
+		}
+		total += v
+		if s.floor && total < 0 {
+			total = 0
+		}
+		s.ToDate[i] = total
+	}
+}
+
+func newSeries(name string, unit openmeteogo.Unit, dates []string, values []float64, seasonStart time.Time, floor bool) *Series {
+	s := &Series{
+		Name:   name,
+		Unit:   unit,
+		Time:   dates,
+		Values: values,
+		floor:  floor,
+	}
+	if !seasonStart.IsZero() {
+		s.seasonStart = seasonStart.Format(openmeteogo.DateFormat)
+	}
+	s.accumulate()
+	return s
+}
+
+// Join concatenates series of the same index, such as one computed from
+// archive data and one from a forecast, and recomputes the season-to-date
+// totals. Where dates overlap the earlier series wins. The season start of
+// the first series is used.
+func Join(series ...*Series) (*Series, error) {
+	if len(series) == 0 {
+		return nil, fmt.Errorf("no series to join")
+	}
+	first := series[0]
+	joined := &Series{
+		Name:        first.Name,
+		Unit:        first.Unit,
+		seasonStart: first.seasonStart,
+		floor:       first.floor,
+	}
+	for _, s := range series {
+		if s.Name != first.Name || s.Unit != first.Unit {
+			return nil, fmt.Errorf("cannot join %s (%s) with %s (%s)", s.Name, s.Unit, first.Name, first.Unit)
+		}
+		for i, date := range s.Time {
+			if slices.Contains(joined.Time, date) {
+				continue
+			}
+			joined.Time = append(joined.Time, date)
+			joined.Values = append(joined.Values, s.Values[i])
+		}
+	}
+	order := make([]int, len(joined.Time))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		switch {
+		case joined.Time[a] < joined.Time[b]:
+			return -1
+		case joined.Time[a] > joined.Time[b]:
+			return 1
+		}
+		return 0
+	})
+	dates := make([]string, len(order))
+	values := make([]float64, len(order))
+	for i, j := range order {
+		dates[i], values[i] = joined.Time[j], joined.Values[j]
+	}
+	joined.Time, joined.Values = dates, values
+	joined.accumulate()
+	return joined, nil
+}
+
+// GDD configures GrowingDegreeDays.
+type GDD struct {
+	// Base is the temperature below which no growth occurs.
+	Base float64
+	// Cap is the temperature above which growth no longer increases.
+	// Zero means no cap.
+	Cap float64
+	// Unit is the unit of Base, Cap and the result. It defaults to °C.
+	Unit openmeteogo.Unit
+	// Metric is the hourly temperature to use, e.g. SoilTemperature6cm for
+	// germination models. If empty, daily temperature_2m_max and
+	// temperature_2m_min are used when present, and hourly temperature_2m
+	// otherwise.
+	Metric openmeteogo.Metric
+	// SeasonStart is the first day included in the season-to-date total.
+	// The zero value starts at the first day of the data.
+	SeasonStart time.Time
+}
+
+// GrowingDegreeDays returns the growing degree days for each day. From
+// daily data it uses the average of the day's minimum and maximum, each
+// clamped to the base and cap. From hourly data it averages the clamped
+// hourly temperatures, which is more accurate.
+func GrowingDegreeDays(wd *openmeteogo.WeatherData, cfg GDD) (*Series, error) {
+	unit := cfg.Unit
+	if unit == "" {
+		unit = openmeteogo.UnitCelsius
+	}
+	if unit.Dimension() != openmeteogo.DimensionTemperature {
+		return nil, fmt.Errorf("growing degree days: unsupported unit: %s", unit)
+	}
+	upper := math.Inf(1)
+	if cfg.Cap != 0 {
+		if cfg.Cap <= cfg.Base {
+			return nil, fmt.Errorf("growing degree days: cap %v is not above base %v", cfg.Cap, cfg.Base)
+		}
+		upper = cfg.Cap
+	}
+	gdd := func(t float64) float64 {
+		return math.Min(math.Max(t, cfg.Base), upper) - cfg.Base
+	}
+
+	if cfg.Metric == "" && len(wd.Daily.Temperature2mMax) > 0 && len(wd.Daily.Temperature2mMin) > 0 {
+		tmax, err := wd.DailyValues(openmeteogo.Temperature2mMax, unit)
+		if err != nil {
+			return nil, fmt.Errorf("growing degree days: %w", err)
+		}
+		tmin, err := wd.DailyValues(openmeteogo.Temperature2mMin, unit)
+		if err != nil {
+			return nil, fmt.Errorf("growing degree days: %w", err)
+		}
+		values := make([]float64, len(tmax))
+		for i := range values {
+			values[i] = (gdd(tmax[i]) + gdd(tmin[i])) / 2
+		}
+		return newSeries("growing_degree_days", unit, wd.Daily.Time, values, cfg.SeasonStart, false), nil
+	}
+
+	metric := cfg.Metric
+	if metric == "" {
+		metric = openmeteogo.Temperature2m
+	}
+	temps, err := wd.HourlyValues(metric, unit)
+	if err != nil {
+		return nil, fmt.Errorf("growing degree days: %w", err)
+	}
+	dates, values, err := daily(wd.Hourly.Time, temps, gdd, true)
+	if err != nil {
+		return nil, fmt.Errorf("growing degree days: %w", err)
+	}
+	return newSeries("growing_degree_days", unit, dates, values, cfg.SeasonStart, false), nil
+}
+
+// Chill configures ChillHours.
+type Chill struct {
+	// Min and Max bound the temperatures that count as chilling. If both
+	// are zero the common 0°C to 7.2°C (32°F to 45°F) model is used.
+	Min, Max float64
+	// Unit is the unit of Min and Max. It defaults to °C.
+	Unit openmeteogo.Unit
+	// SeasonStart is the first day included in the season-to-date total.
+	SeasonStart time.Time
+}
+
+// ChillHours returns the number of hours each day with a temperature above
+// Min and at or below Max. It needs hourly temperature_2m.
+func ChillHours(wd *openmeteogo.WeatherData, cfg Chill) (*Series, error) {
+	unit := cfg.Unit
+	if unit == "" {
+		unit = openmeteogo.UnitCelsius
+	}
+	lo, hi := cfg.Min, cfg.Max
+	if lo == 0 && hi == 0 {
+		var err error
+		if lo, err = openmeteogo.Convert(0, openmeteogo.UnitCelsius, unit); err != nil {
+			return nil, fmt.Errorf("chill hours: %w", err)
+		}
+		if hi, err = openmeteogo.Convert(7.2, openmeteogo.UnitCelsius, unit); err != nil {
+			return nil, fmt.Errorf("chill hours: %w", err)
+		}
+	}
+	temps, err := wd.HourlyValues(openmeteogo.Temperature2m, unit)
+	if err != nil {
+		return nil, fmt.Errorf("chill hours: %w", err)
+	}
+	dates, values, err := daily(wd.Hourly.Time, temps, func(t float64) float64 {
+		if t > lo && t <= hi {
+			return 1
+		}
+		return 0
+	}, false)
+	if err != nil {
+		return nil, fmt.Errorf("chill hours: %w", err)
+	}
+	return newSeries("chill_hours", openmeteogo.UnitHour, dates, values, cfg.SeasonStart, false), nil
+}
+
+// ChillUnits returns the chill units for each day using the Utah model,
+// from hourly temperature_2m. Warm hours subtract units; the season-to-date
+// total never drops below zero.
+func ChillUnits(wd *openmeteogo.WeatherData, seasonStart time.Time) (*Series, error) {
+	temps, err := wd.HourlyValues(openmeteogo.Temperature2m, openmeteogo.UnitCelsius)
+	if err != nil {
+		return nil, fmt.Errorf("chill units: %w", err)
+	}
+	dates, values, err := daily(wd.Hourly.Time, temps, utahChillUnit, false)
+	if err != nil {
+		return nil, fmt.Errorf("chill units: %w", err)
+	}
+	return newSeries("chill_units", openmeteogo.UnitDimensionless, dates, values, seasonStart, true), nil
+}
+
+// utahChillUnit returns the Utah model weight for an hour at t °C.
+func utahChillUnit(t float64) float64 {
+	switch {
+	case t < 1.5:
+		return 0
+	case t < 2.5:
+		return 0.5
+	case t < 9.2:
+		return 1
+	case t < 12.5:
+		return 0.5
+	case t < 16:
+		return 0
+	case t < 18:
+		return -0.5
+	}
+	return -1
+}
+
+// WaterBalance returns precipitation minus FAO reference
+// evapotranspiration (ET₀) for each day, in the precipitation unit of the
+// response. Negative values mean a deficit. It uses daily precipitation_sum
+// and et0_fao_evapotranspiration when present, and sums the hourly
+// precipitation and et0_fao_evapotranspiration otherwise.
+func WaterBalance(wd *openmeteogo.WeatherData, seasonStart time.Time) (*Series, error) {
+	d := wd.Daily
+	if len(d.PrecipitationSum) > 0 && len(d.Et0FaoEvapotranspiration) > 0 {
+		unit, err := wd.DailyUnits.Unit(openmeteogo.PrecipitationSum)
+		if err != nil {
+			return nil, fmt.Errorf("water balance: %w", err)
+		}
+		precip, err := wd.DailyValues(openmeteogo.PrecipitationSum, unit)
+		if err != nil {
+			return nil, fmt.Errorf("water balance: %w", err)
+		}
+		et0, err := wd.DailyValues(openmeteogo.Et0FaoEvapotranspiration, unit)
+		if err != nil {
+			return nil, fmt.Errorf("water balance: %w", err)
+		}
+		values := make([]float64, len(precip))
+		for i := range values {
+			values[i] = precip[i] - et0[i]
+		}
+		return newSeries("water_balance", unit, d.Time, values, seasonStart, false), nil
+	}
+
+	unit, err := wd.HourlyUnits.Unit(openmeteogo.Precipitation)
+	if err != nil {
+		return nil, fmt.Errorf("water balance: %w", err)
+	}
+	precip, err := wd.HourlyValues(openmeteogo.Precipitation, unit)
+	if err != nil {
+		return nil, fmt.Errorf("water balance: %w", err)
+	}
+	et0, err := wd.HourlyValues(openmeteogo.Et0FaoEvapotranspiration, unit)
+	if err != nil {
+		return nil, fmt.Errorf("water balance: %w", err)
+	}
+	for i := range precip {
+		precip[i] -= et0[i]
+	}
+	dates, values, err := daily(wd.Hourly.Time, precip, func(v float64) float64 { return v }, false)
+	if err != nil {
+		return nil, fmt.Errorf("water balance: %w", err)
+	}
+	return newSeries("water_balance", unit, dates, values, seasonStart, false), nil
+}
+
+// Frost configures FrostWindows.
+type Frost struct {
+	// Threshold is the temperature at or below which frost is expected.
+	Threshold float64
+	// Unit is the unit of Threshold. It defaults to °C.
+	Unit openmeteogo.Unit
+	// Metric is the hourly temperature to use. It defaults to
+	// temperature_2m; use SoilTemperature0cm for ground frost.
+	Metric openmeteogo.Metric
+}
+
+// FrostWindow is a run of consecutive frost hours or days.
+type FrostWindow struct {
+	// Start and End are the first and last frost timestamps in the window,
+	// as they appear in the data.
+	Start, End string
+	// Steps is the number of hours, or days for daily data, in the window.
+	Steps int
+	// Min is the lowest temperature in the window, in the Frost unit.
+	Min float64
+}
+
+// FrostWindows returns the periods when the temperature is at or below the
+// threshold. It uses hourly data when the metric is present and falls back
+// to daily temperature_2m_min, in which case each step is a day.
+func FrostWindows(wd *openmeteogo.WeatherData, cfg Frost) ([]FrostWindow, error) {
+	unit := cfg.Unit
+	if unit == "" {
+		unit = openmeteogo.UnitCelsius
+	}
+	metric := cfg.Metric
+	if metric == "" {
+		metric = openmeteogo.Temperature2m
+	}
+
+	times := wd.Hourly.Time
+	temps, err := wd.HourlyValues(metric, unit)
+	if err != nil {
+		if metric != openmeteogo.Temperature2m || len(wd.Daily.Temperature2mMin) == 0 {
+			return nil, fmt.Errorf("frost windows: %w", err)
+		}
+		times = wd.Daily.Time
+		if temps, err = wd.DailyValues(openmeteogo.Temperature2mMin, unit); err != nil {
+			return nil, fmt.Errorf("frost windows: %w", err)
+		}
+	}
+
+	var windows []FrostWindow
+	var current *FrostWindow
+	for i, t := range temps {
+		if t > cfg.Threshold {
+			current = nil
+			continue
+		}
+		if current == nil {
+			windows = append(windows, FrostWindow{Start: times[i], Min: t})
+			current = &windows[len(windows)-1]
+		}
+		current.End = times[i]
+		current.Steps++
+		current.Min = math.Min(current.Min, t)
+	}
+	return windows, nil
+}
+
+// soilMetrics lists the hourly soil fields Soil aggregates.
+var soilMetrics = openmeteogo.Metrics{
+	openmeteogo.SoilTemperature0cm,
+	openmeteogo.SoilTemperature6cm,
+	openmeteogo.SoilTemperature18cm,
+	openmeteogo.SoilTemperature54cm,
+	openmeteogo.SoilMoisture0To1cm,
+	openmeteogo.SoilMoisture1To3cm,
+	openmeteogo.SoilMoisture3To9cm,
+	openmeteogo.SoilMoisture9To27cm,
+}
+
+// Soil returns the daily mean of every hourly soil temperature and soil
+// moisture series present in the data, keyed as "<metric>_mean". Values are
+// in the units of the response.
+func Soil(wd *openmeteogo.WeatherData) ([]openmeteogo.Bucket, error) {
+	var aggregates []openmeteogo.Aggregate
+	for _, m := range soilMetrics {
+		if _, err := wd.Hourly.Values(m); err == nil {
+			aggregates = append(aggregates, openmeteogo.Aggregate{Metric: m, Aggregation: openmeteogo.AggregateMean})
+		}
+	}
+	if len(aggregates) == 0 {
+		return nil, fmt.Errorf("soil: no soil temperature or moisture data")
+	}
+	return wd.Hourly.Resample(openmeteogo.Days(0), aggregates...)
+}
+
+// daily applies f to each hourly value and sums the results per calendar
+// day, or averages them if mean is set.
+func daily(times []string, values []float64, f func(float64) float64, mean bool) ([]string, []float64, error) {
+	mapped := make([]float64, len(values))
+	for i, v := range values {
+		mapped[i] = f(v)
+	}
+	a := openmeteogo.AggregateSum
+	if mean {
+		a = openmeteogo.AggregateMean
+	}
+	buckets, err := openmeteogo.ResampleSeries(times, mapped, openmeteogo.Days(0), a)
+	if err != nil {
+		return nil, nil, err
+	}
+	dates := make([]string, len(buckets))
+	sums := make([]float64, len(buckets))
+	for i, b := range buckets {
+		dates[i], sums[i] = b.Start.Format(openmeteogo.DateFormat), b.Values[a.String()]
+	}
+	return dates, sums, nil
+}
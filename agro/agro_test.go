@@ -0,0 +1,242 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agro
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tpryan/openmeteogo"
+)
+
+func dailyData() *openmeteogo.WeatherData {
+	return &openmeteogo.WeatherData{
+		DailyUnits: openmeteogo.DailyUnits{
+			Temperature2mMax:         "°C",
+			Temperature2mMin:         "°C",
+			PrecipitationSum:         "mm",
+			Et0FaoEvapotranspiration: "mm",
+		},
+		Daily: openmeteogo.Daily{
+			Time:                     []string{"2025-04-01", "2025-04-02", "2025-04-03"},
+			Temperature2mMax:         []float64{20, 35, 8},
+			Temperature2mMin:         []float64{6, 18, -3},
+			PrecipitationSum:         []float64{0, 12.5, 3},
+			Et0FaoEvapotranspiration: []float64{4, 5.5, 1},
+		},
+	}
+}
+
+// hourlyData returns two days of hourly data with the given temperatures
+// repeated.
+func hourlyData(temps ...float64) *openmeteogo.WeatherData {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	wd := &openmeteogo.WeatherData{
+		HourlyUnits: openmeteogo.HourlyUnits{Temperature2m: "°C"},
+	}
+	for i := 0; i < 48; i++ {
+		wd.Hourly.Time = append(wd.Hourly.Time, start.Add(time.Duration(i)*time.Hour).Format(openmeteogo.HourFormat))
+		wd.Hourly.Temperature2m = append(wd.Hourly.Temperature2m, temps[i%len(temps)])
+	}
+	return wd
+}
+
+func TestGrowingDegreeDays(t *testing.T) {
+	tests := map[string]struct {
+		wd         *openmeteogo.WeatherData
+		cfg        GDD
+		want       []float64
+		wantToDate []float64
+		wantUnit   openmeteogo.Unit
+	}{
+		"daily base 10": {
+			wd:         dailyData(),
+			cfg:        GDD{Base: 10},
+			want:       []float64{5, 16.5, 0},
+			wantToDate: []float64{5, 21.5, 21.5},
+			wantUnit:   openmeteogo.UnitCelsius,
+		},
+		"daily base 10 cap 30": {
+			wd:         dailyData(),
+			cfg:        GDD{Base: 10, Cap: 30},
+			want:       []float64{5, 14, 0},
+			wantToDate: []float64{5, 19, 19},
+			wantUnit:   openmeteogo.UnitCelsius,
+		},
+		"daily in fahrenheit": {
+			wd:         dailyData(),
+			cfg:        GDD{Base: 50, Unit: openmeteogo.UnitFahrenheit},
+			want:       []float64{9, 29.7, 0},
+			wantToDate: []float64{9, 38.7, 38.7},
+			wantUnit:   openmeteogo.UnitFahrenheit,
+		},
+		"season starts on second day": {
+			wd:         dailyData(),
+			cfg:        GDD{Base: 10, SeasonStart: time.Date(2025, 4, 2, 0, 0, 0, 0, time.UTC)},
+			want:       []float64{5, 16.5, 0},
+			wantToDate: []float64{0, 16.5, 16.5},
+			wantUnit:   openmeteogo.UnitCelsius,
+		},
+		"hourly": {
+			wd:         hourlyData(5, 15, 25),
+			cfg:        GDD{Base: 10, Cap: 20},
+			want:       []float64{5, 5},
+			wantToDate: []float64{5, 10},
+			wantUnit:   openmeteogo.UnitCelsius,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := GrowingDegreeDays(tc.wd, tc.cfg)
+			require.NoError(t, err)
+			assert.Equal(t, "growing_degree_days", got.Name)
+			assert.Equal(t, tc.wantUnit, got.Unit)
+			assert.InDeltaSlice(t, tc.want, got.Values, 1e-9)
+			assert.InDeltaSlice(t, tc.wantToDate, got.ToDate, 1e-9)
+		})
+	}
+}
+
+func TestGrowingDegreeDays_Errors(t *testing.T) {
+	_, err := GrowingDegreeDays(dailyData(), GDD{Base: 10, Cap: 5})
+	assert.Error(t, err, "cap below base")
+	_, err = GrowingDegreeDays(dailyData(), GDD{Base: 10, Unit: openmeteogo.UnitMetre})
+	assert.Error(t, err, "not a temperature")
+	_, err = GrowingDegreeDays(dailyData(), GDD{Base: 10, Metric: openmeteogo.SoilTemperature6cm})
+	assert.Error(t, err, "no hourly soil temperature")
+}
+
+func TestChill(t *testing.T) {
+	wd := hourlyData(-2, 1, 5, 7.2, 10, 20)
+
+	hours, err := ChillHours(wd, Chill{})
+	require.NoError(t, err)
+	assert.Equal(t, openmeteogo.UnitHour, hours.Unit)
+	assert.Equal(t, []string{"2025-01-01", "2025-01-02"}, hours.Time)
+	assert.Equal(t, []float64{12, 12}, hours.Values)
+	assert.Equal(t, []float64{12, 24}, hours.ToDate)
+
+	// 0, 0, 1, 1, 0.5, -1 per six hours.
+	units, err := ChillUnits(wd, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, []float64{6, 6}, units.Values)
+
+	warm, err := ChillUnits(hourlyData(25), time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, []float64{-24, -24}, warm.Values)
+	assert.Equal(t, []float64{0, 0}, warm.ToDate, "season total is floored at zero")
+}
+
+func TestChill_UnrelatedUnit(t *testing.T) {
+	wd := hourlyData(5)
+	wd.HourlyUnits.WindSpeed10m = "furlong/fortnight"
+
+	hours, err := ChillHours(wd, Chill{})
+	require.NoError(t, err, "only the temperature label is parsed")
+	assert.Equal(t, []float64{24, 24}, hours.Values)
+}
+
+func TestWaterBalance(t *testing.T) {
+	got, err := WaterBalance(dailyData(), time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, openmeteogo.UnitMillimetre, got.Unit)
+	assert.InDeltaSlice(t, []float64{-4, 7, 2}, got.Values, 1e-9)
+	assert.InDeltaSlice(t, []float64{-4, 3, 5}, got.ToDate, 1e-9)
+
+	wd := hourlyData(10)
+	wd.HourlyUnits.Precipitation = "inch"
+	wd.HourlyUnits.Et0FaoEvapotranspiration = "mm"
+	for range wd.Hourly.Time {
+		wd.Hourly.Precipitation = append(wd.Hourly.Precipitation, 0.01)
+		wd.Hourly.Et0FaoEvapotranspiration = append(wd.Hourly.Et0FaoEvapotranspiration, 0.127)
+	}
+	got, err = WaterBalance(wd, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, openmeteogo.UnitInch, got.Unit)
+	assert.InDeltaSlice(t, []float64{0.12, 0.12}, got.Values, 1e-9)
+
+	_, err = WaterBalance(hourlyData(10), time.Time{})
+	assert.Error(t, err)
+}
+
+func TestJoin(t *testing.T) {
+	archive, err := WaterBalance(dailyData(), time.Date(2025, 4, 2, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	forecastData := dailyData()
+	forecastData.Daily.Time = []string{"2025-04-03", "2025-04-04", "2025-04-05"}
+	forecastData.Daily.PrecipitationSum = []float64{100, 2, 0}
+	forecastData.Daily.Et0FaoEvapotranspiration = []float64{0, 1, 3}
+	forecast, err := WaterBalance(forecastData, time.Time{})
+	require.NoError(t, err)
+
+	got, err := Join(forecast, archive)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2025-04-01", "2025-04-02", "2025-04-03", "2025-04-04", "2025-04-05"}, got.Time)
+	assert.InDeltaSlice(t, []float64{-4, 7, 100, 1, -3}, got.Values, 1e-9)
+	// The season start of the first series, the forecast, is unset.
+	assert.InDeltaSlice(t, []float64{-4, 3, 103, 104, 101}, got.ToDate, 1e-9)
+
+	got, err = Join(archive, forecast)
+	require.NoError(t, err)
+	assert.InDeltaSlice(t, []float64{-4, 7, 2, 1, -3}, got.Values, 1e-9)
+	assert.InDeltaSlice(t, []float64{0, 7, 9, 10, 7}, got.ToDate, 1e-9)
+
+	hours, err := ChillHours(hourlyData(5), Chill{})
+	require.NoError(t, err)
+	_, err = Join(archive, hours)
+	assert.Error(t, err)
+	_, err = Join()
+	assert.Error(t, err)
+}
+
+func TestFrostWindows(t *testing.T) {
+	wd := hourlyData(3, 0, -1.5, 2, 2, -0.5)
+
+	got, err := FrostWindows(wd, Frost{})
+	require.NoError(t, err)
+	require.Len(t, got, 16)
+	assert.Equal(t, FrostWindow{Start: "2025-01-01T01:00", End: "2025-01-01T02:00", Steps: 2, Min: -1.5}, got[0])
+	assert.Equal(t, FrostWindow{Start: "2025-01-01T05:00", End: "2025-01-01T05:00", Steps: 1, Min: -0.5}, got[1])
+
+	got, err = FrostWindows(dailyData(), Frost{Threshold: 32, Unit: openmeteogo.UnitFahrenheit})
+	require.NoError(t, err)
+	assert.Equal(t, []FrostWindow{{Start: "2025-04-03", End: "2025-04-03", Steps: 1, Min: 26.6}}, got)
+
+	_, err = FrostWindows(dailyData(), Frost{Metric: openmeteogo.SoilTemperature0cm})
+	assert.Error(t, err, "ground frost needs hourly soil temperature")
+}
+
+func TestSoil(t *testing.T) {
+	dat, err := os.ReadFile("../test_data/all_params.json")
+	require.NoError(t, err)
+	var wd openmeteogo.WeatherData
+	require.NoError(t, json.Unmarshal(dat, &wd))
+
+	got, err := Soil(&wd)
+	require.NoError(t, err)
+	require.Len(t, got, 7)
+	assert.Equal(t, 24, got[0].Hours)
+	assert.Len(t, got[0].Values, len(soilMetrics))
+	assert.Contains(t, got[0].Values, "soil_moisture_3_to_9cm_mean")
+
+	_, err = Soil(hourlyData(10))
+	assert.Error(t, err)
+}
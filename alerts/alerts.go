@@ -0,0 +1,308 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package alerts evaluates declarative threshold rules against Open-Meteo
+// weather data. Rules can be written in Go or loaded from YAML or JSON with
+// ParseRules, for example:
+//
+//   - name: Damaging gusts
+//     severity: severe
+//     metric: wind_gusts_10m
+//     op: ">"
+//     value: 80
+//     unit: km/h
+//     consecutive: 2
+//     before: 24h
+//   - name: Thunderstorms tomorrow
+//     severity: moderate
+//     section: daily
+//     weather_groups: [thunderstorm]
+//     anchor: today
+//     after: 24h
+//     before: 48h
+package alerts
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/tpryan/openmeteogo"
+	"gopkg.in/yaml.v3"
+)
+
+// Severity ranks how serious an alert is.
+type Severity string
+
+const (
+	SeverityMinor    Severity = "minor"
+	SeverityModerate Severity = "moderate"
+	SeveritySevere   Severity = "severe"
+	SeverityExtreme  Severity = "extreme"
+)
+
+var severities = []Severity{SeverityMinor, SeverityModerate, SeveritySevere, SeverityExtreme}
+
+// Anchor is the reference time a rule's window is measured from.
+type Anchor string
+
+const (
+	// AnchorNow measures the window from the evaluation time.
+	AnchorNow Anchor = "now"
+	// AnchorToday measures the window from midnight on the day of the
+	// evaluation time, so After 24h and Before 48h mean tomorrow.
+	AnchorToday Anchor = "today"
+)
+
+// Duration is a time.Duration written as a string such as "24h" in YAML
+// and JSON.
+type Duration time.Duration
+
+// MarshalText implements encoding.TextMarshaler.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// Rule is a condition that raises an alert when it holds.
+type Rule struct {
+	// Name identifies the rule in alert events.
+	Name string `json:"name" yaml:"name"`
+	// Severity is copied to alert events.
+	Severity Severity `json:"severity" yaml:"severity"`
+	// Section is "hourly" or "daily". The default is hourly.
+	Section string `json:"section,omitempty" yaml:"section,omitempty"`
+	// Metric is the metric to test. It defaults to weather_code, for rules
+	// that use WeatherGroups.
+	Metric openmeteogo.Metric `json:"metric,omitempty" yaml:"metric,omitempty"`
+	// Op compares the metric with Value: ">", ">=", "<", "<=", "==" or "!=".
+	Op string `json:"op,omitempty" yaml:"op,omitempty"`
+	// Value is the threshold, in Unit.
+	Value float64 `json:"value,omitempty" yaml:"value,omitempty"`
+	// Unit is the unit of Value. Data is converted to it before comparing.
+	// If empty, Value is in the units of the data.
+	Unit openmeteogo.Unit `json:"unit,omitempty" yaml:"unit,omitempty"`
+	// WeatherGroups, if set, replaces the Op and Value comparison: the rule
+	// holds when the weather code falls in any of the groups.
+	WeatherGroups []openmeteogo.WeatherGroup `json:"weather_groups,omitempty" yaml:"weather_groups,omitempty"`
+	// Consecutive is the number of consecutive hours, or days, the
+	// condition must hold to raise an alert. The default is 1.
+	Consecutive int `json:"consecutive,omitempty" yaml:"consecutive,omitempty"`
+	// Anchor is the reference time of the window. The default is AnchorNow.
+	Anchor Anchor `json:"anchor,omitempty" yaml:"anchor,omitempty"`
+	// After and Before bound the window of data the rule looks at,
+	// relative to Anchor. A zero Before leaves the window open-ended.
+	After  Duration `json:"after,omitempty" yaml:"after,omitempty"`
+	Before Duration `json:"before,omitempty" yaml:"before,omitempty"`
+}
+
+// ParseRules parses a list of rules from YAML or JSON and validates them.
+func ParseRules(data []byte) ([]Rule, error) {
+	var rules []Rule
+	if err := yaml.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("parsing rules: %w", err)
+	}
+	for i, r := range rules {
+		if err := r.Validate(); err != nil {
+			return nil, fmt.Errorf("rule %d: %w", i, err)
+		}
+	}
+	return rules, nil
+}
+
+// Validate reports whether the rule is complete and consistent.
+func (r Rule) Validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("rule has no name")
+	}
+	if !slices.Contains(severities, r.Severity) {
+		return fmt.Errorf("%s: unsupported severity: %q", r.Name, r.Severity)
+	}
+	if r.Section != "" && r.Section != "hourly" && r.Section != "daily" {
+		return fmt.Errorf("%s: unsupported section: %q", r.Name, r.Section)
+	}
+	if r.Anchor != "" && r.Anchor != AnchorNow && r.Anchor != AnchorToday {
+		return fmt.Errorf("%s: unsupported anchor: %q", r.Name, r.Anchor)
+	}
+	if r.Before != 0 && r.Before <= r.After {
+		return fmt.Errorf("%s: before must be later than after", r.Name)
+	}
+	if r.Consecutive < 0 {
+		return fmt.Errorf("%s: consecutive must not be negative", r.Name)
+	}
+	if len(r.WeatherGroups) > 0 {
+		return nil
+	}
+	if r.Metric == "" {
+		return fmt.Errorf("%s: rule has no metric", r.Name)
+	}
+	if _, ok := compare[r.Op]; !ok {
+		return fmt.Errorf("%s: unsupported op: %q", r.Name, r.Op)
+	}
+	return nil
+}
+
+var compare = map[string]func(a, b float64) bool{
+	">":  func(a, b float64) bool { return a > b },
+	">=": func(a, b float64) bool { return a >= b },
+	"<":  func(a, b float64) bool { return a < b },
+	"<=": func(a, b float64) bool { return a <= b },
+	"==": func(a, b float64) bool { return a == b },
+	"!=": func(a, b float64) bool { return a != b },
+}
+
+// Event is an alert raised by a rule.
+type Event struct {
+	// Rule is the name of the rule.
+	Rule string
+	// Severity is the severity of the rule.
+	Severity Severity
+	// Metric is the metric that triggered the rule.
+	Metric openmeteogo.Metric
+	// Start and End are the first and last timestamps the condition held,
+	// as they appear in the data.
+	Start, End string
+	// Values holds the triggering values, one per step, in Unit.
+	Values []float64
+	// Peak is the most extreme triggering value: the lowest for "<" and
+	// "<=" rules and the highest otherwise.
+	Peak float64
+	// Unit is the unit of Values and Peak.
+	Unit openmeteogo.Unit
+	// Description describes the peak weather code for rules that use
+	// WeatherGroups, e.g. "Thunderstorm: Slight or moderate".
+	Description string
+}
+
+// Evaluate runs every rule against wd and returns the alerts raised, in
+// rule order. now is the evaluation time; its wall clock time is compared
+// with the data's timestamps, which are in the response's timezone.
+func Evaluate(wd *openmeteogo.WeatherData, rules []Rule, now time.Time) ([]Event, error) {
+	now = time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), now.Minute(), 0, 0, time.UTC)
+	var events []Event
+	for _, r := range rules {
+		if err := r.Validate(); err != nil {
+			return nil, err
+		}
+		e, err := evaluate(wd, r, now)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", r.Name, err)
+		}
+		events = append(events, e...)
+	}
+	return events, nil
+}
+
+func evaluate(wd *openmeteogo.WeatherData, r Rule, now time.Time) ([]Event, error) {
+	metric := r.Metric
+	if metric == "" {
+		metric = openmeteogo.WeatherCode
+	}
+
+	// Only the rule's own metric is read, so labels of other metrics do not
+	// need to be recognised.
+	times, layout := wd.Hourly.Time, openmeteogo.HourFormat
+	unitOf, valuesOf := wd.HourlyUnits.Unit, wd.HourlyValues
+	if r.Section == "daily" {
+		times, layout = wd.Daily.Time, openmeteogo.DateFormat
+		unitOf, valuesOf = wd.DailyUnits.Unit, wd.DailyValues
+	}
+	unit, err := unitOf(metric)
+	if err != nil {
+		return nil, err
+	}
+	if r.Unit != "" && len(r.WeatherGroups) == 0 {
+		unit = r.Unit
+	}
+	values, err := valuesOf(metric, unit)
+	if err != nil {
+		return nil, err
+	}
+
+	anchor := now
+	if r.Anchor == AnchorToday {
+		anchor = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	from := anchor.Add(time.Duration(r.After))
+	to := time.Time{}
+	if r.Before != 0 {
+		to = anchor.Add(time.Duration(r.Before))
+	}
+	// A daily step covers the whole day, so it is in the window if any of
+	// it is.
+	step := time.Duration(0)
+	if r.Section == "daily" {
+		step = 24*time.Hour - time.Nanosecond
+	}
+
+	holds := func(v float64) bool {
+		if len(r.WeatherGroups) > 0 {
+			return slices.Contains(r.WeatherGroups, openmeteogo.CodeGroup(int(v)))
+		}
+		return compare[r.Op](v, r.Value)
+	}
+	need := max(r.Consecutive, 1)
+
+	var events []Event
+	var run []int
+	flush := func() {
+		if len(run) >= need {
+			e := Event{
+				Rule:     r.Name,
+				Severity: r.Severity,
+				Metric:   metric,
+				Start:    times[run[0]],
+				End:      times[run[len(run)-1]],
+				Unit:     unit,
+			}
+			for _, i := range run {
+				e.Values = append(e.Values, values[i])
+			}
+			if r.Op == "<" || r.Op == "<=" {
+				e.Peak = slices.Min(e.Values)
+			} else {
+				e.Peak = slices.Max(e.Values)
+			}
+			if len(r.WeatherGroups) > 0 {
+				e.Description = openmeteogo.DescribeCode(int(e.Peak))
+			}
+			events = append(events, e)
+		}
+		run = run[:0]
+	}
+	for i, ts := range times {
+		t, err := time.Parse(layout, ts)
+		if err != nil {
+			return nil, fmt.Errorf("parsing time %q: %w", ts, err)
+		}
+		inWindow := !t.Add(step).Before(from) && (to.IsZero() || t.Before(to))
+		if inWindow && !math.IsNaN(values[i]) && holds(values[i]) {
+			run = append(run, i)
+			continue
+		}
+		flush()
+	}
+	flush()
+	return events, nil
+}
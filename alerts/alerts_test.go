@@ -0,0 +1,246 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alerts
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tpryan/openmeteogo"
+)
+
+// hourly returns 30 hours of data from 2025-01-01T00:00 with gusts of
+// 10 m/s and temperatures of 5°C, overridden by the given hours.
+func hourly(gusts, temps map[int]float64) *openmeteogo.WeatherData {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	wd := &openmeteogo.WeatherData{
+		HourlyUnits: openmeteogo.HourlyUnits{WindGusts10m: "m/s", Temperature2m: "°C"},
+	}
+	for i := 0; i < 30; i++ {
+		wd.Hourly.Time = append(wd.Hourly.Time, start.Add(time.Duration(i)*time.Hour).Format(openmeteogo.HourFormat))
+		g, ok := gusts[i]
+		if !ok {
+			g = 10
+		}
+		t, ok := temps[i]
+		if !ok {
+			t = 5
+		}
+		wd.Hourly.WindGusts10m = append(wd.Hourly.WindGusts10m, g)
+		wd.Hourly.Temperature2m = append(wd.Hourly.Temperature2m, t)
+	}
+	return wd
+}
+
+func TestEvaluate_ConsecutiveGusts(t *testing.T) {
+	// 25 m/s is 90 km/h. The single hour at 08:00 is too short, and the
+	// hours from 26 are outside the next 24 hours.
+	wd := hourly(map[int]float64{3: 25, 4: 25, 8: 30, 26: 25, 27: 25}, nil)
+	rule := Rule{
+		Name:        "Damaging gusts",
+		Severity:    SeveritySevere,
+		Metric:      openmeteogo.WindGusts10m,
+		Op:          ">",
+		Value:       80,
+		Unit:        openmeteogo.UnitKmh,
+		Consecutive: 2,
+		Before:      Duration(24 * time.Hour),
+	}
+
+	got, err := Evaluate(wd, []Rule{rule}, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "Damaging gusts", got[0].Rule)
+	assert.Equal(t, SeveritySevere, got[0].Severity)
+	assert.Equal(t, "2025-01-01T03:00", got[0].Start)
+	assert.Equal(t, "2025-01-01T04:00", got[0].End)
+	assert.InDeltaSlice(t, []float64{90, 90}, got[0].Values, 1e-9)
+	assert.InDelta(t, 90, got[0].Peak, 1e-9)
+	assert.Equal(t, openmeteogo.UnitKmh, got[0].Unit)
+	assert.Empty(t, got[0].Description)
+}
+
+func TestEvaluate_FrostTonight(t *testing.T) {
+	// Frost in the morning has passed; tonight runs from 20:00 until 03:00.
+	temps := map[int]float64{10: -1, 20: 0, 21: -1, 22: -2, 23: -2, 24: -3, 25: -3, 26: -2, 27: -1}
+	wd := hourly(nil, temps)
+	// The rule does not read gusts, so their label need not be recognised.
+	wd.HourlyUnits.WindGusts10m = "furlong/fortnight"
+	rule := Rule{
+		Name:     "Frost tonight",
+		Severity: SeverityModerate,
+		Metric:   openmeteogo.Temperature2m,
+		Op:       "<=",
+		Value:    0,
+		Anchor:   AnchorToday,
+		After:    Duration(18 * time.Hour),
+		Before:   Duration(32 * time.Hour),
+	}
+
+	got, err := Evaluate(wd, []Rule{rule}, time.Date(2025, 1, 1, 15, 30, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, "2025-01-01T20:00", got[0].Start)
+	assert.Equal(t, "2025-01-02T03:00", got[0].End)
+	assert.Len(t, got[0].Values, 8)
+	assert.Equal(t, -3.0, got[0].Peak)
+	assert.Equal(t, openmeteogo.UnitCelsius, got[0].Unit)
+}
+
+func TestEvaluate_WeatherGroups(t *testing.T) {
+	wd := &openmeteogo.WeatherData{
+		DailyUnits: openmeteogo.DailyUnits{WeatherCode: "wmo code"},
+		Daily: openmeteogo.Daily{
+			Time:        []string{"2025-01-01", "2025-01-02", "2025-01-03"},
+			WeatherCode: []int{95, 96, 61},
+		},
+	}
+	tomorrow := Rule{
+		Name:          "Thunderstorms tomorrow",
+		Severity:      SeverityModerate,
+		Section:       "daily",
+		WeatherGroups: []openmeteogo.WeatherGroup{openmeteogo.GroupThunderstorm},
+		Anchor:        AnchorToday,
+		After:         Duration(24 * time.Hour),
+		Before:        Duration(48 * time.Hour),
+	}
+	anyDay := tomorrow
+	anyDay.Name = "Thunderstorms"
+	anyDay.Anchor, anyDay.After, anyDay.Before = "", 0, 0
+
+	// Evaluated mid-afternoon, today is still in the open-ended window.
+	got, err := Evaluate(wd, []Rule{tomorrow, anyDay}, time.Date(2025, 1, 1, 15, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	want := []Event{
+		{
+			Rule:        "Thunderstorms tomorrow",
+			Severity:    SeverityModerate,
+			Metric:      openmeteogo.WeatherCode,
+			Start:       "2025-01-02",
+			End:         "2025-01-02",
+			Values:      []float64{96},
+			Peak:        96,
+			Unit:        openmeteogo.UnitWMOCode,
+			Description: "Thunderstorm with slight hail",
+		},
+		{
+			Rule:        "Thunderstorms",
+			Severity:    SeverityModerate,
+			Metric:      openmeteogo.WeatherCode,
+			Start:       "2025-01-01",
+			End:         "2025-01-02",
+			Values:      []float64{95, 96},
+			Peak:        96,
+			Unit:        openmeteogo.UnitWMOCode,
+			Description: "Thunderstorm with slight hail",
+		},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestEvaluate_Errors(t *testing.T) {
+	wd := hourly(nil, nil)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := map[string]Rule{
+		"invalid rule":       {Name: "x", Severity: SeverityMinor},
+		"metric not in data": {Name: "x", Severity: SeverityMinor, Metric: openmeteogo.Snowfall, Op: ">", Value: 1},
+		"incompatible unit":  {Name: "x", Severity: SeverityMinor, Metric: openmeteogo.WindGusts10m, Op: ">", Value: 1, Unit: openmeteogo.UnitCelsius},
+	}
+
+	for name, rule := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Evaluate(wd, []Rule{rule}, now)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParseRules(t *testing.T) {
+	yamlRules := `
+- name: Damaging gusts
+  severity: severe
+  metric: wind_gusts_10m
+  op: ">"
+  value: 80
+  unit: km/h
+  consecutive: 2
+  before: 24h
+- name: Thunderstorms tomorrow
+  severity: moderate
+  section: daily
+  weather_groups: [thunderstorm]
+  anchor: today
+  after: 24h
+  before: 48h
+`
+	jsonRules := `[
+  {"name": "Damaging gusts", "severity": "severe", "metric": "wind_gusts_10m", "op": ">", "value": 80, "unit": "km/h", "consecutive": 2, "before": "24h"},
+  {"name": "Thunderstorms tomorrow", "severity": "moderate", "section": "daily", "weather_groups": ["thunderstorm"], "anchor": "today", "after": "24h", "before": "48h"}
+]`
+	want := []Rule{
+		{
+			Name:        "Damaging gusts",
+			Severity:    SeveritySevere,
+			Metric:      openmeteogo.WindGusts10m,
+			Op:          ">",
+			Value:       80,
+			Unit:        openmeteogo.UnitKmh,
+			Consecutive: 2,
+			Before:      Duration(24 * time.Hour),
+		},
+		{
+			Name:          "Thunderstorms tomorrow",
+			Severity:      SeverityModerate,
+			Section:       "daily",
+			WeatherGroups: []openmeteogo.WeatherGroup{openmeteogo.GroupThunderstorm},
+			Anchor:        AnchorToday,
+			After:         Duration(24 * time.Hour),
+			Before:        Duration(48 * time.Hour),
+		},
+	}
+
+	for name, data := range map[string]string{"yaml": yamlRules, "json": jsonRules} {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseRules([]byte(data))
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestParseRules_Errors(t *testing.T) {
+	tests := map[string]string{
+		"not a list":     `name: x`,
+		"bad duration":   `[{name: x, severity: minor, metric: rain, op: ">", before: tomorrow}]`,
+		"no name":        `[{severity: minor, metric: rain, op: ">"}]`,
+		"bad severity":   `[{name: x, severity: dire, metric: rain, op: ">"}]`,
+		"bad section":    `[{name: x, severity: minor, section: weekly, metric: rain, op: ">"}]`,
+		"bad anchor":     `[{name: x, severity: minor, anchor: dawn, metric: rain, op: ">"}]`,
+		"empty window":   `[{name: x, severity: minor, metric: rain, op: ">", after: 24h, before: 12h}]`,
+		"negative count": `[{name: x, severity: minor, metric: rain, op: ">", consecutive: -1}]`,
+		"no metric":      `[{name: x, severity: minor, op: ">"}]`,
+		"unsupported op": `[{name: x, severity: minor, metric: rain, op: "=>"}]`,
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseRules([]byte(data))
+			assert.Error(t, err)
+		})
+	}
+}
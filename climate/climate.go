@@ -0,0 +1,320 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package climate computes climatological normals, percentiles and records
+// from years of Open-Meteo archive data, and scores forecasts against them.
+package climate
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/tpryan/openmeteogo"
+)
+
+// Period selects how days are grouped into normals.
+type Period int
+
+const (
+	// ByDayOfYear computes normals for each calendar day.
+	ByDayOfYear Period = iota
+	// ByMonth computes normals for each calendar month.
+	ByMonth
+)
+
+// key returns the group a date belongs to: "MM-DD" or "MM".
+func (p Period) key(t time.Time) string {
+	if p == ByMonth {
+		return t.Format("01")
+	}
+	return t.Format("01-02")
+}
+
+// Config describes the climatology to compute.
+type Config struct {
+	// Metrics are the daily metrics to compute normals for, e.g.
+	// Temperature2mMax.
+	Metrics openmeteogo.Metrics
+	// Period groups days by calendar day or month. The default is
+	// ByDayOfYear.
+	Period Period
+	// Window widens each calendar day to include the days up to Window days
+	// either side, which smooths day-of-year normals computed from few
+	// years. It is ignored for ByMonth.
+	Window int
+}
+
+// Stats summarises the historical values for one metric in one period.
+type Stats struct {
+	// Count is the number of historical values.
+	Count int
+	// Mean is the average of the values, the climatological normal.
+	Mean float64
+	// Min and Max are the record low and high.
+	Min, Max float64
+	// MinDate and MaxDate are the dates of the records.
+	MinDate, MaxDate string
+
+	sorted []float64
+}
+
+// Percentile returns the p-th percentile of the historical values, with p
+// between 0 and 100. Values between ranks are interpolated linearly.
+func (s *Stats) Percentile(p float64) float64 {
+	if len(s.sorted) == 0 {
+		return math.NaN()
+	}
+	rank := math.Max(0, math.Min(100, p)) / 100 * float64(len(s.sorted)-1)
+	lo, hi := int(math.Floor(rank)), int(math.Ceil(rank))
+	return s.sorted[lo] + (s.sorted[hi]-s.sorted[lo])*(rank-float64(lo))
+}
+
+// Rank returns the percentile rank of v among the historical values: the
+// percentage of values below it, counting equal values as half.
+func (s *Stats) Rank(v float64) float64 {
+	if len(s.sorted) == 0 {
+		return math.NaN()
+	}
+	below := sort.SearchFloat64s(s.sorted, v)
+	equal := sort.Search(len(s.sorted), func(i int) bool { return s.sorted[i] > v }) - below
+	return (float64(below) + float64(equal)/2) / float64(len(s.sorted)) * 100
+}
+
+// Climatology holds normals for a set of daily metrics.
+type Climatology struct {
+	// Period is how days are grouped.
+	Period Period
+	// Start and End are the first and last dates of the historical data.
+	Start, End string
+	// Units holds the unit of each metric.
+	Units map[openmeteogo.Metric]openmeteogo.Unit
+
+	stats map[openmeteogo.Metric]map[string]*Stats
+}
+
+// New computes a climatology from daily historical data in wd.
+func New(wd *openmeteogo.WeatherData, cfg Config) (*Climatology, error) {
+	if len(cfg.Metrics) == 0 {
+		return nil, fmt.Errorf("no metrics")
+	}
+	if len(wd.Daily.Time) == 0 {
+		return nil, fmt.Errorf("no daily data")
+	}
+	var err error
+	dates := make([]time.Time, len(wd.Daily.Time))
+	for i, ts := range wd.Daily.Time {
+		if dates[i], err = time.Parse(openmeteogo.DateFormat, ts); err != nil {
+			return nil, fmt.Errorf("parsing date %q: %w", ts, err)
+		}
+	}
+	window := cfg.Window
+	if cfg.Period == ByMonth {
+		window = 0
+	}
+
+	c := &Climatology{
+		Period: cfg.Period,
+		Start:  wd.Daily.Time[0],
+		End:    wd.Daily.Time[len(wd.Daily.Time)-1],
+		Units:  map[openmeteogo.Metric]openmeteogo.Unit{},
+		stats:  map[openmeteogo.Metric]map[string]*Stats{},
+	}
+	for _, m := range cfg.Metrics {
+		unit, err := wd.DailyUnits.Unit(m)
+		if err != nil {
+			return nil, err
+		}
+		values, err := wd.DailyValues(m, unit)
+		if err != nil {
+			return nil, err
+		}
+		c.Units[m] = unit
+
+		groups := map[string]*Stats{}
+		for i, v := range values {
+			if math.IsNaN(v) {
+				continue
+			}
+			for d := -window; d <= window; d++ {
+				key := cfg.Period.key(dates[i].AddDate(0, 0, d))
+				s, ok := groups[key]
+				if !ok {
+					s = &Stats{Min: math.Inf(1), Max: math.Inf(-1)}
+					groups[key] = s
+				}
+				s.sorted = append(s.sorted, v)
+				s.Mean += v
+				if v < s.Min {
+					s.Min, s.MinDate = v, wd.Daily.Time[i]
+				}
+				if v > s.Max {
+					s.Max, s.MaxDate = v, wd.Daily.Time[i]
+				}
+			}
+		}
+		for _, s := range groups {
+			s.Count = len(s.sorted)
+			s.Mean /= float64(s.Count)
+			sort.Float64s(s.sorted)
+		}
+		c.stats[m] = groups
+	}
+	return c, nil
+}
+
+// Fetch fetches the given number of complete calendar years of daily data
+// from the archive API, ending with last year, and computes a climatology.
+// Latitude, Longitude, Timezone and unit options in o are used; o itself
+// is not modified.
+func Fetch(c *openmeteogo.Client, o *openmeteogo.Options, years int, cfg Config) (*Climatology, error) {
+	if years < 1 {
+		return nil, fmt.Errorf("years must be at least 1, got %d", years)
+	}
+	last := time.Now().Year() - 1
+	ao := openmeteogo.Options{
+		Latitude:          o.Latitude,
+		Longitude:         o.Longitude,
+		Timezone:          o.Timezone,
+		TemperatureUnit:   o.TemperatureUnit,
+		WindspeedUnit:     o.WindspeedUnit,
+		PrecipitationUnit: o.PrecipitationUnit,
+		Start:             time.Date(last-years+1, time.January, 1, 0, 0, 0, 0, time.UTC),
+		End:               time.Date(last, time.December, 31, 0, 0, 0, 0, time.UTC),
+		DailyMetrics:      cfg.Metrics,
+	}
+	wd, err := c.Get(&ao)
+	if err != nil {
+		return nil, fmt.Errorf("fetching archive: %w", err)
+	}
+	return New(wd, cfg)
+}
+
+// Stats returns the statistics for a metric on the calendar day or month
+// of date, formatted as YYYY-MM-DD.
+func (c *Climatology) Stats(m openmeteogo.Metric, date string) (*Stats, error) {
+	groups, ok := c.stats[m]
+	if !ok {
+		return nil, fmt.Errorf("no climatology for metric: %s", m)
+	}
+	t, err := time.Parse(openmeteogo.DateFormat, date)
+	if err != nil {
+		return nil, fmt.Errorf("parsing date %q: %w", date, err)
+	}
+	s, ok := groups[c.Period.key(t)]
+	if !ok {
+		return nil, fmt.Errorf("%s: no historical data for %s", m, date)
+	}
+	return s, nil
+}
+
+// Score compares one forecast value with the climatology.
+type Score struct {
+	// Metric is the metric scored.
+	Metric openmeteogo.Metric
+	// Time is the forecast date.
+	Time string
+	// Unit is the unit of Value, Normal and Anomaly.
+	Unit openmeteogo.Unit
+	// Value is the forecast value.
+	Value float64
+	// Normal is the climatological mean.
+	Normal float64
+	// Anomaly is Value minus Normal.
+	Anomaly float64
+	// Percentile is the percentile rank of Value among historical values.
+	Percentile float64
+	// RecordHigh and RecordLow report whether Value beats the historical
+	// maximum or minimum.
+	RecordHigh, RecordLow bool
+}
+
+// String describes the score, e.g. "12.0°C above normal, 98th percentile".
+func (s Score) String() string {
+	direction := "above"
+	if s.Anomaly < 0 {
+		direction = "below"
+	}
+	desc := fmt.Sprintf("%.1f%s %s normal, %s percentile", math.Abs(s.Anomaly), s.Unit, direction, ordinal(int(math.Round(s.Percentile))))
+	switch {
+	case s.RecordHigh:
+		desc += ", record high"
+	case s.RecordLow:
+		desc += ", record low"
+	}
+	return desc
+}
+
+// ordinal formats n as 1st, 2nd, 3rd, 4th and so on.
+func ordinal(n int) string {
+	suffix := "th"
+	switch n % 10 {
+	case 1:
+		suffix = "st"
+	case 2:
+		suffix = "nd"
+	case 3:
+		suffix = "rd"
+	}
+	if n%100 >= 11 && n%100 <= 13 {
+		suffix = "th"
+	}
+	return fmt.Sprintf("%d%s", n, suffix)
+}
+
+// Score scores every day of the daily forecast in wd, for each metric of
+// the climatology present in it. Forecast values are converted to the
+// climatology's units. Scores are ordered by metric, then date.
+func (c *Climatology) Score(wd *openmeteogo.WeatherData) ([]Score, error) {
+	metrics := make([]openmeteogo.Metric, 0, len(c.stats))
+	for m := range c.stats {
+		metrics = append(metrics, m)
+	}
+	slices.Sort(metrics)
+
+	var scores []Score
+	for _, m := range metrics {
+		if _, err := wd.Daily.Values(m); err != nil {
+			continue
+		}
+		values, err := wd.DailyValues(m, c.Units[m])
+		if err != nil {
+			return nil, err
+		}
+		for i, date := range wd.Daily.Time {
+			s, err := c.Stats(m, date)
+			if err != nil {
+				return nil, err
+			}
+			v := values[i]
+			scores = append(scores, Score{
+				Metric:     m,
+				Time:       date,
+				Unit:       c.Units[m],
+				Value:      v,
+				Normal:     s.Mean,
+				Anomaly:    v - s.Mean,
+				Percentile: s.Rank(v),
+				RecordHigh: v > s.Max,
+				RecordLow:  v < s.Min,
+			})
+		}
+	}
+	if len(scores) == 0 {
+		return nil, fmt.Errorf("forecast has none of the climatology's metrics")
+	}
+	return scores, nil
+}
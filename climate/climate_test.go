@@ -0,0 +1,201 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package climate
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tpryan/openmeteogo"
+)
+
+// archive returns daily data from start to end in which the maximum
+// temperature is 20°C plus the number of years since 2015, and the
+// precipitation is the day of the month in mm.
+func archive(start, end time.Time) *openmeteogo.WeatherData {
+	wd := &openmeteogo.WeatherData{
+		DailyUnits: openmeteogo.DailyUnits{Time: "iso8601", Temperature2mMax: "°C", PrecipitationSum: "mm"},
+	}
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		wd.Daily.Time = append(wd.Daily.Time, d.Format(openmeteogo.DateFormat))
+		wd.Daily.Temperature2mMax = append(wd.Daily.Temperature2mMax, 20+float64(d.Year()-2015))
+		wd.Daily.PrecipitationSum = append(wd.Daily.PrecipitationSum, float64(d.Day()))
+	}
+	return wd
+}
+
+func tenYears() *openmeteogo.WeatherData {
+	return archive(time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC))
+}
+
+func TestNew(t *testing.T) {
+	c, err := New(tenYears(), Config{Metrics: openmeteogo.Metrics{openmeteogo.Temperature2mMax, openmeteogo.PrecipitationSum}})
+	require.NoError(t, err)
+	assert.Equal(t, "2015-01-01", c.Start)
+	assert.Equal(t, "2024-12-31", c.End)
+	assert.Equal(t, openmeteogo.UnitCelsius, c.Units[openmeteogo.Temperature2mMax])
+
+	s, err := c.Stats(openmeteogo.Temperature2mMax, "2026-07-14")
+	require.NoError(t, err)
+	assert.Equal(t, 10, s.Count)
+	assert.InDelta(t, 24.5, s.Mean, 1e-9)
+	assert.Equal(t, 20.0, s.Min)
+	assert.Equal(t, "2015-07-14", s.MinDate)
+	assert.Equal(t, 29.0, s.Max)
+	assert.Equal(t, "2024-07-14", s.MaxDate)
+	assert.InDelta(t, 28.1, s.Percentile(90), 1e-9)
+	assert.InDelta(t, 24.5, s.Percentile(50), 1e-9)
+
+	leap, err := c.Stats(openmeteogo.Temperature2mMax, "2028-02-29")
+	require.NoError(t, err)
+	assert.Equal(t, 3, leap.Count, "2016, 2020 and 2024")
+
+	_, err = c.Stats(openmeteogo.RainSum, "2026-07-14")
+	assert.Error(t, err)
+}
+
+func TestNew_ByMonthAndWindow(t *testing.T) {
+	monthly, err := New(tenYears(), Config{Metrics: openmeteogo.Metrics{openmeteogo.PrecipitationSum}, Period: ByMonth})
+	require.NoError(t, err)
+	s, err := monthly.Stats(openmeteogo.PrecipitationSum, "2026-04-20")
+	require.NoError(t, err)
+	assert.Equal(t, 300, s.Count)
+	assert.InDelta(t, 15.5, s.Mean, 1e-9)
+
+	windowed, err := New(tenYears(), Config{Metrics: openmeteogo.Metrics{openmeteogo.PrecipitationSum}, Window: 2})
+	require.NoError(t, err)
+	s, err = windowed.Stats(openmeteogo.PrecipitationSum, "2026-04-10")
+	require.NoError(t, err)
+	assert.Equal(t, 50, s.Count)
+	assert.InDelta(t, 10, s.Mean, 1e-9)
+}
+
+func TestNew_Errors(t *testing.T) {
+	_, err := New(tenYears(), Config{})
+	assert.Error(t, err, "no metrics")
+	_, err = New(&openmeteogo.WeatherData{}, Config{Metrics: openmeteogo.Metrics{openmeteogo.Temperature2mMax}})
+	assert.Error(t, err, "no data")
+	_, err = New(tenYears(), Config{Metrics: openmeteogo.Metrics{openmeteogo.RainSum}})
+	assert.Error(t, err, "metric not returned")
+}
+
+func TestStats_Rank(t *testing.T) {
+	s := &Stats{sorted: []float64{1, 2, 2, 3}}
+	assert.Equal(t, 0.0, s.Rank(0))
+	assert.Equal(t, 50.0, s.Rank(2))
+	assert.Equal(t, 87.5, s.Rank(3))
+	assert.Equal(t, 100.0, s.Rank(4))
+}
+
+func TestScore(t *testing.T) {
+	c, err := New(tenYears(), Config{Metrics: openmeteogo.Metrics{openmeteogo.Temperature2mMax}})
+	require.NoError(t, err)
+
+	forecast := &openmeteogo.WeatherData{
+		DailyUnits: openmeteogo.DailyUnits{Temperature2mMax: "°F"},
+		Daily: openmeteogo.Daily{
+			Time:             []string{"2026-07-14", "2026-07-15", "2026-07-16"},
+			Temperature2mMax: []float64{98.6, 75.2, 50},
+		},
+	}
+	got, err := c.Score(forecast)
+	require.NoError(t, err)
+	require.Len(t, got, 3)
+
+	assert.Equal(t, openmeteogo.UnitCelsius, got[0].Unit)
+	assert.InDelta(t, 37, got[0].Value, 1e-9)
+	assert.InDelta(t, 12.5, got[0].Anomaly, 1e-9)
+	assert.Equal(t, 100.0, got[0].Percentile)
+	assert.True(t, got[0].RecordHigh)
+	assert.Equal(t, "12.5°C above normal, 100th percentile, record high", got[0].String())
+
+	assert.InDelta(t, 24, got[1].Value, 1e-9)
+	assert.Equal(t, "0.5°C below normal, 45th percentile", got[1].String())
+
+	assert.True(t, got[2].RecordLow)
+
+	_, err = c.Score(&openmeteogo.WeatherData{})
+	assert.Error(t, err)
+}
+
+func TestScore_UnrelatedUnit(t *testing.T) {
+	archive := tenYears()
+	archive.DailyUnits.WindSpeed10mMax = "furlong/fortnight"
+	c, err := New(archive, Config{Metrics: openmeteogo.Metrics{openmeteogo.Temperature2mMax}})
+	require.NoError(t, err, "only the label of temperature_2m_max is parsed")
+
+	forecast := &openmeteogo.WeatherData{
+		DailyUnits: openmeteogo.DailyUnits{Temperature2mMax: "°C", WindSpeed10mMax: "furlong/fortnight"},
+		Daily:      openmeteogo.Daily{Time: []string{"2026-07-14"}, Temperature2mMax: []float64{30}},
+	}
+	got, err := c.Score(forecast)
+	require.NoError(t, err)
+	assert.Len(t, got, 1)
+}
+
+func TestOrdinal(t *testing.T) {
+	for n, want := range map[int]string{1: "1st", 2: "2nd", 3: "3rd", 4: "4th", 11: "11th", 12: "12th", 13: "13th", 21: "21st", 98: "98th", 100: "100th"} {
+		assert.Equal(t, want, ordinal(n))
+	}
+}
+
+func TestFetch(t *testing.T) {
+	var requested *url.URL
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		requested = req.URL
+		start, _ := time.Parse(openmeteogo.DateFormat, req.URL.Query().Get("start_date"))
+		end, _ := time.Parse(openmeteogo.DateFormat, req.URL.Query().Get("end_date"))
+		rw.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(rw).Encode(archive(start, end))
+	}))
+	defer server.Close()
+
+	target, _ := url.Parse(server.URL)
+	client := openmeteogo.NewClient()
+	client.HTTPClient = &http.Client{Transport: rewriteTransport{target}}
+
+	opts := openmeteogo.NewOptionsBuilder().Latitude(48.85).Longitude(2.35).Build()
+	c, err := Fetch(client, opts, 3, Config{Metrics: openmeteogo.Metrics{openmeteogo.Temperature2mMax}})
+	require.NoError(t, err)
+
+	last := time.Now().Year() - 1
+	assert.Equal(t, "/v1/archive", requested.Path)
+	assert.Equal(t, fmt.Sprintf("%d-01-01", last-2), requested.Query().Get("start_date"))
+	assert.Equal(t, fmt.Sprintf("%d-12-31", last), requested.Query().Get("end_date"))
+	assert.Equal(t, "temperature_2m_max", requested.Query().Get("daily"))
+	assert.Equal(t, fmt.Sprintf("%d-01-01", last-2), c.Start)
+
+	_, err = Fetch(client, opts, 0, Config{Metrics: openmeteogo.Metrics{openmeteogo.Temperature2mMax}})
+	assert.Error(t, err)
+}
+
+// rewriteTransport sends every request to a test server.
+type rewriteTransport struct {
+	target *url.URL
+}
+
+func (rt rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r := req.Clone(req.Context())
+	r.URL.Scheme = rt.target.Scheme
+	r.URL.Host = rt.target.Host
+	return http.DefaultTransport.RoundTrip(r)
+}
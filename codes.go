@@ -55,3 +55,37 @@ func DescribeCode(code int) string {
 	}
 	return "Unknown code"
 }
+
+// WeatherGroup is a broad category of WMO weather codes, such as rain or
+// thunderstorm.
+type WeatherGroup string
+
+const (
+	GroupClear        WeatherGroup = "clear"
+	GroupCloudy       WeatherGroup = "cloudy"
+	GroupFog          WeatherGroup = "fog"
+	GroupDrizzle      WeatherGroup = "drizzle"
+	GroupRain         WeatherGroup = "rain"
+	GroupFreezingRain WeatherGroup = "freezing_rain"
+	GroupSnow         WeatherGroup = "snow"
+	GroupThunderstorm WeatherGroup = "thunderstorm"
+)
+
+// weatherGroups maps each code in WeatherCodeMap to its group. Freezing
+// drizzle counts as freezing rain, and showers as rain or snow.
+var weatherGroups = map[int]WeatherGroup{
+	0: GroupClear, 1: GroupClear,
+	2: GroupCloudy, 3: GroupCloudy,
+	45: GroupFog, 48: GroupFog,
+	51: GroupDrizzle, 53: GroupDrizzle, 55: GroupDrizzle,
+	56: GroupFreezingRain, 57: GroupFreezingRain, 66: GroupFreezingRain, 67: GroupFreezingRain,
+	61: GroupRain, 63: GroupRain, 65: GroupRain, 80: GroupRain, 81: GroupRain, 82: GroupRain,
+	71: GroupSnow, 73: GroupSnow, 75: GroupSnow, 77: GroupSnow, 85: GroupSnow, 86: GroupSnow,
+	95: GroupThunderstorm, 96: GroupThunderstorm, 99: GroupThunderstorm,
+}
+
+// CodeGroup returns the group a weather code belongs to, or "" if the code
+// is not found.
+func CodeGroup(code int) WeatherGroup {
+	return weatherGroups[code]
+}
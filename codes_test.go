@@ -46,3 +46,28 @@ func TestGetWeatherCodeDescription(t *testing.T) {
 		})
 	}
 }
+
+func TestCodeGroup(t *testing.T) {
+	tests := map[string]struct {
+		code int
+		want WeatherGroup
+	}{
+		"mainly clear":     {code: 1, want: GroupClear},
+		"overcast":         {code: 3, want: GroupCloudy},
+		"rain showers":     {code: 81, want: GroupRain},
+		"freezing drizzle": {code: 56, want: GroupFreezingRain},
+		"snow grains":      {code: 77, want: GroupSnow},
+		"hail":             {code: 99, want: GroupThunderstorm},
+		"unknown":          {code: 100, want: ""},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, CodeGroup(tc.code))
+		})
+	}
+
+	for code := range WeatherCodeMap {
+		assert.NotEmpty(t, CodeGroup(code), "code %d has no group", code)
+	}
+}
@@ -0,0 +1,381 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openmeteogo
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Units describes the unit system WeatherData.ConvertTo converts into.
+// A zero value field leaves the corresponding quantities unchanged.
+type Units struct {
+	// Temperature is the target unit for temperature values.
+	Temperature TemperatureUnit
+	// WindSpeed is the target unit for wind speed and current velocity values.
+	WindSpeed WindSpeedUnit
+	// Precipitation is the target unit for precipitation values. It also
+	// selects cm or inch for snowfall and m or ft for snow depth, matching
+	// the API's own behaviour.
+	Precipitation PrecipitationUnit
+	// Length is the target unit system for wave heights and other lengths.
+	// If unset, lengths follow Precipitation.
+	Length LengthUnit
+	// Pressure is the target unit for atmospheric pressure values.
+	Pressure PressureUnit
+	// Visibility is the target unit for visibility values. If unset,
+	// visibility follows Precipitation.
+	Visibility VisibilityUnit
+}
+
+// quantity groups unit labels that can be converted into one another.
+type quantity int
+
+const (
+	noQuantity quantity = iota
+	temperatureQuantity
+	speedQuantity
+	precipitationQuantity
+	snowfallQuantity
+	snowDepthQuantity
+	lengthQuantity
+	visibilityQuantity
+	pressureQuantity
+)
+
+// classify returns the quantity measured by a field, from its JSON name and
+// the unit the API reported for it.
+func classify(field string, unit Unit) quantity {
+	switch unit.Dimension() {
+	case DimensionTemperature:
+		return temperatureQuantity
+	case DimensionSpeed:
+		return speedQuantity
+	case DimensionPressure:
+		// Vapour pressure deficit is not an atmospheric pressure.
+		if strings.HasPrefix(field, "vapour_pressure") {
+			return noQuantity
+		}
+		return pressureQuantity
+	case DimensionLength:
+		switch {
+		case field == "visibility":
+			return visibilityQuantity
+		case field == "snow_depth":
+			return snowDepthQuantity
+		case unit == UnitMillimetre:
+			return precipitationQuantity
+		case unit == UnitCentimetre:
+			return snowfallQuantity
+		case unit == UnitInch:
+			if strings.HasPrefix(field, "snowfall") {
+				return snowfallQuantity
+			}
+			return precipitationQuantity
+		}
+		return lengthQuantity
+	}
+	return noQuantity
+}
+
+// precipitationTargets maps a PrecipitationUnit to the unit used for each
+// quantity it governs.
+var precipitationTargets = map[PrecipitationUnit]map[quantity]Unit{
+	MM: {precipitationQuantity: UnitMillimetre, snowfallQuantity: UnitCentimetre, snowDepthQuantity: UnitMetre},
+	IN: {precipitationQuantity: UnitInch, snowfallQuantity: UnitInch, snowDepthQuantity: UnitFoot},
+}
+
+// target returns the unit a quantity converts to, or "" to leave it as is.
+func (u Units) target(q quantity) (Unit, error) {
+	switch q {
+	case temperatureQuantity:
+		switch u.Temperature {
+		case "":
+			return "", nil
+		case Celsius:
+			return UnitCelsius, nil
+		case Fahrenheit:
+			return UnitFahrenheit, nil
+		}
+		return "", fmt.Errorf("unsupported temperature unit: %s", u.Temperature)
+	case speedQuantity:
+		switch u.WindSpeed {
+		case "":
+			return "", nil
+		case KMH:
+			return UnitKmh, nil
+		case MS:
+			return UnitMs, nil
+		case MPH:
+			return UnitMph, nil
+		case KN:
+			return UnitKnots, nil
+		case BFT:
+			return UnitBeaufort, nil
+		}
+		return "", fmt.Errorf("unsupported wind speed unit: %s", u.WindSpeed)
+	case pressureQuantity:
+		switch u.Pressure {
+		case "":
+			return "", nil
+		case HPA, KPA, INHG:
+			return Unit(u.Pressure), nil
+		}
+		return "", fmt.Errorf("unsupported pressure unit: %s", u.Pressure)
+	case lengthQuantity:
+		switch u.Length {
+		case LengthMetric:
+			return UnitMetre, nil
+		case LengthImperial:
+			return UnitFoot, nil
+		case "":
+			return u.target(snowDepthQuantity)
+		}
+		return "", fmt.Errorf("unsupported length unit: %s", u.Length)
+	case visibilityQuantity:
+		switch u.Visibility {
+		case Meters, Kilometers, Feet, Miles:
+			return Unit(u.Visibility), nil
+		case "":
+			return u.target(snowDepthQuantity)
+		}
+		return "", fmt.Errorf("unsupported visibility unit: %s", u.Visibility)
+	case precipitationQuantity, snowfallQuantity, snowDepthQuantity:
+		if u.Precipitation == "" {
+			return "", nil
+		}
+		targets, ok := precipitationTargets[u.Precipitation]
+		if !ok {
+			return "", fmt.Errorf("unsupported precipitation unit: %s", u.Precipitation)
+		}
+		return targets[q], nil
+	}
+	return "", nil
+}
+
+// section pairs a *Units struct with the data struct it describes.
+type section struct {
+	units, data any
+}
+
+// hourlySections returns the hourly section and one section per pressure level.
+func hourlySections(units *HourlyUnits, data *Hourly) []section {
+	sections := []section{{units, data}}
+	for level, d := range data.PressureLevels {
+		if u, ok := units.PressureLevels[level]; ok {
+			sections = append(sections, section{u, d})
+		}
+	}
+	return sections
+}
+
+// ConvertTo converts every temperature, speed, precipitation, snowfall,
+// snow depth, pressure, visibility and wave height value in wd to the given
+// units and updates the matching *Units labels. The conversion is done
+// locally, so it also works on cached or stored responses. If any value
+// cannot be converted, wd is left unchanged.
+func (wd *WeatherData) ConvertTo(u Units) error {
+	sections := []section{
+		{&wd.CurrentUnits, &wd.Current},
+		{&wd.Minutely15Units, &wd.Minutely15},
+		{&wd.DailyUnits, &wd.Daily},
+		{&wd.WeeklyUnits, &wd.Weekly},
+		{&wd.MonthlyUnits, &wd.Monthly},
+	}
+	sections = append(sections, hourlySections(&wd.HourlyUnits, &wd.Hourly)...)
+
+	for _, md := range wd.ByModel {
+		sections = append(sections,
+			section{&md.CurrentUnits, &md.Current},
+			section{&md.Minutely15Units, &md.Minutely15},
+			section{&md.DailyUnits, &md.Daily},
+		)
+		sections = append(sections, hourlySections(&md.HourlyUnits, &md.Hourly)...)
+	}
+
+	// Convert everything before changing anything, so that an error leaves
+	// wd as it was.
+	var updates []func()
+	for _, s := range sections {
+		set, err := convertSection(s.units, s.data, u)
+		if err != nil {
+			return err
+		}
+		updates = append(updates, set...)
+	}
+	for _, set := range updates {
+		set()
+	}
+
+	return nil
+}
+
+// HourlyValues returns the hourly series for a metric, as Hourly.Values
+// does, converted to unit with Metric.Convert. It returns an error unless
+// there is one value per timestamp.
+func (wd *WeatherData) HourlyValues(m Metric, unit Unit) ([]float64, error) {
+	values, err := wd.Hourly.Values(m)
+	if err != nil {
+		return nil, err
+	}
+	from, err := wd.HourlyUnits.Unit(m)
+	if err != nil {
+		return nil, err
+	}
+	return convertSeries(m, values, from, unit, len(wd.Hourly.Time))
+}
+
+// DailyValues returns the daily series for a metric, as Daily.Values does,
+// converted to unit with Metric.Convert. It returns an error unless there
+// is one value per date.
+func (wd *WeatherData) DailyValues(m Metric, unit Unit) ([]float64, error) {
+	values, err := wd.Daily.Values(m)
+	if err != nil {
+		return nil, err
+	}
+	from, err := wd.DailyUnits.Unit(m)
+	if err != nil {
+		return nil, err
+	}
+	return convertSeries(m, values, from, unit, len(wd.Daily.Time))
+}
+
+// convertSeries converts values, which must have n elements, in place.
+func convertSeries(m Metric, values []float64, from, to Unit, n int) ([]float64, error) {
+	if len(values) != n {
+		return nil, fmt.Errorf("%s: need %d values, got %d", m, n, len(values))
+	}
+	for i, v := range values {
+		c, err := m.Convert(v, from, to)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", m, err)
+		}
+		values[i] = c
+	}
+	return values, nil
+}
+
+// convertSection converts the fields of data, a pointer to a section struct,
+// using the labels in units, a pointer to its *Units struct. Fields are
+// matched by their JSON names. Nothing is changed until the returned
+// functions are called, each of which sets a converted field and its label.
+func convertSection(units, data any, u Units) ([]func(), error) {
+	uv := reflect.ValueOf(units).Elem()
+	dv := reflect.ValueOf(data).Elem()
+
+	labels := map[string]reflect.Value{}
+	for i := 0; i < uv.NumField(); i++ {
+		if uv.Field(i).Kind() == reflect.String {
+			labels[jsonName(uv.Type().Field(i))] = uv.Field(i)
+		}
+	}
+
+	var updates []func()
+	for i := 0; i < dv.NumField(); i++ {
+		name := jsonName(dv.Type().Field(i))
+		label, ok := labels[name]
+		if !ok || label.String() == "" {
+			continue
+		}
+
+		field := dv.Field(i)
+		if field.Kind() != reflect.Float64 && field.Type() != reflect.TypeOf([]float64(nil)) {
+			continue
+		}
+
+		to, convert, err := u.conversion(name, label.String())
+		if err != nil {
+			return nil, err
+		}
+		if convert == nil {
+			continue
+		}
+
+		var converted reflect.Value
+		if field.Kind() == reflect.Float64 {
+			v, err := convert(field.Float())
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			converted = reflect.ValueOf(v)
+		} else {
+			values := make([]float64, field.Len())
+			for j := range values {
+				if values[j], err = convert(field.Index(j).Float()); err != nil {
+					return nil, fmt.Errorf("%s: %w", name, err)
+				}
+			}
+			converted = reflect.ValueOf(values)
+		}
+		updates = append(updates, func() {
+			field.Set(converted)
+			label.SetString(to.String())
+		})
+	}
+
+	return updates, nil
+}
+
+// conversion returns the unit to convert a field with the given JSON name
+// and unit label to, and the function that converts its values. convert is
+// nil if the field is left as is. Values are converted with Metric.Convert,
+// so anomalies get no offset. An unrecognised label is an error if u has a
+// target for the field's dimension, and is left as is otherwise.
+func (u Units) conversion(name, label string) (to Unit, convert func(float64) (float64, error), err error) {
+	from, err := ParseUnit(label)
+	if err != nil {
+		if !u.converts(name) {
+			return "", nil, nil
+		}
+		return "", nil, fmt.Errorf("%s: %w", name, err)
+	}
+	q := classify(name, from)
+	if q == noQuantity {
+		return "", nil, nil
+	}
+	to, err = u.target(q)
+	if err != nil || to == "" || to == from {
+		return "", nil, err
+	}
+	return to, func(v float64) (float64, error) { return Metric(name).Convert(v, from, to) }, nil
+}
+
+// converts reports whether u has a target unit for the dimension of the
+// field with the given JSON name, which may be a pressure level variable.
+func (u Units) converts(name string) bool {
+	dim, ok := Metric(name).Dimension()
+	if !ok {
+		dim = pressureDimensions[PressureVariable(name)]
+	}
+	switch dim {
+	case DimensionTemperature, DimensionTemperatureDifference:
+		return u.Temperature != ""
+	case DimensionSpeed:
+		return u.WindSpeed != ""
+	case DimensionPressure:
+		// Vapour pressure deficit is not an atmospheric pressure.
+		return u.Pressure != "" && !strings.HasPrefix(name, "vapour_pressure")
+	case DimensionLength:
+		return u.Precipitation != "" || u.Length != "" || u.Visibility != ""
+	}
+	return false
+}
+
+// jsonName returns the name a struct field is encoded under.
+func jsonName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	return name
+}
@@ -0,0 +1,216 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openmeteogo
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func loadWeatherData(t *testing.T, path string) *WeatherData {
+	t.Helper()
+	dat, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var wd WeatherData
+	require.NoError(t, json.Unmarshal(dat, &wd))
+	return &wd
+}
+
+func TestConvertTo(t *testing.T) {
+	wd := loadWeatherData(t, "test_data/all_params.json")
+	orig := loadWeatherData(t, "test_data/all_params.json")
+
+	err := wd.ConvertTo(Units{Temperature: Fahrenheit, WindSpeed: MPH, Precipitation: IN})
+	require.NoError(t, err)
+
+	assert.Equal(t, "°F", wd.CurrentUnits.Temperature2m)
+	assert.InDelta(t, orig.Current.Temperature2m*9/5+32, wd.Current.Temperature2m, 1e-9)
+	assert.Equal(t, "mp/h", wd.CurrentUnits.WindSpeed10m)
+	assert.InDelta(t, orig.Current.WindSpeed10m/1.609344, wd.Current.WindSpeed10m, 1e-9)
+
+	assert.Equal(t, "°F", wd.HourlyUnits.Temperature2m)
+	assert.InDelta(t, orig.Hourly.Temperature2m[5]*9/5+32, wd.Hourly.Temperature2m[5], 1e-9)
+	assert.Equal(t, "inch", wd.HourlyUnits.Precipitation)
+	assert.Equal(t, "inch", wd.HourlyUnits.Snowfall)
+	assert.Equal(t, "ft", wd.HourlyUnits.SnowDepth)
+	assert.Equal(t, "ft", wd.HourlyUnits.Visibility)
+	assert.InDelta(t, orig.Hourly.Visibility[0]/0.3048, wd.Hourly.Visibility[0], 1e-6)
+
+	assert.Equal(t, "inch", wd.DailyUnits.PrecipitationSum)
+	assert.Equal(t, "inch", wd.DailyUnits.SnowfallSum)
+
+	// Quantities without alternative units are left alone.
+	assert.Equal(t, "hPa", wd.HourlyUnits.PressureMsl)
+	assert.Equal(t, orig.Hourly.PressureMsl, wd.Hourly.PressureMsl)
+	assert.Equal(t, orig.Hourly.RelativeHumidity2m, wd.Hourly.RelativeHumidity2m)
+	assert.Equal(t, "iso8601", wd.DailyUnits.Sunrise)
+
+	// Converting back restores the original values.
+	err = wd.ConvertTo(Units{Temperature: Celsius, WindSpeed: KMH, Precipitation: MM})
+	require.NoError(t, err)
+	assert.Equal(t, orig.HourlyUnits, wd.HourlyUnits)
+	assert.Equal(t, orig.DailyUnits, wd.DailyUnits)
+	assert.InDeltaSlice(t, orig.Hourly.Temperature2m, wd.Hourly.Temperature2m, 1e-9)
+	assert.InDeltaSlice(t, orig.Hourly.WindSpeed10m, wd.Hourly.WindSpeed10m, 1e-9)
+	assert.InDeltaSlice(t, orig.Hourly.SnowDepth, wd.Hourly.SnowDepth, 1e-9)
+	assert.InDeltaSlice(t, orig.Daily.SnowfallSum, wd.Daily.SnowfallSum, 1e-9)
+}
+
+func TestConvertTo_Partial(t *testing.T) {
+	wd := loadWeatherData(t, "test_data/all_params.json")
+
+	require.NoError(t, wd.ConvertTo(Units{WindSpeed: KN}))
+	assert.Equal(t, "kn", wd.HourlyUnits.WindSpeed10m)
+	assert.Equal(t, "°C", wd.HourlyUnits.Temperature2m)
+	assert.Equal(t, "mm", wd.HourlyUnits.Precipitation)
+}
+
+func TestConvertTo_PressureLevelsAndModels(t *testing.T) {
+	wd := &WeatherData{
+		HourlyUnits: HourlyUnits{
+			PressureLevels: map[int]*PressureLevelUnits{850: {Temperature: "°C", WindSpeed: "km/h"}},
+		},
+		Hourly: Hourly{
+			PressureLevels: map[int]*PressureLevel{850: {Temperature: []float64{0, 10}, WindSpeed: []float64{36}}},
+		},
+		ByModel: map[string]*ModelData{
+			"gfs_seamless": {
+				HourlyUnits: HourlyUnits{Temperature2m: "°C"},
+				Hourly:      Hourly{Temperature2m: []float64{100}},
+			},
+		},
+	}
+
+	require.NoError(t, wd.ConvertTo(Units{Temperature: Fahrenheit, WindSpeed: MS}))
+	assert.Equal(t, []float64{32, 50}, wd.Hourly.PressureLevels[850].Temperature)
+	assert.InDeltaSlice(t, []float64{10}, wd.Hourly.PressureLevels[850].WindSpeed, 1e-9)
+	assert.Equal(t, "m/s", wd.HourlyUnits.PressureLevels[850].WindSpeed)
+	assert.Equal(t, []float64{212}, wd.ByModel["gfs_seamless"].Hourly.Temperature2m)
+	assert.Equal(t, "°F", wd.ByModel["gfs_seamless"].HourlyUnits.Temperature2m)
+}
+
+func TestConvertTo_Anomalies(t *testing.T) {
+	wd := &WeatherData{
+		WeeklyUnits: WeeklyUnits{Temperature2mMean: "°C", Temperature2mAnomaly: "°C", PrecipitationAnomaly: "mm"},
+		Weekly: Weekly{
+			Temperature2mMean:    []float64{2},
+			Temperature2mAnomaly: []float64{2, -1},
+			PrecipitationAnomaly: []float64{25.4},
+		},
+	}
+
+	require.NoError(t, wd.ConvertTo(Units{Temperature: Fahrenheit, Precipitation: IN}))
+	assert.InDeltaSlice(t, []float64{35.6}, wd.Weekly.Temperature2mMean, 1e-9)
+	assert.InDeltaSlice(t, []float64{3.6, -1.8}, wd.Weekly.Temperature2mAnomaly, 1e-9, "anomalies are converted without the offset")
+	assert.Equal(t, "°F", wd.WeeklyUnits.Temperature2mAnomaly)
+	assert.InDeltaSlice(t, []float64{1}, wd.Weekly.PrecipitationAnomaly, 1e-9)
+
+	require.NoError(t, wd.ConvertTo(Units{Temperature: Celsius}))
+	assert.InDeltaSlice(t, []float64{2, -1}, wd.Weekly.Temperature2mAnomaly, 1e-9)
+}
+
+func TestConvertTo_InvalidUnit(t *testing.T) {
+	wd := loadWeatherData(t, "test_data/all_params.json")
+	assert.Error(t, wd.ConvertTo(Units{Temperature: "kelvin"}))
+}
+
+func TestConvertTo_UnrecognisedUnit(t *testing.T) {
+	wd := &WeatherData{
+		HourlyUnits: HourlyUnits{Temperature2m: "K"},
+		Hourly:      Hourly{Temperature2m: []float64{273.15}},
+	}
+	err := wd.ConvertTo(Units{Temperature: Fahrenheit})
+	assert.EqualError(t, err, `temperature_2m: unrecognised unit: "K"`)
+	assert.Equal(t, []float64{273.15}, wd.Hourly.Temperature2m)
+}
+
+func TestConvertTo_Atomic(t *testing.T) {
+	data := func() *WeatherData {
+		return &WeatherData{
+			CurrentUnits: CurrentUnits{Temperature2m: "°C", WindSpeed10m: "km/h"},
+			Current:      Current{Temperature2m: 10, WindSpeed10m: 36},
+			HourlyUnits:  HourlyUnits{Temperature2m: "°C", WindSpeed10m: "furlong/fortnight"},
+			Hourly:       Hourly{Temperature2m: []float64{10}, WindSpeed10m: []float64{1}},
+		}
+	}
+
+	wd := data()
+	err := wd.ConvertTo(Units{Temperature: Fahrenheit, WindSpeed: MS})
+	assert.EqualError(t, err, `wind_speed_10m: unrecognised unit: "furlong/fortnight"`)
+	assert.Equal(t, data(), wd, "nothing is converted")
+
+	wd = data()
+	require.NoError(t, wd.ConvertTo(Units{Temperature: Fahrenheit}), "wind speed is not being converted")
+	assert.Equal(t, 50.0, wd.Current.Temperature2m)
+	assert.Equal(t, []float64{50}, wd.Hourly.Temperature2m)
+	assert.Equal(t, "°F", wd.HourlyUnits.Temperature2m)
+	assert.Equal(t, "furlong/fortnight", wd.HourlyUnits.WindSpeed10m)
+	assert.Equal(t, []float64{1}, wd.Hourly.WindSpeed10m)
+}
+
+func TestWeatherData_Values(t *testing.T) {
+	wd := &WeatherData{
+		HourlyUnits: HourlyUnits{Temperature2m: "°C", WindSpeed10m: "furlong/fortnight"},
+		Hourly:      Hourly{Time: hourlyRange("2025-01-01T00:00", 2), Temperature2m: []float64{0, 100}},
+		DailyUnits:  DailyUnits{Temperature2mMax: "°F", PrecipitationSum: "mm"},
+		Daily:       Daily{Time: []string{"2025-01-01"}, Temperature2mMax: []float64{212}, PrecipitationSum: []float64{1, 2}},
+	}
+
+	got, err := wd.HourlyValues(Temperature2m, UnitFahrenheit)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{32, 212}, got)
+	assert.Equal(t, []float64{0, 100}, wd.Hourly.Temperature2m, "the data is not modified")
+
+	got, err = wd.DailyValues(Temperature2mMax, UnitCelsius)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{100}, got)
+
+	_, err = wd.HourlyValues(Rain, UnitMillimetre)
+	assert.EqualError(t, err, "no data for metric: rain")
+	_, err = wd.DailyValues(PrecipitationSum, UnitInch)
+	assert.EqualError(t, err, "precipitation_sum: need 1 values, got 2")
+	_, err = wd.DailyValues(Temperature2mMax, UnitKmh)
+	assert.EqualError(t, err, `temperature_2m_max: cannot convert "°F" to "km/h"`)
+}
+
+func TestConvertTo_AdditionalUnits(t *testing.T) {
+	wd := loadWeatherData(t, "test_data/all_params.json")
+	orig := loadWeatherData(t, "test_data/all_params.json")
+	wd.HourlyUnits.WaveHeight = "m"
+	wd.Hourly.WaveHeight = []float64{3.048}
+
+	err := wd.ConvertTo(Units{WindSpeed: BFT, Pressure: INHG, Visibility: Miles, Length: LengthImperial})
+	require.NoError(t, err)
+
+	assert.Equal(t, "bft", wd.HourlyUnits.WindSpeed10m)
+	for _, force := range wd.Hourly.WindSpeed10m {
+		assert.Equal(t, float64(int(force)), force, "Beaufort forces are whole numbers")
+	}
+	assert.Equal(t, "inHg", wd.HourlyUnits.PressureMsl)
+	assert.InDelta(t, orig.Hourly.PressureMsl[0]*100/3386.389, wd.Hourly.PressureMsl[0], 1e-9)
+	assert.Equal(t, "mi", wd.HourlyUnits.Visibility)
+	assert.InDelta(t, orig.Hourly.Visibility[0]/1609.344, wd.Hourly.Visibility[0], 1e-9)
+	assert.Equal(t, "ft", wd.HourlyUnits.WaveHeight)
+	assert.InDelta(t, 10, wd.Hourly.WaveHeight[0], 1e-9)
+
+	// Vapour pressure deficit and untouched quantities keep their units.
+	assert.Equal(t, "kPa", wd.HourlyUnits.VapourPressureDeficit)
+	assert.Equal(t, "m", wd.HourlyUnits.SnowDepth)
+	assert.Equal(t, "mm", wd.HourlyUnits.Precipitation)
+}
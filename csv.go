@@ -0,0 +1,619 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openmeteogo
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CSVSections are the sections of WeatherData written as CSV blocks, in the
+// order they are written.
+var CSVSections = []string{"current", "minutely_15", "hourly", "daily", "weekly", "monthly"}
+
+// csvLocation are the columns of the location block, as in the API's own
+// CSV format.
+var csvLocation = []string{"latitude", "longitude", "elevation", "utc_offset_seconds", "timezone", "timezone_abbreviation"}
+
+// csvColumn is one column of a CSV block.
+type csvColumn struct {
+	name   string
+	unit   string
+	values []string
+}
+
+// csvBlock is one section of WeatherData as CSV. Its first column is the
+// time.
+type csvBlock struct {
+	section string
+	columns []csvColumn
+	line    int
+}
+
+// WriteCSV writes wd as CSV: a block with the location, then a block for
+// each section of CSVSections that has data, separated by blank lines. Each
+// section block starts with a row naming the section, followed by a header
+// row of metric names, a row of their units and a row per timestamp.
+//
+//	latitude,longitude,elevation,utc_offset_seconds,timezone,timezone_abbreviation
+//	52.52,13.419998,38,3600,Europe/Berlin,GMT+1
+//
+//	hourly
+//	time,temperature_2m,precipitation
+//	iso8601,°C,mm
+//	2025-01-01T00:00,1.5,0
+//
+// If o is not nil, only the metrics it requested are written, in request
+// order. When it requested several models, each metric has a column per
+// model, named as in the API, e.g. temperature_2m_gfs_seamless. If o is nil,
+// every metric with data is written. Missing values are empty cells.
+func WriteCSV(w io.Writer, wd *WeatherData, o *Options) error {
+	blocks, err := csvBlocks(wd, o)
+	if err != nil {
+		return err
+	}
+	return writeCSVBlocks(w, wd, blocks)
+}
+
+// WriteCSVSection writes the location and a single section of wd as CSV, in
+// the layout of WriteCSV, such as to keep each section in a file of its own.
+// section is one of CSVSections. It returns an error if the section has no
+// data.
+func WriteCSVSection(w io.Writer, wd *WeatherData, section string, o *Options) error {
+	if !slices.Contains(CSVSections, section) {
+		return fmt.Errorf("unknown section %q", section)
+	}
+	blocks, err := csvBlocks(wd, o)
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(blocks, func(b csvBlock) bool { return b.section == section })
+	if i < 0 {
+		return fmt.Errorf("no %s data", section)
+	}
+	return writeCSVBlocks(w, wd, blocks[i:i+1])
+}
+
+func writeCSVBlocks(w io.Writer, wd *WeatherData, blocks []csvBlock) error {
+	cw := csv.NewWriter(w)
+	writeCSVLocation(cw, wd)
+	for _, b := range blocks {
+		cw.Write(nil)
+		cw.Write([]string{b.section})
+		names := make([]string, len(b.columns))
+		units := make([]string, len(b.columns))
+		for i, c := range b.columns {
+			names[i], units[i] = c.name, c.unit
+		}
+		cw.Write(names)
+		cw.Write(units)
+		for i := range b.columns[0].values {
+			row := make([]string, len(b.columns))
+			for j, c := range b.columns {
+				row[j] = c.values[i]
+			}
+			cw.Write(row)
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeCSVLocation writes the location block of wd.
+func writeCSVLocation(cw *csv.Writer, wd *WeatherData) {
+	cw.Write(csvLocation)
+	cw.Write([]string{
+		strconv.FormatFloat(wd.Latitude, 'f', -1, 64),
+		strconv.FormatFloat(wd.Longitude, 'f', -1, 64),
+		strconv.FormatFloat(wd.Elevation, 'f', -1, 64),
+		strconv.Itoa(wd.UtcOffsetSeconds),
+		wd.Timezone,
+		wd.TimezoneAbbreviation,
+	})
+}
+
+// CSVHourlyWriter writes the chunks of a streamed hourly series as CSV, in
+// the layout WriteCSVSection uses for the hourly section, so that ReadCSV
+// reads the result. Pass its WriteHourly method to Client.StreamHourly.
+type CSVHourlyWriter struct {
+	cw      *csv.Writer
+	metrics Metrics
+}
+
+// NewCSVHourlyWriter returns a CSVHourlyWriter that writes to w.
+func NewCSVHourlyWriter(w io.Writer) *CSVHourlyWriter {
+	return &CSVHourlyWriter{cw: csv.NewWriter(w)}
+}
+
+// WriteHourly writes the rows of a chunk. The first chunk is preceded by the
+// location and header rows, and every later chunk must have the same
+// metrics. NaN values are written as empty cells.
+func (w *CSVHourlyWriter) WriteHourly(c *HourlyChunk) error {
+	if w.metrics == nil {
+		w.metrics = slices.Clone(c.Metrics)
+		writeCSVLocation(w.cw, c.Data)
+		w.cw.Write(nil)
+		w.cw.Write([]string{"hourly"})
+		names := []string{"time"}
+		for _, m := range c.Metrics {
+			names = append(names, string(m))
+		}
+		w.cw.Write(names)
+		w.cw.Write(append([]string{c.Data.HourlyUnits.Time}, c.Units...))
+	} else if !slices.Equal(w.metrics, c.Metrics) {
+		return fmt.Errorf("chunk metrics %v differ from %v", c.Metrics, w.metrics)
+	}
+
+	row := make([]string, len(c.Metrics)+1)
+	for i, t := range c.Time {
+		row[0] = t
+		for j, values := range c.Values {
+			row[j+1] = ""
+			if v := values[i]; !math.IsNaN(v) {
+				row[j+1] = strconv.FormatFloat(v, 'f', -1, 64)
+			}
+		}
+		w.cw.Write(row)
+	}
+	w.cw.Flush()
+	return w.cw.Error()
+}
+
+// sectionMetrics returns the metrics o requested for a section.
+func sectionMetrics(o *Options, section string) Metrics {
+	switch section {
+	case "current":
+		return o.CurrentMetrics
+	case "minutely_15":
+		return o.Minutely15Metrics
+	case "hourly":
+		return o.HourlyMetrics
+	case "daily":
+		return o.DailyMetrics
+	case "weekly":
+		return o.WeeklyMetrics
+	case "monthly":
+		return o.MonthlyMetrics
+	}
+	return nil
+}
+
+// csvBlocks returns the sections of wd with data as CSV blocks. It goes
+// through the JSON encoding so that every field, including pressure level
+// variables, is found by its API name.
+func csvBlocks(wd *WeatherData, o *Options) ([]csvBlock, error) {
+	top, err := jsonFields(wd)
+	if err != nil {
+		return nil, err
+	}
+
+	// The per-model series, as named in the API.
+	type source struct {
+		top    map[string]json.RawMessage
+		suffix string
+	}
+	var names []string
+	switch {
+	case o != nil && len(o.Models) > 1:
+		names = o.Models
+	case o == nil:
+		for m := range wd.ByModel {
+			names = append(names, m)
+		}
+		slices.Sort(names)
+	}
+	var models []source
+	for _, m := range names {
+		md, ok := wd.ByModel[m]
+		if !ok {
+			continue
+		}
+		fields, err := jsonFields(md)
+		if err != nil {
+			return nil, err
+		}
+		models = append(models, source{fields, "_" + m})
+	}
+
+	var blocks []csvBlock
+	for _, section := range CSVSections {
+		if o != nil && len(sectionMetrics(o, section)) == 0 {
+			continue
+		}
+		data, units, keys, err := sectionFields(top, section)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", section, err)
+		}
+		times, err := csvValues(data["time"])
+		if err != nil {
+			return nil, fmt.Errorf("%s: time: %w", section, err)
+		}
+		if len(times) == 0 || times[0] == "" {
+			continue
+		}
+
+		b := csvBlock{section: section, columns: []csvColumn{{"time", csvUnit(units["time"]), times}}}
+		add := func(fields, units map[string]json.RawMessage, metric, suffix string) error {
+			name := metric + suffix
+			values, err := csvValues(fields[metric])
+			if err != nil {
+				return fmt.Errorf("%s: %s: %w", section, name, err)
+			}
+			// Without options, a metric is only present if it has a unit.
+			if values == nil || (o == nil && csvUnit(units[metric]) == "") {
+				return nil
+			}
+			if len(values) != len(times) {
+				return fmt.Errorf("%s: %s has %d values for %d timestamps", section, name, len(values), len(times))
+			}
+			b.columns = append(b.columns, csvColumn{name, csvUnit(units[metric]), values})
+			return nil
+		}
+
+		if section == "current" {
+			if err := add(data, units, "interval", ""); err != nil {
+				return nil, err
+			}
+		}
+		if len(models) == 0 || !slices.Contains(modelSections, section) {
+			metrics := keys
+			if o != nil {
+				metrics = metrics[:0:0]
+				for _, m := range sectionMetrics(o, section) {
+					metrics = append(metrics, string(m))
+				}
+			}
+			for _, m := range metrics {
+				if m == "time" || m == "interval" {
+					continue
+				}
+				if err := add(data, units, m, ""); err != nil {
+					return nil, err
+				}
+			}
+		} else {
+			for _, src := range models {
+				md, mu, keys, err := sectionFields(src.top, section)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", section, err)
+				}
+				if o != nil {
+					keys = keys[:0:0]
+					for _, m := range sectionMetrics(o, section) {
+						keys = append(keys, string(m))
+					}
+				}
+				for _, m := range keys {
+					if m == "time" || m == "interval" {
+						continue
+					}
+					if err := add(md, mu, m, src.suffix); err != nil {
+						return nil, err
+					}
+				}
+			}
+		}
+		if len(b.columns) > 1 {
+			blocks = append(blocks, b)
+		}
+	}
+	return blocks, nil
+}
+
+// jsonFields returns the top-level fields of v encoded as JSON.
+func jsonFields(v any) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// sectionFields returns the fields of a section and of its units, and the
+// names of the section's fields in encoding order.
+func sectionFields(top map[string]json.RawMessage, section string) (data, units map[string]json.RawMessage, keys []string, err error) {
+	raw, ok := top[section]
+	if !ok || string(raw) == "null" {
+		return nil, nil, nil, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if _, err := dec.Token(); err != nil {
+		return nil, nil, nil, err
+	}
+	data = map[string]json.RawMessage{}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		key, _ := tok.(string)
+		var v json.RawMessage
+		if err := dec.Decode(&v); err != nil {
+			return nil, nil, nil, err
+		}
+		data[key] = v
+		keys = append(keys, key)
+	}
+	units = map[string]json.RawMessage{}
+	if raw, ok := top[section+"_units"]; ok && string(raw) != "null" {
+		if err := json.Unmarshal(raw, &units); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	return data, units, keys, nil
+}
+
+// csvValues returns a JSON series, or a single value, as CSV cells. Nulls
+// are empty cells. A missing or null series returns nil.
+func csvValues(raw json.RawMessage) ([]string, error) {
+	if raw == nil || string(raw) == "null" {
+		return nil, nil
+	}
+	var elems []json.RawMessage
+	if raw[0] == '[' {
+		if err := json.Unmarshal(raw, &elems); err != nil {
+			return nil, err
+		}
+		if len(elems) == 0 {
+			return nil, nil
+		}
+	} else {
+		elems = []json.RawMessage{raw}
+	}
+	values := make([]string, len(elems))
+	for i, e := range elems {
+		switch {
+		case string(e) == "null":
+		case e[0] == '"':
+			if err := json.Unmarshal(e, &values[i]); err != nil {
+				return nil, err
+			}
+		default:
+			values[i] = string(e)
+		}
+	}
+	return values, nil
+}
+
+// csvUnit returns a JSON unit as a string.
+func csvUnit(raw json.RawMessage) string {
+	var u string
+	json.Unmarshal(raw, &u)
+	return u
+}
+
+// ReadCSV reads WeatherData from CSV written by WriteCSV or WriteCSVSection,
+// or from the API's own format=csv output, in which units follow the metric
+// names, e.g. "temperature_2m (°C)", and sections are told apart by their
+// time step. Empty cells are missing values. As with Client.Get, o gives
+// the requested models, so that per-model columns are grouped into
+// WeatherData.ByModel; it may be nil.
+func ReadCSV(r io.Reader, o *Options) (*WeatherData, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	top := map[string]any{}
+	var blocks []*csvBlock
+	var block *csvBlock
+	title := ""
+	for {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading CSV: %w", err)
+		}
+		line, _ := cr.FieldPos(0)
+
+		switch {
+		case rec[0] == "location_id":
+			return nil, fmt.Errorf("reading CSV: line %d: multiple locations are not supported", line)
+
+		case rec[0] == "latitude":
+			values, err := cr.Read()
+			if err != nil {
+				return nil, fmt.Errorf("reading CSV: line %d: location: %w", line, err)
+			}
+			for i, name := range rec {
+				if i < len(values) {
+					top[name] = csvLocationValue(name, values[i])
+				}
+			}
+			block = nil
+
+		case len(rec) == 1 && slices.Contains(CSVSections, rec[0]):
+			title = rec[0]
+			block = nil
+
+		case rec[0] == "time":
+			block = &csvBlock{section: title, line: line}
+			if title != "" {
+				units, err := cr.Read()
+				if err != nil {
+					return nil, fmt.Errorf("reading CSV: line %d: %s units: %w", line, title, err)
+				}
+				for i, name := range rec {
+					unit := ""
+					if i < len(units) {
+						unit = units[i]
+					}
+					block.columns = append(block.columns, csvColumn{name: name, unit: unit})
+				}
+			} else {
+				for _, h := range rec {
+					name, unit := h, ""
+					if i := strings.LastIndex(h, " ("); i > 0 && strings.HasSuffix(h, ")") {
+						name, unit = h[:i], h[i+2:len(h)-1]
+					}
+					block.columns = append(block.columns, csvColumn{name: name, unit: unit})
+				}
+			}
+			blocks = append(blocks, block)
+			title = ""
+
+		case block == nil:
+			return nil, fmt.Errorf("reading CSV: line %d: row outside a section", line)
+
+		default:
+			if len(rec) != len(block.columns) {
+				return nil, fmt.Errorf("reading CSV: line %d: %d fields for %d columns", line, len(rec), len(block.columns))
+			}
+			for i, v := range rec {
+				block.columns[i].values = append(block.columns[i].values, v)
+			}
+		}
+	}
+
+	for _, b := range blocks {
+		if b.section == "" {
+			section, unit, err := inferSection(b)
+			if err != nil {
+				return nil, fmt.Errorf("reading CSV: line %d: %w", b.line, err)
+			}
+			b.section, b.columns[0].unit = section, unit
+		}
+		if _, ok := top[b.section]; ok {
+			return nil, fmt.Errorf("reading CSV: line %d: duplicate %s section", b.line, b.section)
+		}
+
+		data, units := map[string]any{}, map[string]string{}
+		for _, c := range b.columns {
+			values := make([]any, len(c.values))
+			for i, v := range c.values {
+				if c.name == "time" && c.unit != "unixtime" {
+					values[i] = v
+				} else {
+					values[i] = csvValue(v)
+				}
+			}
+			if b.section == "current" && len(values) == 1 {
+				data[c.name] = values[0]
+			} else {
+				data[c.name] = values
+			}
+			units[c.name] = c.unit
+		}
+		top[b.section], top[b.section+"_units"] = data, units
+	}
+
+	data, err := json.Marshal(top)
+	if err != nil {
+		return nil, fmt.Errorf("reading CSV: %w", err)
+	}
+	if o == nil {
+		o = &Options{}
+	}
+	wd, err := decode(bytes.NewReader(data), o)
+	if err != nil {
+		return nil, fmt.Errorf("reading CSV: %w", err)
+	}
+	return wd, nil
+}
+
+// csvValue returns a CSV cell as a JSON value: null if empty, a number if
+// it is a JSON number, and a string otherwise.
+func csvValue(s string) any {
+	if s == "" {
+		return nil
+	}
+	// Valid JSON that starts like a number is one; ParseFloat would also
+	// accept cells such as "+04", "Inf" or "0x1p4".
+	if (s[0] == '-' || s[0] >= '0' && s[0] <= '9') && json.Valid([]byte(s)) {
+		return json.Number(s)
+	}
+	return s
+}
+
+// csvLocationValue returns a cell of the location block as a JSON value.
+// Cells of string fields, such as a timezone abbreviation like "+04", are
+// kept as strings.
+func csvLocationValue(name, s string) any {
+	t := reflect.TypeOf(WeatherData{})
+	for i := 0; i < t.NumField(); i++ {
+		if jsonName(t.Field(i)) == name && t.Field(i).Type.Kind() == reflect.String {
+			return s
+		}
+	}
+	return csvValue(s)
+}
+
+// inferSection tells which section a block of the API's CSV output holds,
+// and the format of its time column, "iso8601" or "unixtime". A block with
+// an interval column, or a single timestamp with a time of day, is the
+// current weather; other blocks are told apart by their time step.
+func inferSection(b *csvBlock) (section, unit string, err error) {
+	times := b.columns[0].values
+	if len(times) == 0 {
+		return "", "", errors.New("section without rows")
+	}
+	unit = "iso8601"
+	parse := func(s string) (time.Time, bool, error) {
+		if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+			unit = "unixtime"
+			return time.Unix(n, 0), true, nil
+		}
+		if t, err := time.Parse(DateFormat, s); err == nil {
+			return t, false, nil
+		}
+		t, err := time.Parse(HourFormat, s)
+		return t, true, err
+	}
+
+	t0, clock, err := parse(times[0])
+	if err != nil {
+		return "", "", fmt.Errorf("time %q: %w", times[0], err)
+	}
+	if slices.ContainsFunc(b.columns, func(c csvColumn) bool { return c.name == "interval" }) {
+		return "current", unit, nil
+	}
+	if len(times) == 1 {
+		if clock {
+			return "current", unit, nil
+		}
+		return "daily", unit, nil
+	}
+	t1, _, err := parse(times[1])
+	if err != nil {
+		return "", "", fmt.Errorf("time %q: %w", times[1], err)
+	}
+
+	switch step := t1.Sub(t0); {
+	case step <= 15*time.Minute:
+		return "minutely_15", unit, nil
+	case step < 24*time.Hour:
+		return "hourly", unit, nil
+	case step < 7*24*time.Hour:
+		return "daily", unit, nil
+	case step < 28*24*time.Hour:
+		return "weekly", unit, nil
+	default:
+		return "monthly", unit, nil
+	}
+}
@@ -0,0 +1,287 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openmeteogo
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const csvResponse = `{
+	"latitude": 52.52, "longitude": 13.419998, "elevation": 38, "utc_offset_seconds": 3600,
+	"timezone": "Europe/Berlin", "timezone_abbreviation": "GMT+1",
+	"current_units": {"time": "iso8601", "interval": "seconds", "temperature_2m": "°C", "weather_code": "wmo code"},
+	"current": {"time": "2025-01-01T12:00", "interval": 900, "temperature_2m": 3.5, "weather_code": 61},
+	"hourly_units": {"time": "iso8601", "temperature_2m": "°C", "weather_code": "wmo code", "temperature_850hPa": "°C"},
+	"hourly": {"time": ["2025-01-01T00:00", "2025-01-01T01:00"], "temperature_2m": [1.5, 2],
+		"weather_code": [3, 95], "temperature_850hPa": [-4.2, -4.5]},
+	"daily_units": {"time": "iso8601", "temperature_2m_max": "°C", "sunrise": "iso8601"},
+	"daily": {"time": ["2025-01-01"], "temperature_2m_max": [4.2], "sunrise": ["2025-01-01T08:17"]}
+}`
+
+func csvWeatherData(t *testing.T) *WeatherData {
+	t.Helper()
+	wd, err := decode(strings.NewReader(csvResponse), &Options{})
+	require.NoError(t, err)
+	return wd
+}
+
+func TestWriteCSV(t *testing.T) {
+	wd := csvWeatherData(t)
+	o := &Options{
+		CurrentMetrics: Metrics{Temperature2m},
+		HourlyMetrics:  Metrics{"temperature_850hPa", Temperature2m, Precipitation},
+		DailyMetrics:   Metrics{Sunrise},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteCSV(&buf, wd, o))
+	want := `latitude,longitude,elevation,utc_offset_seconds,timezone,timezone_abbreviation
+52.52,13.419998,38,3600,Europe/Berlin,GMT+1
+
+current
+time,interval,temperature_2m
+iso8601,seconds,°C
+2025-01-01T12:00,900,3.5
+
+hourly
+time,temperature_850hPa,temperature_2m
+iso8601,°C,°C
+2025-01-01T00:00,-4.2,1.5
+2025-01-01T01:00,-4.5,2
+
+daily
+time,sunrise
+iso8601,iso8601
+2025-01-01,2025-01-01T08:17
+`
+	assert.Equal(t, want, buf.String(), "only the requested metrics with data are written")
+}
+
+func TestWriteCSV_AllMetrics(t *testing.T) {
+	wd := csvWeatherData(t)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteCSV(&buf, wd, nil))
+	got := buf.String()
+	assert.Contains(t, got, "\ncurrent\ntime,interval,temperature_2m,weather_code\niso8601,seconds,°C,wmo code\n2025-01-01T12:00,900,3.5,61\n")
+	assert.Contains(t, got, "\nhourly\ntime,temperature_2m,temperature_850hPa,weather_code\n")
+	assert.Contains(t, got, "\ndaily\ntime,temperature_2m_max,sunrise\n")
+	assert.NotContains(t, got, "minutely_15", "sections without data are skipped")
+	assert.NotContains(t, got, "precipitation", "metrics without units were not returned")
+}
+
+func TestCSV_RoundTrip(t *testing.T) {
+	wd := csvWeatherData(t)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteCSV(&buf, wd, nil))
+	got, err := ReadCSV(&buf, nil)
+	require.NoError(t, err)
+	assert.Equal(t, wd, got)
+}
+
+func TestCSV_RoundTripNumericAbbreviation(t *testing.T) {
+	const response = `{
+		"latitude": 25.25, "longitude": 55.25, "elevation": 5, "utc_offset_seconds": 14400,
+		"timezone": "Asia/Dubai", "timezone_abbreviation": "+04",
+		"hourly_units": {"time": "iso8601", "temperature_2m": "°C"},
+		"hourly": {"time": ["2025-01-01T00:00"], "temperature_2m": [21.5]}
+	}`
+	wd, err := decode(strings.NewReader(response), &Options{})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteCSV(&buf, wd, nil))
+	assert.Contains(t, buf.String(), "25.25,55.25,5,14400,Asia/Dubai,+04\n")
+	got, err := ReadCSV(&buf, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "+04", got.TimezoneAbbreviation)
+	assert.Equal(t, wd, got)
+}
+
+func TestCSVValue(t *testing.T) {
+	tests := map[string]any{
+		"":      nil,
+		"1.5":   json.Number("1.5"),
+		"-4":    json.Number("-4"),
+		"1e3":   json.Number("1e3"),
+		"+04":   "+04",
+		"Inf":   "Inf",
+		"NaN":   "NaN",
+		"0x1p4": "0x1p4",
+		"04":    "04",
+		"CET":   "CET",
+	}
+	for cell, want := range tests {
+		assert.Equal(t, want, csvValue(cell), cell)
+	}
+}
+
+func TestCSV_RoundTripModels(t *testing.T) {
+	const response = `{
+		"latitude": 52.52, "longitude": 13.42,
+		"hourly_units": {"time": "iso8601", "temperature_2m_icon_seamless": "°C", "temperature_2m_gfs_seamless": "°C"},
+		"hourly": {"time": ["2025-01-01T00:00"], "temperature_2m_icon_seamless": [1.5], "temperature_2m_gfs_seamless": [2.5]}
+	}`
+	o := &Options{Models: []string{"icon_seamless", "gfs_seamless"}, HourlyMetrics: Metrics{Temperature2m}}
+	wd, err := decode(strings.NewReader(response), o)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteCSV(&buf, wd, o))
+	assert.Contains(t, buf.String(), "\nhourly\ntime,temperature_2m_icon_seamless,temperature_2m_gfs_seamless\niso8601,°C,°C\n2025-01-01T00:00,1.5,2.5\n")
+
+	got, err := ReadCSV(&buf, o)
+	require.NoError(t, err)
+	require.Contains(t, got.ByModel, "gfs_seamless")
+	assert.Equal(t, []float64{2.5}, got.ByModel["gfs_seamless"].Hourly.Temperature2m)
+	assert.Equal(t, "°C", got.ByModel["gfs_seamless"].HourlyUnits.Temperature2m)
+	assert.Equal(t, wd.ByModel, got.ByModel)
+}
+
+func TestWriteCSVSection(t *testing.T) {
+	wd := csvWeatherData(t)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteCSVSection(&buf, wd, "daily", nil))
+	want := `latitude,longitude,elevation,utc_offset_seconds,timezone,timezone_abbreviation
+52.52,13.419998,38,3600,Europe/Berlin,GMT+1
+
+daily
+time,temperature_2m_max,sunrise
+iso8601,°C,iso8601
+2025-01-01,4.2,2025-01-01T08:17
+`
+	assert.Equal(t, want, buf.String())
+
+	got, err := ReadCSV(&buf, nil)
+	require.NoError(t, err)
+	assert.Equal(t, wd.Daily, got.Daily)
+	assert.Empty(t, got.Hourly.Time)
+
+	assert.EqualError(t, WriteCSVSection(&buf, wd, "minutely_15", nil), "no minutely_15 data")
+	assert.EqualError(t, WriteCSVSection(&buf, wd, "yearly", nil), `unknown section "yearly"`)
+}
+
+func TestReadCSV_APIFormat(t *testing.T) {
+	// The layout of the API's format=csv: units follow the names, and
+	// sections are not named.
+	const apiCSV = `latitude,longitude,elevation,utc_offset_seconds,timezone,timezone_abbreviation
+52.52,13.419998,38.0,3600,Europe/Berlin,GMT+1
+
+time,interval,temperature_2m (°C)
+2025-01-01T12:00,900,3.5
+
+time,temperature_2m (°C),precipitation (mm)
+2025-01-01T00:00,1.5,
+2025-01-01T01:00,2.0,0.3
+
+time,temperature_2m_max (°C),sunrise (iso8601)
+2025-01-01,4.2,2025-01-01T08:17
+2025-01-02,5.0,2025-01-02T08:17
+`
+	wd, err := ReadCSV(strings.NewReader(apiCSV), nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, 52.52, wd.Latitude)
+	assert.Equal(t, 38.0, wd.Elevation)
+	assert.Equal(t, "Europe/Berlin", wd.Timezone)
+	assert.Equal(t, Current{Time: "2025-01-01T12:00", Interval: 900, Temperature2m: 3.5}, wd.Current)
+	assert.Equal(t, "°C", wd.CurrentUnits.Temperature2m)
+	assert.Equal(t, []string{"2025-01-01T00:00", "2025-01-01T01:00"}, wd.Hourly.Time)
+	assert.Equal(t, []float64{1.5, 2}, wd.Hourly.Temperature2m)
+	assert.Equal(t, []float64{0, 0.3}, wd.Hourly.Precipitation, "empty cells are missing values")
+	assert.Equal(t, "iso8601", wd.HourlyUnits.Time)
+	assert.Equal(t, "mm", wd.HourlyUnits.Precipitation)
+	assert.Equal(t, []float64{4.2, 5}, wd.Daily.Temperature2mMax)
+	assert.Equal(t, []string{"2025-01-01T08:17", "2025-01-02T08:17"}, wd.Daily.Sunrise)
+}
+
+func TestInferSection(t *testing.T) {
+	tests := map[string]struct {
+		header  []string
+		times   []string
+		section string
+		unit    string
+	}{
+		"current":          {[]string{"time", "interval"}, []string{"2025-01-01T12:00"}, "current", "iso8601"},
+		"current no step":  {[]string{"time"}, []string{"2025-01-01T12:00"}, "current", "iso8601"},
+		"minutely_15":      {[]string{"time"}, []string{"2025-01-01T12:00", "2025-01-01T12:15"}, "minutely_15", "iso8601"},
+		"hourly":           {[]string{"time"}, []string{"2025-01-01T12:00", "2025-01-01T13:00"}, "hourly", "iso8601"},
+		"hourly unixtime":  {[]string{"time"}, []string{"1735732800", "1735736400"}, "hourly", "unixtime"},
+		"daily":            {[]string{"time"}, []string{"2025-01-01", "2025-01-02"}, "daily", "iso8601"},
+		"single day":       {[]string{"time"}, []string{"2025-01-01"}, "daily", "iso8601"},
+		"weekly":           {[]string{"time"}, []string{"2025-01-01", "2025-01-08"}, "weekly", "iso8601"},
+		"monthly":          {[]string{"time"}, []string{"2025-01-01", "2025-02-01"}, "monthly", "iso8601"},
+		"monthly unixtime": {[]string{"time"}, []string{"1735689600", "1738368000"}, "monthly", "unixtime"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			b := &csvBlock{}
+			for _, h := range tc.header {
+				b.columns = append(b.columns, csvColumn{name: h})
+			}
+			b.columns[0].values = tc.times
+			section, unit, err := inferSection(b)
+			require.NoError(t, err)
+			assert.Equal(t, tc.section, section)
+			assert.Equal(t, tc.unit, unit)
+		})
+	}
+}
+
+func TestReadCSV_Errors(t *testing.T) {
+	tests := map[string]string{
+		"row outside a section": "2025-01-01T00:00,1.5\n",
+		"field count":           "time,temperature_2m (°C)\n2025-01-01T00:00,1.5,2\n",
+		"multiple locations":    "location_id,latitude,longitude\n0,52.52,13.42\n",
+		"duplicate section":     "hourly\ntime,temperature_2m\niso8601,°C\n2025-01-01T00:00,1\n\nhourly\ntime,temperature_2m\niso8601,°C\n2025-01-01T00:00,1\n",
+		"bad time":              "time,temperature_2m (°C)\nyesterday,1.5\n",
+		"bad value":             "hourly\ntime,temperature_2m\niso8601,°C\n2025-01-01T00:00,warm\n",
+	}
+
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ReadCSV(strings.NewReader(input), nil)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestCSVHourlyWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewCSVHourlyWriter(&buf)
+	require.NoError(t, DecodeHourlyStream(strings.NewReader(csvResponse), 1, w.WriteHourly))
+
+	var want bytes.Buffer
+	wd := csvWeatherData(t)
+	o := NewOptionsBuilder().HourlyMetrics(Metrics{Temperature2m, WeatherCode, "temperature_850hPa"}).Build()
+	require.NoError(t, WriteCSVSection(&want, wd, "hourly", o))
+	assert.Equal(t, want.String(), buf.String(), "streamed chunks are written in the WriteCSVSection layout, in response order")
+
+	got, err := ReadCSV(&buf, nil)
+	require.NoError(t, err)
+	assert.Equal(t, wd.Hourly, got.Hourly)
+
+	other := &HourlyChunk{Data: wd, Metrics: Metrics{Rain}, Units: []string{"mm"}}
+	assert.ErrorContains(t, w.WriteHourly(other), "chunk metrics [rain] differ")
+}
@@ -0,0 +1,361 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package derived computes meteorological quantities that the Open-Meteo API
+// does not return, such as heat index or cloud base height, from the hourly
+// data it does return.
+//
+// Every function reads the fields it needs from WeatherData.Hourly, converts
+// them using the units reported in WeatherData.HourlyUnits, and returns a
+// Series aligned to Hourly.Time. Series values are computed locally and are
+// marked as such, so they are never confused with values from the API.
+package derived
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/tpryan/openmeteogo"
+)
+
+// Source is the value of Series.Source for every series in this package.
+const Source = "local"
+
+// Series is a locally computed hourly series.
+type Series struct {
+	// Name is the snake_case name of the quantity, e.g. "heat_index".
+	Name string
+	// Source is always Source, marking the values as computed locally
+	// rather than returned by the API.
+	Source string
+	// Method names the formula used to compute the values.
+	Method string
+	// Unit is the unit of Values.
+	Unit openmeteogo.Unit
+	// Time holds the timestamps of Values, as in Hourly.Time.
+	Time []string
+	// Values holds one value per timestamp.
+	Values []float64
+}
+
+// inputs holds the hourly fields used by the formulas, converted to °C, %
+// and m/s. tempUnit is the temperature unit reported by the API, which
+// temperature results are returned in.
+type inputs struct {
+	time        []string
+	temperature []float64
+	humidity    []float64
+	dewPoint    []float64
+	windSpeed   []float64
+	tempUnit    openmeteogo.Unit
+}
+
+// field names the hourly fields inputs can be loaded from.
+type field int
+
+const (
+	temperatureField field = 1 << iota
+	humidityField
+	dewPointField
+	windSpeedField
+)
+
+// load reads the requested fields from wd. A missing dew point is computed
+// from temperature and relative humidity when both are present.
+func load(wd *openmeteogo.WeatherData, fields field) (*inputs, error) {
+	if wd == nil {
+		return nil, fmt.Errorf("no weather data")
+	}
+	h := wd.Hourly
+	in := &inputs{time: h.Time}
+	n := len(h.Time)
+
+	if fields&dewPointField != 0 && len(h.DewPoint2m) == 0 {
+		fields |= temperatureField | humidityField
+	}
+
+	var err error
+	if fields&temperatureField != 0 {
+		if in.tempUnit, err = wd.HourlyUnits.Unit(openmeteogo.Temperature2m); err != nil {
+			return nil, err
+		}
+		if in.temperature, err = wd.HourlyValues(openmeteogo.Temperature2m, openmeteogo.UnitCelsius); err != nil {
+			return nil, err
+		}
+	}
+	if fields&humidityField != 0 {
+		if len(h.RelativeHumidity2m) != n || n == 0 {
+			return nil, fmt.Errorf("relative_humidity_2m: need %d values, got %d", n, len(h.RelativeHumidity2m))
+		}
+		in.humidity = make([]float64, n)
+		for i, v := range h.RelativeHumidity2m {
+			in.humidity[i] = float64(v)
+		}
+	}
+	if fields&dewPointField != 0 {
+		if len(h.DewPoint2m) == 0 {
+			in.dewPoint = make([]float64, n)
+			for i := range in.dewPoint {
+				in.dewPoint[i] = dewPoint(in.temperature[i], in.humidity[i])
+			}
+		} else {
+			if in.dewPoint, err = wd.HourlyValues(openmeteogo.DewPoint2m, openmeteogo.UnitCelsius); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if fields&windSpeedField != 0 {
+		if in.windSpeed, err = wd.HourlyValues(openmeteogo.WindSpeed10m, openmeteogo.UnitMs); err != nil {
+			return nil, err
+		}
+	}
+	return in, nil
+}
+
+// temperatureSeries builds a Series from values in °C, converted back to
+// the temperature unit of the input.
+func (in *inputs) temperatureSeries(name, method string, values []float64) (*Series, error) {
+	for i, v := range values {
+		c, err := openmeteogo.Convert(v, openmeteogo.UnitCelsius, in.tempUnit)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		values[i] = c
+	}
+	return in.series(name, method, in.tempUnit, values), nil
+}
+
+// series builds a Series aligned to the input timestamps.
+func (in *inputs) series(name, method string, unit openmeteogo.Unit, values []float64) *Series {
+	return &Series{
+		Name:   name,
+		Source: Source,
+		Method: method,
+		Unit:   unit,
+		Time:   in.time,
+		Values: values,
+	}
+}
+
+// HeatIndex returns the heat index computed from temperature_2m and
+// relative_humidity_2m, in the temperature unit of the input. Below 80°F the
+// simpler Steadman approximation is used, as the NWS does.
+func HeatIndex(wd *openmeteogo.WeatherData) (*Series, error) {
+	in, err := load(wd, temperatureField|humidityField)
+	if err != nil {
+		return nil, fmt.Errorf("heat index: %w", err)
+	}
+	values := make([]float64, len(in.time))
+	for i := range values {
+		values[i] = heatIndex(in.temperature[i], in.humidity[i])
+	}
+	return in.temperatureSeries("heat_index", "NWS Rothfusz regression", values)
+}
+
+// WindChill returns the wind chill computed from temperature_2m and
+// wind_speed_10m, in the temperature unit of the input. Where the formula
+// does not apply, above 10°C or below 4.8 km/h, the air temperature is used.
+func WindChill(wd *openmeteogo.WeatherData) (*Series, error) {
+	in, err := load(wd, temperatureField|windSpeedField)
+	if err != nil {
+		return nil, fmt.Errorf("wind chill: %w", err)
+	}
+	values := make([]float64, len(in.time))
+	for i := range values {
+		values[i] = windChill(in.temperature[i], in.windSpeed[i])
+	}
+	return in.temperatureSeries("wind_chill", "NWS/MSC 2001 wind chill index", values)
+}
+
+// Humidex returns the Canadian humidex computed from temperature_2m and
+// dew_point_2m, in the temperature unit of the input. If the data has no
+// dew point it is computed from relative_humidity_2m.
+func Humidex(wd *openmeteogo.WeatherData) (*Series, error) {
+	in, err := load(wd, temperatureField|dewPointField)
+	if err != nil {
+		return nil, fmt.Errorf("humidex: %w", err)
+	}
+	values := make([]float64, len(in.time))
+	for i := range values {
+		values[i] = humidex(in.temperature[i], in.dewPoint[i])
+	}
+	return in.temperatureSeries("humidex", "Environment Canada humidex", values)
+}
+
+// WetBulbTemperature returns the wet-bulb temperature computed from
+// temperature_2m and relative_humidity_2m, in the temperature unit of the
+// input. The approximation is valid at sea level pressure for relative
+// humidity above 5%.
+func WetBulbTemperature(wd *openmeteogo.WeatherData) (*Series, error) {
+	in, err := load(wd, temperatureField|humidityField)
+	if err != nil {
+		return nil, fmt.Errorf("wet-bulb temperature: %w", err)
+	}
+	values := make([]float64, len(in.time))
+	for i := range values {
+		values[i] = wetBulb(in.temperature[i], in.humidity[i])
+	}
+	return in.temperatureSeries("wet_bulb_temperature", "Stull 2011", values)
+}
+
+// DewPoint returns the dew point computed from temperature_2m and
+// relative_humidity_2m, in the temperature unit of the input. Unlike the
+// API's dew_point_2m it can be derived from any response with those fields.
+func DewPoint(wd *openmeteogo.WeatherData) (*Series, error) {
+	in, err := load(wd, temperatureField|humidityField)
+	if err != nil {
+		return nil, fmt.Errorf("dew point: %w", err)
+	}
+	values := make([]float64, len(in.time))
+	for i := range values {
+		values[i] = dewPoint(in.temperature[i], in.humidity[i])
+	}
+	return in.temperatureSeries("dew_point", "Magnus formula", values)
+}
+
+// AbsoluteHumidity returns the mass of water vapour per cubic metre of air,
+// in g/m³, computed from temperature_2m and relative_humidity_2m.
+func AbsoluteHumidity(wd *openmeteogo.WeatherData) (*Series, error) {
+	in, err := load(wd, temperatureField|humidityField)
+	if err != nil {
+		return nil, fmt.Errorf("absolute humidity: %w", err)
+	}
+	values := make([]float64, len(in.time))
+	for i := range values {
+		values[i] = absoluteHumidity(in.temperature[i], in.humidity[i])
+	}
+	return in.series("absolute_humidity", "Magnus formula and ideal gas law", openmeteogo.UnitGramPerCubicMetre, values), nil
+}
+
+// CloudBaseHeight returns the estimated height of the base of convective
+// clouds above ground, computed from the spread between temperature_2m and
+// dew_point_2m. It is in metres, or feet if the temperature is in °F.
+func CloudBaseHeight(wd *openmeteogo.WeatherData) (*Series, error) {
+	in, err := load(wd, temperatureField|dewPointField)
+	if err != nil {
+		return nil, fmt.Errorf("cloud base height: %w", err)
+	}
+	unit := openmeteogo.UnitMetre
+	if in.tempUnit == openmeteogo.UnitFahrenheit {
+		unit = openmeteogo.UnitFoot
+	}
+	values := make([]float64, len(in.time))
+	for i := range values {
+		v, err := openmeteogo.Convert(cloudBase(in.temperature[i], in.dewPoint[i]), openmeteogo.UnitMetre, unit)
+		if err != nil {
+			return nil, fmt.Errorf("cloud base height: %w", err)
+		}
+		values[i] = v
+	}
+	return in.series("cloud_base_height", "dew point spread, 125 m/°C", unit, values), nil
+}
+
+// FeelsLike returns the temperature it feels like outside, in the
+// temperature unit of the input: the wind chill when it is cold and windy,
+// the heat index when it is hot, and the air temperature otherwise. It needs
+// temperature_2m, relative_humidity_2m and wind_speed_10m.
+func FeelsLike(wd *openmeteogo.WeatherData) (*Series, error) {
+	in, err := load(wd, temperatureField|humidityField|windSpeedField)
+	if err != nil {
+		return nil, fmt.Errorf("feels like: %w", err)
+	}
+	values := make([]float64, len(in.time))
+	for i := range values {
+		values[i] = feelsLike(in.temperature[i], in.humidity[i], in.windSpeed[i])
+	}
+	return in.temperatureSeries("feels_like", "wind chill below 10°C, heat index above 26.7°C", values)
+}
+
+func cToF(c float64) float64 { return c*9/5 + 32 }
+func fToC(f float64) float64 { return (f - 32) * 5 / 9 }
+
+// saturationVapourPressure returns the saturation vapour pressure over
+// water in hPa at t °C.
+func saturationVapourPressure(t float64) float64 {
+	return 6.112 * math.Exp(17.62*t/(243.12+t))
+}
+
+// heatIndex implements the NWS heat index algorithm for t °C and rh %.
+func heatIndex(t, rh float64) float64 {
+	f := cToF(t)
+	hi := 0.5 * (f + 61 + (f-68)*1.2 + rh*0.094)
+	if (hi+f)/2 < 80 {
+		return fToC(hi)
+	}
+	hi = -42.379 + 2.04901523*f + 10.14333127*rh - 0.22475541*f*rh -
+		0.00683783*f*f - 0.05481717*rh*rh + 0.00122874*f*f*rh +
+		0.00085282*f*rh*rh - 0.00000199*f*f*rh*rh
+	switch {
+	case rh < 13 && f >= 80 && f <= 112:
+		hi -= (13 - rh) / 4 * math.Sqrt((17-math.Abs(f-95))/17)
+	case rh > 85 && f >= 80 && f <= 87:
+		hi += (rh - 85) / 10 * (87 - f) / 5
+	}
+	return fToC(hi)
+}
+
+// windChill implements the wind chill index for t °C and wind speed ms m/s.
+func windChill(t, ms float64) float64 {
+	kmh := ms * 3.6
+	if t > 10 || kmh < 4.8 {
+		return t
+	}
+	v := math.Pow(kmh, 0.16)
+	return 13.12 + 0.6215*t - 11.37*v + 0.3965*t*v
+}
+
+// humidex implements the humidex for t °C and dew point td °C.
+func humidex(t, td float64) float64 {
+	e := 6.11 * math.Exp(5417.7530*(1/273.16-1/(273.15+td)))
+	return t + 0.5555*(e-10)
+}
+
+// wetBulb implements Stull's wet-bulb approximation for t °C and rh %.
+func wetBulb(t, rh float64) float64 {
+	return t*math.Atan(0.151977*math.Sqrt(rh+8.313659)) +
+		math.Atan(t+rh) - math.Atan(rh-1.676331) +
+		0.00391838*math.Pow(rh, 1.5)*math.Atan(0.023101*rh) - 4.686035
+}
+
+// dewPoint implements the Magnus formula for t °C and rh %.
+func dewPoint(t, rh float64) float64 {
+	if rh <= 0 {
+		return math.NaN()
+	}
+	g := math.Log(rh/100) + 17.62*t/(243.12+t)
+	return 243.12 * g / (17.62 - g)
+}
+
+// absoluteHumidity returns g/m³ of water vapour for t °C and rh %.
+func absoluteHumidity(t, rh float64) float64 {
+	e := saturationVapourPressure(t) * rh / 100 * 100 // Pa
+	return e / (461.5 * (t + 273.15)) * 1000
+}
+
+// cloudBase returns the convective cloud base in metres above ground for
+// t °C and dew point td °C.
+func cloudBase(t, td float64) float64 {
+	return math.Max(0, (t-td)*125)
+}
+
+// feelsLike chooses between wind chill, heat index and t °C.
+func feelsLike(t, rh, ms float64) float64 {
+	switch {
+	case t <= 10 && ms*3.6 >= 4.8:
+		return windChill(t, ms)
+	case t >= 26.7:
+		return heatIndex(t, rh)
+	}
+	return t
+}
@@ -0,0 +1,179 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package derived
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tpryan/openmeteogo"
+)
+
+func weatherData(tempUnit, windUnit string, temp []float64, rh []int, wind []float64) *openmeteogo.WeatherData {
+	time := make([]string, len(temp))
+	for i := range time {
+		time[i] = "2025-07-01T00:00"
+	}
+	return &openmeteogo.WeatherData{
+		HourlyUnits: openmeteogo.HourlyUnits{
+			Temperature2m:      tempUnit,
+			RelativeHumidity2m: "%",
+			WindSpeed10m:       windUnit,
+		},
+		Hourly: openmeteogo.Hourly{
+			Time:               time,
+			Temperature2m:      temp,
+			RelativeHumidity2m: rh,
+			WindSpeed10m:       wind,
+		},
+	}
+}
+
+func TestSeries(t *testing.T) {
+	celsius := weatherData("°C", "km/h", []float64{20, 30, -10}, []int{50, 70, 80}, []float64{10, 5, 30})
+	fahrenheit := weatherData("°F", "mp/h", []float64{90, 0}, []int{70, 50}, []float64{5, 15})
+
+	tests := map[string]struct {
+		fn       func(*openmeteogo.WeatherData) (*Series, error)
+		wd       *openmeteogo.WeatherData
+		wantUnit openmeteogo.Unit
+		want     []float64
+		delta    float64
+	}{
+		"heat index °F": {
+			fn:       HeatIndex,
+			wd:       fahrenheit,
+			wantUnit: openmeteogo.UnitFahrenheit,
+			want:     []float64{105.9, -7.95},
+			delta:    0.2,
+		},
+		"wind chill °F": {
+			fn:       WindChill,
+			wd:       fahrenheit,
+			wantUnit: openmeteogo.UnitFahrenheit,
+			// 90°F is out of range; the NWS chart gives -19°F for 0°F at 15 mph.
+			want:  []float64{90, -19.4},
+			delta: 0.5,
+		},
+		"dew point": {
+			fn:       DewPoint,
+			wd:       celsius,
+			wantUnit: openmeteogo.UnitCelsius,
+			want:     []float64{9.26, 23.9, -12.8},
+			delta:    0.1,
+		},
+		"wet-bulb temperature": {
+			fn:       WetBulbTemperature,
+			wd:       celsius,
+			wantUnit: openmeteogo.UnitCelsius,
+			want:     []float64{13.7, 25.5, -11.2},
+			delta:    0.3,
+		},
+		"absolute humidity": {
+			fn:       AbsoluteHumidity,
+			wd:       celsius,
+			wantUnit: openmeteogo.UnitGramPerCubicMetre,
+			want:     []float64{8.64, 21.2, 1.88},
+			delta:    0.05,
+		},
+		"humidex": {
+			fn:       Humidex,
+			wd:       celsius,
+			wantUnit: openmeteogo.UnitCelsius,
+			want:     []float64{20.9, 41.2, -14.3},
+			delta:    0.2,
+		},
+		"cloud base": {
+			fn:       CloudBaseHeight,
+			wd:       celsius,
+			wantUnit: openmeteogo.UnitMetre,
+			want:     []float64{1343, 763, 350},
+			delta:    10,
+		},
+		"feels like": {
+			fn:       FeelsLike,
+			wd:       celsius,
+			wantUnit: openmeteogo.UnitCelsius,
+			want:     []float64{20, 35.0, -19.5},
+			delta:    0.5,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := tc.fn(tc.wd)
+			require.NoError(t, err)
+			assert.Equal(t, Source, got.Source)
+			assert.NotEmpty(t, got.Method)
+			assert.Equal(t, tc.wantUnit, got.Unit)
+			assert.Equal(t, tc.wd.Hourly.Time, got.Time)
+			assert.InDeltaSlice(t, tc.want, got.Values, tc.delta)
+		})
+	}
+}
+
+func TestHumidex_UsesDewPoint(t *testing.T) {
+	wd := weatherData("°C", "km/h", []float64{30}, nil, nil)
+	wd.HourlyUnits.DewPoint2m = "°C"
+	wd.Hourly.DewPoint2m = []float64{15}
+
+	got, err := Humidex(wd)
+	require.NoError(t, err)
+	assert.InDelta(t, 34.0, got.Values[0], 0.1)
+}
+
+func TestCloudBaseHeight_Imperial(t *testing.T) {
+	wd := weatherData("°F", "mp/h", []float64{68}, []int{50}, nil)
+
+	got, err := CloudBaseHeight(wd)
+	require.NoError(t, err)
+	assert.Equal(t, openmeteogo.UnitFoot, got.Unit)
+	assert.InDelta(t, 1343/0.3048, got.Values[0], 30)
+}
+
+func TestSeries_Errors(t *testing.T) {
+	tests := map[string]struct {
+		fn func(*openmeteogo.WeatherData) (*Series, error)
+		wd *openmeteogo.WeatherData
+	}{
+		"nil data": {
+			fn: HeatIndex,
+		},
+		"missing humidity": {
+			fn: DewPoint,
+			wd: weatherData("°C", "km/h", []float64{20}, nil, nil),
+		},
+		"missing wind": {
+			fn: WindChill,
+			wd: weatherData("°C", "km/h", []float64{20}, nil, nil),
+		},
+		"misaligned series": {
+			fn: HeatIndex,
+			wd: weatherData("°C", "km/h", []float64{20, 21}, []int{50}, nil),
+		},
+		"unknown unit": {
+			fn: HeatIndex,
+			wd: weatherData("K", "km/h", []float64{290}, []int{50}, nil),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := tc.fn(tc.wd)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,276 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openmeteogo
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+)
+
+// ChangeKind describes how a value changed between two fetches.
+type ChangeKind string
+
+const (
+	// ChangeValue means a value moved by at least its threshold.
+	ChangeValue ChangeKind = "changed"
+	// ChangeAppeared means precipitation or snowfall went from zero to
+	// a positive amount.
+	ChangeAppeared ChangeKind = "appeared"
+	// ChangeDisappeared means precipitation or snowfall went from a
+	// positive amount to zero.
+	ChangeDisappeared ChangeKind = "disappeared"
+	// ChangeWeatherGroup means a weather code moved to a different
+	// WeatherGroup, e.g. from rain to thunderstorm.
+	ChangeWeatherGroup ChangeKind = "weather_group"
+)
+
+// Thresholds sets the smallest absolute change Diff reports for each
+// metric, in the units of the old data. Metrics without a threshold report
+// any change.
+type Thresholds map[Metric]float64
+
+// Change is one difference found by Diff.
+type Change struct {
+	// Section is "current", "minutely_15", "hourly" or "daily".
+	Section string
+	// Metric is the metric that changed.
+	Metric Metric
+	// Time is the timestamp of the value. For the current section it is
+	// the time of the new data.
+	Time string
+	// Kind describes the change.
+	Kind ChangeKind
+	// Old and New are the values before and after. New is in the units of
+	// the old data. For weather codes they are the codes.
+	Old, New float64
+	// Unit is the unit of Old and New.
+	Unit Unit
+}
+
+// Delta returns New minus Old.
+func (c Change) Delta() float64 {
+	return c.New - c.Old
+}
+
+// String describes the change, e.g.
+// "hourly temperature_2m at 2025-01-01T10:00 changed: 12 -> 15.5 °C".
+func (c Change) String() string {
+	if c.Kind == ChangeWeatherGroup {
+		return fmt.Sprintf("%s %s at %s %s: %s -> %s", c.Section, c.Metric, c.Time, c.Kind,
+			CodeGroup(int(c.Old)), CodeGroup(int(c.New)))
+	}
+	desc := fmt.Sprintf("%s %s at %s %s: %v -> %v", c.Section, c.Metric, c.Time, c.Kind, c.Old, c.New)
+	if c.Unit != "" {
+		desc += " " + c.Unit.String()
+	}
+	return desc
+}
+
+// Diff compares two fetches of the same forecast, such as consecutive
+// polls, and returns what changed in the current, minutely_15, hourly and
+// daily sections. Series are aligned by timestamp, so values only present
+// in one fetch, at the shifted edges of the forecast window, are ignored.
+// Weather codes are only reported when their WeatherGroup changes, and
+// precipitation appearing or disappearing is always reported. Changes are
+// ordered by section, metric and time. It returns an error if either fetch
+// is nil.
+func Diff(previous, latest *WeatherData, thresholds Thresholds) ([]Change, error) {
+	if previous == nil || latest == nil {
+		return nil, fmt.Errorf("nil weather data")
+	}
+	type pair struct {
+		name             string
+		oldUnits, oldVal any
+		newUnits, newVal any
+		suffix           string
+	}
+	pairs := []pair{
+		{"current", &previous.CurrentUnits, &previous.Current, &latest.CurrentUnits, &latest.Current, ""},
+		{"minutely_15", &previous.Minutely15Units, &previous.Minutely15, &latest.Minutely15Units, &latest.Minutely15, ""},
+		{"hourly", &previous.HourlyUnits, &previous.Hourly, &latest.HourlyUnits, &latest.Hourly, ""},
+	}
+	levels := make([]int, 0, len(previous.Hourly.PressureLevels))
+	for level := range previous.Hourly.PressureLevels {
+		levels = append(levels, level)
+	}
+	slices.Sort(levels)
+	for _, level := range levels {
+		ou, nu := previous.HourlyUnits.PressureLevels[level], latest.HourlyUnits.PressureLevels[level]
+		nd := latest.Hourly.PressureLevels[level]
+		if ou == nil || nu == nil || nd == nil {
+			continue
+		}
+		// Pressure levels share the hourly timestamps.
+		od := &levelSeries{Time: previous.Hourly.Time, PressureLevel: previous.Hourly.PressureLevels[level]}
+		pairs = append(pairs, pair{"hourly", ou, od, nu, &levelSeries{Time: latest.Hourly.Time, PressureLevel: nd}, fmt.Sprintf("_%dhPa", level)})
+	}
+	pairs = append(pairs, pair{"daily", &previous.DailyUnits, &previous.Daily, &latest.DailyUnits, &latest.Daily, ""})
+
+	var changes []Change
+	for _, p := range pairs {
+		c, err := diffSection(p.name, p.oldUnits, p.oldVal, p.newUnits, p.newVal, p.suffix, thresholds)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", p.name, err)
+		}
+		changes = append(changes, c...)
+	}
+	return changes, nil
+}
+
+// levelSeries pairs a pressure level with the hourly timestamps.
+type levelSeries struct {
+	Time []string `json:"time"`
+	*PressureLevel
+}
+
+// numericSeries holds one numeric field of a section as float64 values.
+type numericSeries struct {
+	name   string
+	values []float64
+}
+
+// sectionSeries returns the timestamps and numeric fields of data, a pointer
+// to a section struct. Scalar sections such as Current are returned as
+// series of length one.
+func sectionSeries(data any) ([]string, []numericSeries) {
+	var times []string
+	var fields []numericSeries
+	var walk func(v reflect.Value)
+	walk = func(v reflect.Value) {
+		for i := 0; i < v.NumField(); i++ {
+			f, field := v.Type().Field(i), v.Field(i)
+			if f.Anonymous && field.Kind() == reflect.Pointer {
+				if !field.IsNil() {
+					walk(field.Elem())
+				}
+				continue
+			}
+			name := jsonName(f)
+			switch {
+			case name == "time" && field.Kind() == reflect.String:
+				times = []string{field.String()}
+			case name == "time" && field.Type() == reflect.TypeOf([]string(nil)):
+				times = field.Interface().([]string)
+			case name == "interval" || name == "" || name == "-":
+			case field.Kind() == reflect.Float64:
+				fields = append(fields, numericSeries{name, []float64{field.Float()}})
+			case field.Kind() == reflect.Int:
+				fields = append(fields, numericSeries{name, []float64{float64(field.Int())}})
+			case field.Kind() == reflect.Slice && field.Len() > 0:
+				if values, ok := seriesValues(v.Addr().Interface(), name); ok {
+					fields = append(fields, numericSeries{name, values})
+				}
+			}
+		}
+	}
+	walk(reflect.ValueOf(data).Elem())
+	return times, fields
+}
+
+// unitLabels returns the string fields of units, a pointer to a *Units
+// struct, keyed by JSON name.
+func unitLabels(units any) map[string]string {
+	v := reflect.ValueOf(units).Elem()
+	labels := map[string]string{}
+	for i := 0; i < v.NumField(); i++ {
+		if v.Field(i).Kind() == reflect.String {
+			labels[jsonName(v.Type().Field(i))] = v.Field(i).String()
+		}
+	}
+	return labels
+}
+
+// diffSection compares one section of two responses.
+func diffSection(section string, oldUnits, oldData, newUnits, newData any, suffix string, thresholds Thresholds) ([]Change, error) {
+	oldTimes, oldFields := sectionSeries(oldData)
+	newTimes, newFields := sectionSeries(newData)
+	oldLabels, newLabels := unitLabels(oldUnits), unitLabels(newUnits)
+
+	// Current data is compared regardless of its time.
+	scalar := section == "current"
+	newIndex := make(map[string]int, len(newTimes))
+	for i, ts := range newTimes {
+		newIndex[ts] = i
+	}
+
+	newByName := make(map[string][]float64, len(newFields))
+	for _, f := range newFields {
+		newByName[f.name] = f.values
+	}
+
+	var changes []Change
+	for _, of := range oldFields {
+		nv, ok := newByName[of.name]
+		if !ok {
+			continue
+		}
+		metric := Metric(of.name + suffix)
+		unit, _ := ParseUnit(oldLabels[of.name])
+		from, _ := ParseUnit(newLabels[of.name])
+		convert := from != unit && oldLabels[of.name] != "" && newLabels[of.name] != ""
+		isCode := of.name == "weather_code" || unit.Dimension() == DimensionWeatherCode
+		q := classify(of.name, unit)
+		isPrecipitation := q == precipitationQuantity || q == snowfallQuantity
+		threshold := thresholds[metric]
+
+		for i, ov := range of.values {
+			j, ts := i, ""
+			if scalar {
+				if len(newTimes) > 0 {
+					ts = newTimes[0]
+				}
+			} else {
+				if i >= len(oldTimes) {
+					break
+				}
+				ts = oldTimes[i]
+				if j, ok = newIndex[ts]; !ok {
+					continue
+				}
+			}
+			if j >= len(nv) {
+				continue
+			}
+			n := nv[j]
+			if convert {
+				var err error
+				if n, err = metric.Convert(n, from, unit); err != nil {
+					return nil, fmt.Errorf("%s: %w", of.name, err)
+				}
+			}
+
+			change := Change{Section: section, Metric: metric, Time: ts, Old: ov, New: n, Unit: unit}
+			switch {
+			case isCode:
+				if CodeGroup(int(ov)) == CodeGroup(int(n)) {
+					continue
+				}
+				change.Kind, change.Unit = ChangeWeatherGroup, UnitWMOCode
+			case isPrecipitation && ov == 0 && n > 0:
+				change.Kind = ChangeAppeared
+			case isPrecipitation && ov > 0 && n == 0:
+				change.Kind = ChangeDisappeared
+			case ov != n && math.Abs(n-ov) >= threshold:
+				change.Kind = ChangeValue
+			default:
+				continue
+			}
+			changes = append(changes, change)
+		}
+	}
+	return changes, nil
+}
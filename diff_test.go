@@ -0,0 +1,142 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openmeteogo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	previous := &WeatherData{
+		CurrentUnits: CurrentUnits{Time: "iso8601", Temperature2m: "°C", WeatherCode: "wmo code"},
+		Current:      Current{Time: "2025-01-01T10:00", Temperature2m: 5, WeatherCode: 61},
+		HourlyUnits:  HourlyUnits{Time: "iso8601", Temperature2m: "°C", Precipitation: "mm", WeatherCode: "wmo code"},
+		Hourly: Hourly{
+			Time:          []string{"2025-01-01T10:00", "2025-01-01T11:00", "2025-01-01T12:00"},
+			Temperature2m: []float64{5, 6, 7},
+			Precipitation: []float64{0, 1.2, 0.4},
+			WeatherCode:   []int{3, 61, 61},
+		},
+		DailyUnits: DailyUnits{Time: "iso8601", Temperature2mMax: "°C", Sunrise: "iso8601"},
+		Daily: Daily{
+			Time:             []string{"2025-01-01", "2025-01-02"},
+			Temperature2mMax: []float64{8, 9},
+			Sunrise:          []string{"2025-01-01T08:00", "2025-01-02T08:00"},
+		},
+	}
+	latest := &WeatherData{
+		CurrentUnits: CurrentUnits{Time: "iso8601", Temperature2m: "°C", WeatherCode: "wmo code"},
+		Current:      Current{Time: "2025-01-01T11:00", Temperature2m: 5.5, WeatherCode: 63},
+		HourlyUnits:  HourlyUnits{Time: "iso8601", Temperature2m: "°C", Precipitation: "mm", WeatherCode: "wmo code"},
+		Hourly: Hourly{
+			// The window moved on by an hour.
+			Time:          []string{"2025-01-01T11:00", "2025-01-01T12:00", "2025-01-01T13:00"},
+			Temperature2m: []float64{6.2, 10, 2},
+			Precipitation: []float64{0, 0.5, 3},
+			WeatherCode:   []int{65, 95, 0},
+		},
+		DailyUnits: DailyUnits{Time: "iso8601", Temperature2mMax: "°C", Sunrise: "iso8601"},
+		Daily: Daily{
+			Time:             []string{"2025-01-01", "2025-01-02"},
+			Temperature2mMax: []float64{8, 12},
+			Sunrise:          []string{"2025-01-01T08:01", "2025-01-02T08:01"},
+		},
+	}
+
+	got, err := Diff(previous, latest, Thresholds{Temperature2m: 1})
+	require.NoError(t, err)
+
+	want := []Change{
+		{Section: "hourly", Metric: Temperature2m, Time: "2025-01-01T12:00", Kind: ChangeValue, Old: 7, New: 10, Unit: UnitCelsius},
+		{Section: "hourly", Metric: Precipitation, Time: "2025-01-01T11:00", Kind: ChangeDisappeared, Old: 1.2, New: 0, Unit: UnitMillimetre},
+		{Section: "hourly", Metric: Precipitation, Time: "2025-01-01T12:00", Kind: ChangeValue, Old: 0.4, New: 0.5, Unit: UnitMillimetre},
+		{Section: "hourly", Metric: WeatherCode, Time: "2025-01-01T12:00", Kind: ChangeWeatherGroup, Old: 61, New: 95, Unit: UnitWMOCode},
+		{Section: "daily", Metric: Temperature2mMax, Time: "2025-01-02", Kind: ChangeValue, Old: 9, New: 12, Unit: UnitCelsius},
+	}
+	assert.Equal(t, want, got)
+	assert.InDelta(t, 3, got[0].Delta(), 1e-9)
+	assert.Equal(t, "hourly temperature_2m at 2025-01-01T12:00 changed: 7 -> 10 °C", got[0].String())
+	assert.Equal(t, "hourly weather_code at 2025-01-01T12:00 weather_group: rain -> thunderstorm", got[3].String())
+}
+
+func TestDiff_Current(t *testing.T) {
+	previous := &WeatherData{
+		CurrentUnits: CurrentUnits{Temperature2m: "°C", Precipitation: "mm", WeatherCode: "wmo code"},
+		Current:      Current{Time: "2025-01-01T10:00", Temperature2m: 5, WeatherCode: 0},
+	}
+	latest := &WeatherData{
+		CurrentUnits: CurrentUnits{Temperature2m: "°C", Precipitation: "mm", WeatherCode: "wmo code"},
+		Current:      Current{Time: "2025-01-01T10:15", Temperature2m: 5, Precipitation: 0.2, WeatherCode: 61},
+	}
+
+	got, err := Diff(previous, latest, nil)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, Change{Section: "current", Metric: Precipitation, Time: "2025-01-01T10:15", Kind: ChangeAppeared, New: 0.2, Unit: UnitMillimetre}, got[0])
+	assert.Equal(t, ChangeWeatherGroup, got[1].Kind)
+}
+
+func TestDiff_UnitsAndPressureLevels(t *testing.T) {
+	previous := &WeatherData{
+		HourlyUnits: HourlyUnits{
+			Temperature2m:  "°C",
+			PressureLevels: map[int]*PressureLevelUnits{850: {Temperature: "°C"}},
+		},
+		Hourly: Hourly{
+			Time:           []string{"2025-01-01T00:00"},
+			Temperature2m:  []float64{0},
+			PressureLevels: map[int]*PressureLevel{850: {Temperature: []float64{-5}}},
+		},
+	}
+	latest := &WeatherData{
+		HourlyUnits: HourlyUnits{
+			Temperature2m:  "°F",
+			PressureLevels: map[int]*PressureLevelUnits{850: {Temperature: "°C"}},
+		},
+		Hourly: Hourly{
+			Time:           []string{"2025-01-01T00:00"},
+			Temperature2m:  []float64{32},
+			PressureLevels: map[int]*PressureLevel{850: {Temperature: []float64{-8}}},
+		},
+	}
+
+	got, err := Diff(previous, latest, nil)
+	require.NoError(t, err)
+	require.Len(t, got, 1, "32°F is no change from 0°C")
+	assert.Equal(t, Metric("temperature_850hPa"), got[0].Metric)
+	assert.Equal(t, -3.0, got[0].Delta())
+
+	latest.HourlyUnits.Temperature2m = "km/h"
+	_, err = Diff(previous, latest, nil)
+	assert.Error(t, err)
+}
+
+func TestDiff_Nil(t *testing.T) {
+	wd := loadWeatherData(t, "test_data/all_params.json")
+	_, err := Diff(nil, wd, nil)
+	assert.EqualError(t, err, "nil weather data")
+	_, err = Diff(wd, nil, nil)
+	assert.EqualError(t, err, "nil weather data")
+}
+
+func TestDiff_NoChanges(t *testing.T) {
+	wd := loadWeatherData(t, "test_data/all_params.json")
+	got, err := Diff(wd, loadWeatherData(t, "test_data/all_params.json"), nil)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
@@ -0,0 +1,270 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package energy computes heating and cooling degree days from Open-Meteo
+// weather data, and compares forecast degree days with the multi-year
+// average from the archive API.
+package energy
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/tpryan/openmeteogo"
+)
+
+// Kind selects heating or cooling degree days.
+type Kind string
+
+const (
+	// Heating degree days measure how far the temperature is below the base.
+	Heating Kind = "heating_degree_days"
+	// Cooling degree days measure how far the temperature is above the base.
+	Cooling Kind = "cooling_degree_days"
+)
+
+// Config describes how degree days are computed.
+type Config struct {
+	// Kind is Heating or Cooling.
+	Kind Kind
+	// Base is the base temperature, commonly 18°C or 65°F.
+	Base float64
+	// Unit is the unit of Base and of the result. It defaults to °C.
+	Unit openmeteogo.Unit
+}
+
+func (c Config) validate() (Config, error) {
+	if c.Kind != Heating && c.Kind != Cooling {
+		return c, fmt.Errorf("unsupported degree day kind: %q", c.Kind)
+	}
+	if c.Unit == "" {
+		c.Unit = openmeteogo.UnitCelsius
+	}
+	if c.Unit.Dimension() != openmeteogo.DimensionTemperature {
+		return c, fmt.Errorf("unsupported unit: %s", c.Unit)
+	}
+	return c, nil
+}
+
+// degrees returns the degree days for a temperature t in the config unit.
+func (c Config) degrees(t float64) float64 {
+	if c.Kind == Heating {
+		return math.Max(0, c.Base-t)
+	}
+	return math.Max(0, t-c.Base)
+}
+
+// Series holds degree days per day.
+type Series struct {
+	// Kind is Heating or Cooling.
+	Kind Kind
+	// Unit is the temperature unit of the degree days.
+	Unit openmeteogo.Unit
+	// Time holds the dates of Values, formatted as YYYY-MM-DD.
+	Time []string
+	// Values holds the degree days for each day.
+	Values []float64
+	// Cumulative holds the running total of Values.
+	Cumulative []float64
+}
+
+// Total returns the degree days over the whole series.
+func (s *Series) Total() float64 {
+	if len(s.Cumulative) == 0 {
+		return 0
+	}
+	return s.Cumulative[len(s.Cumulative)-1]
+}
+
+// DegreeDays returns the heating or cooling degree days for each day in
+// wd. With daily temperature_2m_max and temperature_2m_min it uses the mean
+// of the two, the convention used by most energy markets. Otherwise it
+// averages the degree days of each hourly temperature_2m value.
+func DegreeDays(wd *openmeteogo.WeatherData, cfg Config) (*Series, error) {
+	cfg, err := cfg.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Series{Kind: cfg.Kind, Unit: cfg.Unit}
+	if d := wd.Daily; len(d.Temperature2mMax) > 0 && len(d.Temperature2mMin) > 0 {
+		tmax, err := wd.DailyValues(openmeteogo.Temperature2mMax, cfg.Unit)
+		if err != nil {
+			return nil, err
+		}
+		tmin, err := wd.DailyValues(openmeteogo.Temperature2mMin, cfg.Unit)
+		if err != nil {
+			return nil, err
+		}
+		s.Time = d.Time
+		s.Values = make([]float64, len(d.Time))
+		for i := range s.Values {
+			s.Values[i] = cfg.degrees((tmax[i] + tmin[i]) / 2)
+		}
+	} else {
+		temps, err := wd.HourlyValues(openmeteogo.Temperature2m, cfg.Unit)
+		if err != nil {
+			return nil, err
+		}
+		for i, t := range temps {
+			temps[i] = cfg.degrees(t)
+		}
+		days, err := openmeteogo.ResampleSeries(wd.Hourly.Time, temps, openmeteogo.Days(0), openmeteogo.AggregateMean)
+		if err != nil {
+			return nil, err
+		}
+		for _, day := range days {
+			s.Time = append(s.Time, day.Start.Format(openmeteogo.DateFormat))
+			s.Values = append(s.Values, day.Values[openmeteogo.AggregateMean.String()])
+		}
+	}
+
+	s.Cumulative = make([]float64, len(s.Values))
+	total := 0.0
+	for i, v := range s.Values {
+		total += v
+		s.Cumulative[i] = total
+	}
+	return s, nil
+}
+
+// Anomaly compares forecast degree days with the average for the same
+// calendar days over previous years.
+type Anomaly struct {
+	// Kind is Heating or Cooling.
+	Kind Kind
+	// Unit is the temperature unit of the degree days.
+	Unit openmeteogo.Unit
+	// Years is the number of years averaged into Normal.
+	Years int
+	// Time holds the forecast dates, formatted as YYYY-MM-DD.
+	Time []string
+	// Forecast holds the forecast degree days for each day.
+	Forecast []float64
+	// Normal holds the average degree days for the same calendar day.
+	Normal []float64
+	// Anomaly holds Forecast minus Normal for each day.
+	Anomaly []float64
+}
+
+// Total returns the forecast, normal and anomaly degree days summed over
+// the forecast period.
+func (a *Anomaly) Total() (forecast, normal, anomaly float64) {
+	for i := range a.Time {
+		forecast += a.Forecast[i]
+		normal += a.Normal[i]
+		anomaly += a.Anomaly[i]
+	}
+	return forecast, normal, anomaly
+}
+
+// FetchAnomaly fetches the daily forecast for the location in o, and the
+// same calendar days for each of the previous years from the archive API,
+// and returns the forecast degree days against their multi-year average.
+// Latitude, Longitude, Timezone, ForcastDays and Models in o are used;
+// o itself is not modified. At most one model may be given, as the API
+// returns the data of several models separately.
+func FetchAnomaly(c *openmeteogo.Client, o *openmeteogo.Options, cfg Config, years int) (*Anomaly, error) {
+	cfg, err := cfg.validate()
+	if err != nil {
+		return nil, err
+	}
+	if years < 1 {
+		return nil, fmt.Errorf("years must be at least 1, got %d", years)
+	}
+	if len(o.Models) > 1 {
+		return nil, fmt.Errorf("one model at most is supported, got %d", len(o.Models))
+	}
+	daily := openmeteogo.Metrics{openmeteogo.Temperature2mMax, openmeteogo.Temperature2mMin}
+
+	fo := openmeteogo.Options{
+		Latitude:     o.Latitude,
+		Longitude:    o.Longitude,
+		Timezone:     o.Timezone,
+		ForcastDays:  o.ForcastDays,
+		Models:       o.Models,
+		DailyMetrics: daily,
+	}
+	fwd, err := c.Get(&fo)
+	if err != nil {
+		return nil, fmt.Errorf("fetching forecast: %w", err)
+	}
+	forecast, err := DegreeDays(fwd, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("forecast: %w", err)
+	}
+	if len(forecast.Time) == 0 {
+		return nil, fmt.Errorf("forecast: no daily data")
+	}
+
+	first, err := time.Parse(openmeteogo.DateFormat, forecast.Time[0])
+	if err != nil {
+		return nil, fmt.Errorf("parsing forecast date: %w", err)
+	}
+	last, err := time.Parse(openmeteogo.DateFormat, forecast.Time[len(forecast.Time)-1])
+	if err != nil {
+		return nil, fmt.Errorf("parsing forecast date: %w", err)
+	}
+
+	// One archive request covers every year; days outside the forecast's
+	// calendar window are ignored below.
+	ao := openmeteogo.Options{
+		Latitude:     o.Latitude,
+		Longitude:    o.Longitude,
+		Timezone:     o.Timezone,
+		Start:        first.AddDate(-years, 0, 0),
+		End:          last.AddDate(-1, 0, 0),
+		DailyMetrics: daily,
+	}
+	awd, err := c.Get(&ao)
+	if err != nil {
+		return nil, fmt.Errorf("fetching archive: %w", err)
+	}
+	archive, err := DegreeDays(awd, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("archive: %w", err)
+	}
+	history := make(map[string]float64, len(archive.Time))
+	for i, date := range archive.Time {
+		history[date] = archive.Values[i]
+	}
+
+	a := &Anomaly{Kind: cfg.Kind, Unit: cfg.Unit, Years: years, Time: forecast.Time, Forecast: forecast.Values}
+	for i, date := range forecast.Time {
+		day, err := time.Parse(openmeteogo.DateFormat, date)
+		if err != nil {
+			return nil, fmt.Errorf("parsing forecast date: %w", err)
+		}
+		sum, n := 0.0, 0
+		for y := 1; y <= years; y++ {
+			past := day.AddDate(-y, 0, 0)
+			// AddDate normalises 29 February to 1 March; use 28 February.
+			if day.Month() == time.February && day.Day() == 29 && past.Month() == time.March {
+				past = past.AddDate(0, 0, -1)
+			}
+			if v, ok := history[past.Format(openmeteogo.DateFormat)]; ok {
+				sum += v
+				n++
+			}
+		}
+		if n == 0 {
+			return nil, fmt.Errorf("no archive data for %s", date)
+		}
+		normal := sum / float64(n)
+		a.Normal = append(a.Normal, normal)
+		a.Anomaly = append(a.Anomaly, forecast.Values[i]-normal)
+	}
+	return a, nil
+}
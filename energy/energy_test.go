@@ -0,0 +1,189 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package energy
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tpryan/openmeteogo"
+)
+
+func dailyData() *openmeteogo.WeatherData {
+	return &openmeteogo.WeatherData{
+		DailyUnits: openmeteogo.DailyUnits{Temperature2mMax: "°C", Temperature2mMin: "°C"},
+		Daily: openmeteogo.Daily{
+			Time:             []string{"2025-01-01", "2025-01-02", "2025-01-03"},
+			Temperature2mMax: []float64{10, 20, 30},
+			Temperature2mMin: []float64{0, 12, 24},
+		},
+	}
+}
+
+func TestDegreeDays(t *testing.T) {
+	hourly := &openmeteogo.WeatherData{
+		HourlyUnits: openmeteogo.HourlyUnits{Temperature2m: "°F"},
+		Hourly: openmeteogo.Hourly{
+			Time:          []string{"2025-01-01T00:00", "2025-01-01T12:00", "2025-01-02T00:00"},
+			Temperature2m: []float64{55, 75, 60},
+		},
+	}
+
+	tests := map[string]struct {
+		wd   *openmeteogo.WeatherData
+		cfg  Config
+		want []float64
+		cum  []float64
+	}{
+		"heating daily": {
+			wd:   dailyData(),
+			cfg:  Config{Kind: Heating, Base: 18},
+			want: []float64{13, 2, 0},
+			cum:  []float64{13, 15, 15},
+		},
+		"cooling daily": {
+			wd:   dailyData(),
+			cfg:  Config{Kind: Cooling, Base: 18},
+			want: []float64{0, 0, 9},
+			cum:  []float64{0, 0, 9},
+		},
+		"heating daily in fahrenheit": {
+			wd:   dailyData(),
+			cfg:  Config{Kind: Heating, Base: 65, Unit: openmeteogo.UnitFahrenheit},
+			want: []float64{24, 4.2, 0},
+			cum:  []float64{24, 28.2, 28.2},
+		},
+		"heating hourly": {
+			wd:   hourly,
+			cfg:  Config{Kind: Heating, Base: 65, Unit: openmeteogo.UnitFahrenheit},
+			want: []float64{5, 5},
+			cum:  []float64{5, 10},
+		},
+		"cooling hourly": {
+			wd:   hourly,
+			cfg:  Config{Kind: Cooling, Base: 65, Unit: openmeteogo.UnitFahrenheit},
+			want: []float64{5, 0},
+			cum:  []float64{5, 5},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := DegreeDays(tc.wd, tc.cfg)
+			require.NoError(t, err)
+			assert.Equal(t, tc.cfg.Kind, got.Kind)
+			assert.InDeltaSlice(t, tc.want, got.Values, 1e-9)
+			assert.InDeltaSlice(t, tc.cum, got.Cumulative, 1e-9)
+			assert.InDelta(t, tc.cum[len(tc.cum)-1], got.Total(), 1e-9)
+		})
+	}
+}
+
+func TestDegreeDays_Errors(t *testing.T) {
+	_, err := DegreeDays(dailyData(), Config{Base: 18})
+	assert.Error(t, err, "missing kind")
+	_, err = DegreeDays(dailyData(), Config{Kind: Heating, Unit: openmeteogo.UnitKmh})
+	assert.Error(t, err, "not a temperature")
+	_, err = DegreeDays(&openmeteogo.WeatherData{}, Config{Kind: Heating})
+	assert.Error(t, err, "no data")
+}
+
+// rewriteTransport sends every request to a test server, recording the
+// requested URLs.
+type rewriteTransport struct {
+	target *url.URL
+	urls   []*url.URL
+}
+
+func (rt *rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.urls = append(rt.urls, req.URL)
+	r := req.Clone(req.Context())
+	r.URL.Scheme = rt.target.Scheme
+	r.URL.Host = rt.target.Host
+	r.Host = req.URL.Host
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func TestFetchAnomaly(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		var daily openmeteogo.Daily
+		if strings.HasPrefix(req.Host, "archive-") {
+			// Each past year is one degree colder than the next.
+			start, _ := time.Parse(openmeteogo.DateFormat, req.URL.Query().Get("start_date"))
+			end, _ := time.Parse(openmeteogo.DateFormat, req.URL.Query().Get("end_date"))
+			for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+				temp := 10 - float64(2026-d.Year())
+				daily.Time = append(daily.Time, d.Format(openmeteogo.DateFormat))
+				daily.Temperature2mMax = append(daily.Temperature2mMax, temp)
+				daily.Temperature2mMin = append(daily.Temperature2mMin, temp)
+			}
+		} else {
+			daily.Time = []string{"2026-02-27", "2026-02-28", "2026-03-01"}
+			daily.Temperature2mMax = []float64{12, 8, 10}
+			daily.Temperature2mMin = []float64{4, 8, 10}
+		}
+		json.NewEncoder(rw).Encode(openmeteogo.WeatherData{
+			DailyUnits: openmeteogo.DailyUnits{Time: "iso8601", Temperature2mMax: "°C", Temperature2mMin: "°C"},
+			Daily:      daily,
+		})
+	}))
+	defer server.Close()
+
+	target, _ := url.Parse(server.URL)
+	transport := &rewriteTransport{target: target}
+	client := openmeteogo.NewClient()
+	client.HTTPClient = &http.Client{Transport: transport}
+
+	opts := openmeteogo.NewOptionsBuilder().Latitude(51.5).Longitude(-0.12).ForcastDays(3).Build()
+	got, err := FetchAnomaly(client, opts, Config{Kind: Heating, Base: 18}, 3)
+	require.NoError(t, err)
+
+	require.Len(t, transport.urls, 2)
+	assert.Equal(t, "api.open-meteo.com", transport.urls[0].Host)
+	assert.Equal(t, "archive-api.open-meteo.com", transport.urls[1].Host)
+	assert.Equal(t, "2023-02-27", transport.urls[1].Query().Get("start_date"))
+	assert.Equal(t, "2025-03-01", transport.urls[1].Query().Get("end_date"))
+
+	assert.Equal(t, Heating, got.Kind)
+	assert.Equal(t, 3, got.Years)
+	assert.Equal(t, []string{"2026-02-27", "2026-02-28", "2026-03-01"}, got.Time)
+	assert.InDeltaSlice(t, []float64{10, 10, 8}, got.Forecast, 1e-9)
+	// The three past years had 9, 10 and 11 degree days each day.
+	assert.InDeltaSlice(t, []float64{10, 10, 10}, got.Normal, 1e-9)
+	assert.InDeltaSlice(t, []float64{0, 0, -2}, got.Anomaly, 1e-9)
+
+	forecast, normal, anomaly := got.Total()
+	assert.InDelta(t, 28, forecast, 1e-9)
+	assert.InDelta(t, 30, normal, 1e-9)
+	assert.InDelta(t, -2, anomaly, 1e-9)
+
+	_, err = FetchAnomaly(client, opts, Config{Kind: Heating}, 0)
+	assert.Error(t, err)
+
+	transport.urls = nil
+	models := openmeteogo.NewOptionsBuilder().Latitude(51.5).Longitude(-0.12).
+		Models([]string{"icon_seamless", "gfs_seamless"}).Build()
+	_, err = FetchAnomaly(client, models, Config{Kind: Heating, Base: 18}, 3)
+	assert.EqualError(t, err, "one model at most is supported, got 2")
+	assert.Empty(t, transport.urls, "nothing is fetched")
+}
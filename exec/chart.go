@@ -0,0 +1,517 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	openmeteogo "github.com/tpryan/openmeteogo"
+)
+
+const (
+	// lineHeight and barHeight are the heights of line and bar charts, in
+	// rows.
+	lineHeight = 8
+	barHeight  = 4
+	// labelWidth is the width of the value labels left of the charts.
+	labelWidth = 7
+	// defaultWidth is the chart width when the terminal's is not known.
+	defaultWidth = 80
+	// maxStretch is the most columns a single time step is drawn across.
+	maxStretch = 3
+)
+
+// charset holds the characters one chart style is drawn with.
+type charset struct {
+	// spark holds the sparkline levels, lowest first.
+	spark []string
+	// bar holds the partly filled bar cells, emptiest first, ending with a
+	// full cell.
+	bar []string
+	// The line chart segments: flat, up from the left, up to the right,
+	// down from the left, down to the right, and vertical.
+	flat, upFrom, upTo, downFrom, downTo, vertical string
+	// axis marks labelled rows, edge marks other rows, and corner and rule
+	// draw the time axis.
+	axis, edge, corner, rule string
+	// arrows point where the wind blows to, for winds from N, NE, E, SE,
+	// S, SW, W and NW.
+	arrows [8]string
+	// symbols mark the weather code groups.
+	symbols map[openmeteogo.WeatherGroup]string
+	unknown string
+}
+
+var unicodeChars = charset{
+	spark:    []string{"▁", "▂", "▃", "▄", "▅", "▆", "▇", "█"},
+	bar:      []string{" ", "▁", "▂", "▃", "▄", "▅", "▆", "▇", "█"},
+	flat:     "─",
+	upFrom:   "╯",
+	upTo:     "╭",
+	downFrom: "╮",
+	downTo:   "╰",
+	vertical: "│",
+	axis:     "┤",
+	edge:     "│",
+	corner:   "└",
+	rule:     "─",
+	arrows:   [8]string{"↓", "↙", "←", "↖", "↑", "↗", "→", "↘"},
+	symbols: map[openmeteogo.WeatherGroup]string{
+		openmeteogo.GroupClear:        "☀",
+		openmeteogo.GroupCloudy:       "☁",
+		openmeteogo.GroupFog:          "≡",
+		openmeteogo.GroupDrizzle:      "∴",
+		openmeteogo.GroupRain:         "☂",
+		openmeteogo.GroupFreezingRain: "⁂",
+		openmeteogo.GroupSnow:         "❄",
+		openmeteogo.GroupThunderstorm: "ϟ",
+	},
+	unknown: "?",
+}
+
+var asciiChars = charset{
+	spark:    []string{"_", ".", "-", "~", "=", "+", "*", "#"},
+	bar:      []string{" ", ".", ".", ".", ":", ":", ":", ":", "#"},
+	flat:     "-",
+	upFrom:   "/",
+	upTo:     "/",
+	downFrom: "\\",
+	downTo:   "\\",
+	vertical: "|",
+	axis:     "+",
+	edge:     "|",
+	corner:   "+",
+	rule:     "-",
+	arrows:   [8]string{"v", "/", "<", "\\", "^", "/", ">", "\\"},
+	symbols: map[openmeteogo.WeatherGroup]string{
+		openmeteogo.GroupClear:        "o",
+		openmeteogo.GroupCloudy:       "c",
+		openmeteogo.GroupFog:          "=",
+		openmeteogo.GroupDrizzle:      ",",
+		openmeteogo.GroupRain:         "/",
+		openmeteogo.GroupFreezingRain: "~",
+		openmeteogo.GroupSnow:         "*",
+		openmeteogo.GroupThunderstorm: "!",
+	},
+	unknown: "?",
+}
+
+// groupOrder is the order weather groups are listed in chart legends.
+var groupOrder = []openmeteogo.WeatherGroup{
+	openmeteogo.GroupClear,
+	openmeteogo.GroupCloudy,
+	openmeteogo.GroupFog,
+	openmeteogo.GroupDrizzle,
+	openmeteogo.GroupRain,
+	openmeteogo.GroupFreezingRain,
+	openmeteogo.GroupSnow,
+	openmeteogo.GroupThunderstorm,
+}
+
+// Metrics drawn as something other than a sparkline, in order of
+// preference for each role.
+var (
+	lineMetrics      = []string{"temperature_2m", "temperature_2m_max", "temperature_2m_mean"}
+	barMetrics       = []string{"precipitation", "precipitation_sum", "precipitation_mean"}
+	directionMetrics = []string{"wind_direction_10m", "wind_direction_10m_dominant"}
+	speedMetrics     = []string{"wind_speed_10m", "wind_speed_10m_max", "wind_speed_10m_mean"}
+	codeMetrics      = []string{"weather_code"}
+)
+
+// chartOptions configures chart output.
+type chartOptions struct {
+	// width is the width of the terminal, in columns.
+	width int
+	// ascii draws with ASCII characters only.
+	ascii bool
+}
+
+// charts returns the chart options given by the flags, or nil if charts
+// were not asked for. The width defaults to $COLUMNS, then 80 columns.
+func (f *requestFlags) charts(getenv func(string) string) (*chartOptions, error) {
+	if !f.chart {
+		return nil, nil
+	}
+	if f.format != "table" {
+		return nil, fmt.Errorf("--chart needs the table format, got %q", f.format)
+	}
+	o := &chartOptions{width: f.width, ascii: f.ascii}
+	if o.width == 0 {
+		o.width, _ = strconv.Atoi(getenv("COLUMNS"))
+	}
+	if o.width <= 0 {
+		o.width = defaultWidth
+	}
+	return o, nil
+}
+
+// series is a numeric column sampled to the chart's columns. Missing values
+// are NaN.
+type series struct {
+	column
+	values []float64
+}
+
+func (s series) bounds() (lo, hi float64, ok bool) {
+	lo, hi = math.Inf(1), math.Inf(-1)
+	for _, v := range s.values {
+		if !math.IsNaN(v) {
+			lo, hi = min(lo, v), max(hi, v)
+		}
+	}
+	return lo, hi, !math.IsInf(lo, 0)
+}
+
+// chart draws the numeric columns of a section against time.
+type chart struct {
+	chars   charset
+	times   []string
+	stretch int
+	lines   []string
+}
+
+// writeChart draws a section as charts: a line chart of temperature, bars
+// of precipitation, wind arrows, weather symbols and sparklines of the other
+// metrics. Sections with more time steps than fit in the width are sampled,
+// summing precipitation and keeping the most severe weather code.
+func writeChart(w io.Writer, s section, o chartOptions) error {
+	chars := unicodeChars
+	if o.ascii {
+		chars = asciiChars
+	}
+	width := max(o.width-labelWidth-1, 1)
+
+	step := max((len(s.time)+width-1)/width, 1)
+	c := &chart{chars: chars, stretch: 1}
+	for i := 0; i < len(s.time); i += step {
+		c.times = append(c.times, s.time[i])
+	}
+	if len(c.times) > 0 {
+		c.stretch = min(max(width/len(c.times), 1), maxStretch)
+	}
+
+	var all []series
+	for _, col := range s.columns {
+		if sr, ok := sample(col, len(s.time), step); ok {
+			all = append(all, sr)
+		}
+	}
+	take := func(names []string) (series, bool) {
+		for _, n := range names {
+			if i := slices.IndexFunc(all, func(sr series) bool { return sr.name == n }); i >= 0 {
+				sr := all[i]
+				all = slices.Delete(all, i, i+1)
+				return sr, true
+			}
+		}
+		return series{}, false
+	}
+
+	if sr, ok := take(lineMetrics); ok {
+		c.line(sr)
+	}
+	if sr, ok := take(barMetrics); ok {
+		c.bars(sr)
+	}
+	direction, hasDirection := take(directionMetrics)
+	speed, hasSpeed := take(speedMetrics)
+	if hasDirection || hasSpeed {
+		c.wind(direction, hasDirection, speed, hasSpeed)
+	}
+	if sr, ok := take(codeMetrics); ok {
+		c.weather(sr)
+	}
+	for _, sr := range all {
+		c.sparkline(sr)
+	}
+	c.axis()
+
+	_, err := io.WriteString(w, strings.Join(c.lines, "\n")+"\n")
+	return err
+}
+
+// sample reduces a numeric column to every step-th value. Precipitation is
+// summed over each step, and weather codes keep the highest, most severe,
+// code.
+func sample(col column, n, step int) (series, bool) {
+	values := make([]float64, n)
+	numeric := false
+	for i := range values {
+		values[i] = math.NaN()
+		if i < len(col.values) {
+			if v, ok := col.values[i].(float64); ok {
+				values[i] = v
+				numeric = true
+			}
+		}
+	}
+	if !numeric {
+		return series{}, false
+	}
+
+	sr := series{column: col}
+	for i := 0; i < n; i += step {
+		v := values[i]
+		for _, u := range values[i+1 : min(i+step, n)] {
+			switch {
+			case math.IsNaN(u):
+			case math.IsNaN(v):
+				v = u
+			case slices.Contains(barMetrics, col.name):
+				v += u
+			case slices.Contains(codeMetrics, col.name):
+				v = max(v, u)
+			}
+		}
+		sr.values = append(sr.values, v)
+	}
+	return sr, true
+}
+
+// title adds the heading of one chart.
+func (c *chart) title(sr series, extra string) {
+	t := sr.header()
+	if extra != "" {
+		t += "  " + extra
+	}
+	c.lines = append(c.lines, t)
+}
+
+// row adds a chart row with an optional value label.
+func (c *chart) row(label string, cells []string) {
+	edge := c.chars.edge
+	if label != "" {
+		edge = c.chars.axis
+	}
+	c.lines = append(c.lines, fmt.Sprintf("%*s%s%s", labelWidth, label, edge, strings.TrimRight(strings.Join(cells, ""), " ")))
+}
+
+// cells returns an empty row of cells.
+func (c *chart) cells() []string {
+	cells := make([]string, len(c.times)*c.stretch)
+	for i := range cells {
+		cells[i] = " "
+	}
+	return cells
+}
+
+// set sets the first cell of time step i.
+func (c *chart) set(cells []string, i int, s string) {
+	cells[i*c.stretch] = s
+}
+
+// line adds a line chart. A series that does not change is drawn as a
+// single row.
+func (c *chart) line(sr series) {
+	lo, hi, ok := sr.bounds()
+	if !ok {
+		return
+	}
+	c.title(sr, "")
+	height := lineHeight
+	if hi == lo {
+		height = 1
+	}
+	level := func(v float64) int {
+		if hi == lo {
+			return 0
+		}
+		return int(math.Round((v - lo) / (hi - lo) * float64(height-1)))
+	}
+
+	grid := make([][]string, height)
+	for r := range grid {
+		grid[r] = c.cells()
+	}
+	prev := -1
+	for i, v := range sr.values {
+		if math.IsNaN(v) {
+			prev = -1
+			continue
+		}
+		y, col := level(v), i*c.stretch
+		switch {
+		case prev < 0 || prev == y:
+			grid[y][col] = c.chars.flat
+		case y > prev:
+			grid[prev][col] = c.chars.upFrom
+			grid[y][col] = c.chars.upTo
+			for r := prev + 1; r < y; r++ {
+				grid[r][col] = c.chars.vertical
+			}
+		default:
+			grid[prev][col] = c.chars.downFrom
+			grid[y][col] = c.chars.downTo
+			for r := y + 1; r < prev; r++ {
+				grid[r][col] = c.chars.vertical
+			}
+		}
+		for j := 1; j < c.stretch; j++ {
+			grid[y][col+j] = c.chars.flat
+		}
+		prev = y
+	}
+
+	for r := height - 1; r >= 0; r-- {
+		label := lo
+		if height > 1 {
+			label += (hi - lo) * float64(r) / float64(height-1)
+		}
+		c.row(strconv.FormatFloat(label, 'f', 1, 64), grid[r])
+	}
+}
+
+// bars adds a bar chart, with bars rising from zero.
+func (c *chart) bars(sr series) {
+	_, hi, ok := sr.bounds()
+	if !ok {
+		return
+	}
+	if hi <= 0 {
+		c.title(sr, "none")
+		return
+	}
+	c.title(sr, "max "+formatFloat(hi))
+	full := len(c.chars.bar) - 1
+	for r := barHeight - 1; r >= 0; r-- {
+		cells := c.cells()
+		for i, v := range sr.values {
+			if math.IsNaN(v) || v <= 0 {
+				continue
+			}
+			eighths := v / hi * barHeight * float64(full)
+			n := min(max(int(math.Round(eighths))-r*full, 0), full)
+			if r == 0 && n == 0 {
+				// Show that some fell, however little.
+				n = 1
+			}
+			c.set(cells, i, c.chars.bar[n])
+		}
+		label := ""
+		switch r {
+		case barHeight - 1:
+			label = formatFloat(hi)
+		case 0:
+			label = "0"
+		}
+		c.row(label, cells)
+	}
+}
+
+// wind adds a row of arrows for the wind direction and a sparkline of the
+// wind speed.
+func (c *chart) wind(direction series, hasDirection bool, speed series, hasSpeed bool) {
+	if hasDirection {
+		c.title(direction, "arrows point downwind")
+		cells := c.cells()
+		for i, v := range direction.values {
+			if !math.IsNaN(v) {
+				sector := int(math.Round(math.Mod(v, 360)/45)) % 8
+				c.set(cells, i, c.chars.arrows[(sector+8)%8])
+			}
+		}
+		c.row("", cells)
+	}
+	if hasSpeed {
+		c.sparkline(speed)
+	}
+}
+
+// weather adds a row of weather symbols and their legend.
+func (c *chart) weather(sr series) {
+	c.title(sr, "")
+	cells := c.cells()
+	seen := map[openmeteogo.WeatherGroup]bool{}
+	for i, v := range sr.values {
+		if math.IsNaN(v) {
+			continue
+		}
+		g := openmeteogo.CodeGroup(int(v))
+		sym, ok := c.chars.symbols[g]
+		if !ok {
+			sym = c.chars.unknown
+		}
+		seen[g] = true
+		c.set(cells, i, sym)
+	}
+	c.row("", cells)
+
+	var legend []string
+	for _, g := range groupOrder {
+		if seen[g] {
+			legend = append(legend, c.chars.symbols[g]+" "+string(g))
+		}
+	}
+	if len(legend) > 0 {
+		c.lines = append(c.lines, strings.Repeat(" ", labelWidth+1)+strings.Join(legend, "  "))
+	}
+}
+
+// sparkline adds a one-row chart scaled between the lowest and highest
+// values.
+func (c *chart) sparkline(sr series) {
+	lo, hi, ok := sr.bounds()
+	if !ok {
+		return
+	}
+	c.title(sr, fmt.Sprintf("min %s  max %s", formatFloat(lo), formatFloat(hi)))
+	cells := c.cells()
+	top := len(c.chars.spark) - 1
+	for i, v := range sr.values {
+		if math.IsNaN(v) {
+			continue
+		}
+		n := 0
+		if hi > lo {
+			n = int(math.Round((v - lo) / (hi - lo) * float64(top)))
+		}
+		c.set(cells, i, c.chars.spark[n])
+	}
+	c.row("", cells)
+}
+
+// axis adds the time axis, labelling midnights with the date and every six
+// hours with the hour, or each day of daily data, where there is room.
+func (c *chart) axis() {
+	c.lines = append(c.lines, strings.Repeat(" ", labelWidth)+c.chars.corner+strings.Repeat(c.chars.rule, len(c.times)*c.stretch))
+	labels := []byte(strings.Repeat(" ", len(c.times)*c.stretch+5))
+	free := 0
+	for i, t := range c.times {
+		label := ""
+		date, clock, hasClock := strings.Cut(t, "T")
+		switch {
+		case !hasClock && len(date) == len(openmeteogo.DateFormat):
+			label = date[5:]
+		case clock == "00:00" && len(date) == len(openmeteogo.DateFormat):
+			label = date[5:]
+		case strings.HasSuffix(clock, ":00") && len(clock) == 5:
+			if h, err := strconv.Atoi(clock[:2]); err == nil && h%6 == 0 {
+				label = clock[:2]
+			}
+		}
+		col := i * c.stretch
+		if label == "" || col < free {
+			continue
+		}
+		copy(labels[col:], label)
+		free = col + len(label) + 1
+	}
+	c.lines = append(c.lines, strings.TrimRight(strings.Repeat(" ", labelWidth+1)+string(labels), " "))
+}
@@ -0,0 +1,150 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun_Chart(t *testing.T) {
+	a, stdout, stderr, _ := newApp(t)
+
+	code := a.run([]string{"forecast", "--chart", "--hourly", "temperature_2m,weather_code", "--daily", "temperature_2m_max", "Berlin"})
+	require.Equal(t, 0, code, stderr.String())
+
+	want := `Berlin, Land Berlin, Germany (52.52, 13.42, 38 m, Europe/Berlin)
+
+Hourly
+temperature_2m (°C)
+    2.0┤   ╭──
+    1.9┤   │
+    1.9┤   │
+    1.8┤   │
+    1.7┤   │
+    1.6┤   │
+    1.6┤   │
+    1.5┤───╯
+weather_code (wmo code)
+       │☁  ϟ
+        ☁ cloudy  ϟ thunderstorm
+       └──────
+        01-01
+
+Daily
+temperature_2m_max (°C)
+    4.2┤───
+       └───
+        01-01
+`
+	assert.Equal(t, want, stdout.String())
+}
+
+func TestRun_ChartASCII(t *testing.T) {
+	a, stdout, stderr, _ := newApp(t)
+	a.getenv = func(k string) string {
+		if k == "COLUMNS" {
+			return "40"
+		}
+		return ""
+	}
+
+	code := a.run([]string{"forecast", "--chart", "--ascii", "--hourly", "weather_code", "Berlin"})
+	require.Equal(t, 0, code, stderr.String())
+	assert.Contains(t, stdout.String(), "       |c  !\n        c cloudy  ! thunderstorm\n")
+	for _, r := range stdout.String() {
+		if r > 127 {
+			t.Fatalf("output has non-ASCII character %q:\n%s", r, stdout.String())
+		}
+	}
+}
+
+// hours returns a section of n hourly steps from midnight with the given
+// columns.
+func hours(n int, columns ...column) section {
+	s := section{name: "hourly", columns: columns}
+	for i := range n {
+		s.time = append(s.time, fmt.Sprintf("2025-01-%02dT%02d:00", 1+i/24, i%24))
+	}
+	return s
+}
+
+// chartRows returns the rows of a chart below the given title, up to the
+// next title or the time axis.
+func chartRows(t *testing.T, chart, title string) []string {
+	t.Helper()
+	_, after, ok := strings.Cut(chart, title+"\n")
+	require.True(t, ok, "no %q in\n%s", title, chart)
+	var rows []string
+	for _, line := range strings.Split(after, "\n") {
+		if !strings.HasPrefix(line, " ") || strings.Contains(line, "└") {
+			break
+		}
+		rows = append(rows, line[labelWidth:])
+	}
+	return rows
+}
+
+func TestWriteChart_Wind(t *testing.T) {
+	direction := column{name: "wind_direction_10m", unit: "°", values: []any{0.0, 45.0, 90.0, 135.0, 180.0, 225.0, 270.0, 315.0, 350.0, nil}}
+	speed := column{name: "wind_speed_10m", unit: "km/h", values: []any{0.0, 1.0, 2.0, 3.0, 4.0, 5.0, 6.0, 7.0, 7.0, 7.0}}
+
+	var buf bytes.Buffer
+	require.NoError(t, writeChart(&buf, hours(10, direction, speed), chartOptions{width: 18}))
+	assert.Equal(t, []string{"│↓↙←↖↑↗→↘↓"}, chartRows(t, buf.String(), "wind_direction_10m (°)  arrows point downwind"))
+	assert.Equal(t, []string{"│▁▂▃▄▅▆▇███"}, chartRows(t, buf.String(), "wind_speed_10m (km/h)  min 0  max 7"))
+}
+
+func TestWriteChart_Sampled(t *testing.T) {
+	var precipitation, code []any
+	for i := range 48 {
+		precipitation = append(precipitation, 0.5)
+		code = append(code, float64(i%4))
+	}
+	s := hours(48,
+		column{name: "precipitation", unit: "mm", values: precipitation},
+		column{name: "weather_code", unit: "wmo code", values: code},
+	)
+
+	var buf bytes.Buffer
+	require.NoError(t, writeChart(&buf, s, chartOptions{width: labelWidth + 1 + 12}))
+	chart := buf.String()
+	assert.Contains(t, chart, "precipitation (mm)  max 2\n", "precipitation is summed over each sampled step")
+	assert.Equal(t, []string{"│☁☁☁☁☁☁☁☁☁☁☁☁"}, chartRows(t, chart, "weather_code (wmo code)")[:1], "the most severe code is kept")
+	assert.Contains(t, chart, "└────────────\n        01-01 01-02\n")
+}
+
+func TestWriteChart_Gaps(t *testing.T) {
+	s := hours(4,
+		column{name: "temperature_2m", unit: "°C", values: []any{1.0, nil, 2.0, 2.0}},
+		column{name: "precipitation", unit: "mm", values: []any{0.0, 0.0, nil, 0.0}},
+		column{name: "sunrise", unit: "iso8601", values: []any{"x", "y", "z", "w"}},
+	)
+
+	var buf bytes.Buffer
+	require.NoError(t, writeChart(&buf, s, chartOptions{width: 80}))
+	chart := buf.String()
+	rows := chartRows(t, chart, "temperature_2m (°C)")
+	require.Len(t, rows, lineHeight)
+	assert.Equal(t, "┤      ──────", rows[0])
+	assert.Equal(t, "┤───", rows[lineHeight-1])
+	assert.Contains(t, chart, "precipitation (mm)  none\n")
+	assert.NotContains(t, chart, "sunrise", "columns that are not numbers are not charted")
+}
@@ -0,0 +1,200 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	openmeteogo "github.com/tpryan/openmeteogo"
+	"gopkg.in/yaml.v3"
+)
+
+// envPrefix prefixes the environment variables that override the config
+// file, e.g. OPENMETEO_TEMPERATURE_UNIT for --temperature-unit.
+const envPrefix = "OPENMETEO_"
+
+// config is the configuration file. Its settings are named like the flags
+// they provide defaults for, with underscores, e.g. temperature_unit for
+// --temperature-unit.
+//
+//	api_key: ...
+//	temperature_unit: fahrenheit
+//	timezone: America/Chicago
+//	metrics:
+//	  forecast:
+//	    hourly: [temperature_2m, precipitation]
+//	locations:
+//	  hq:
+//	    latitude: 41.88
+//	    longitude: -87.63
+type config struct {
+	// APIKey selects the commercial endpoints, as in NewClientWithKey.
+	APIKey string `yaml:"api_key"`
+	// UserAgent sets Client.UserAgent.
+	UserAgent string `yaml:"user_agent"`
+
+	// The remaining settings map onto the Options fields of the same name.
+	TemperatureUnit   string   `yaml:"temperature_unit"`
+	WindspeedUnit     string   `yaml:"windspeed_unit"`
+	PrecipitationUnit string   `yaml:"precipitation_unit"`
+	LengthUnit        string   `yaml:"length_unit"`
+	PressureUnit      string   `yaml:"pressure_unit"`
+	VisibilityUnit    string   `yaml:"visibility_unit"`
+	Timezone          string   `yaml:"timezone"`
+	Models            []string `yaml:"models"`
+
+	// Format is the default output format.
+	Format string `yaml:"format"`
+
+	// Metrics replaces the default metrics of a command, by command and
+	// section, e.g. metrics.forecast.hourly.
+	Metrics map[string]map[string][]string `yaml:"metrics"`
+
+	// Locations are named places that can be given instead of a place name.
+	Locations map[string]location `yaml:"locations"`
+}
+
+// location is a named place in the config file.
+type location struct {
+	Latitude  float64 `yaml:"latitude"`
+	Longitude float64 `yaml:"longitude"`
+	// Timezone is the IANA timezone of the place. If empty, timestamps are
+	// in UTC unless a timezone is set elsewhere.
+	Timezone string `yaml:"timezone"`
+}
+
+// configPath returns the path of the config file: the --config flag, then
+// OPENMETEO_CONFIG, then openmeteo/config.yaml in the user's config
+// directory. explicit reports whether the path was given by the user, in
+// which case the file must exist.
+func configPath(flagValue string, getenv func(string) string) (path string, explicit bool) {
+	if flagValue != "" {
+		return flagValue, true
+	}
+	if p := getenv(envPrefix + "CONFIG"); p != "" {
+		return p, true
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", false
+	}
+	return filepath.Join(dir, "openmeteo", "config.yaml"), false
+}
+
+// loadConfig reads the config file at path. A missing file gives an empty
+// config unless the path was explicit.
+func loadConfig(path string, explicit bool) (*config, error) {
+	cfg := &config{}
+	if path == "" {
+		return cfg, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && !explicit {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading config: %w", err)
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parsing config %s: %w", path, err)
+	}
+	for cmd, sections := range cfg.Metrics {
+		if _, ok := lookup(cmd); !ok {
+			return nil, fmt.Errorf("parsing config %s: metrics: unknown command %q", path, cmd)
+		}
+		for s := range sections {
+			if !slices.Contains(metricSections, s) {
+				return nil, fmt.Errorf("parsing config %s: metrics.%s: unknown section %q", path, cmd, s)
+			}
+		}
+	}
+	return cfg, nil
+}
+
+// metrics returns the default metrics for cmd: those in the config file if
+// it has any for the command, otherwise the command's own.
+func (c *config) metrics(cmd command) map[string]openmeteogo.Metrics {
+	sections, ok := c.Metrics[cmd.name]
+	if !ok {
+		return cmd.defaults
+	}
+	defaults := map[string]openmeteogo.Metrics{}
+	for s, names := range sections {
+		for _, n := range names {
+			defaults[s] = append(defaults[s], openmeteogo.Metric(n))
+		}
+	}
+	return defaults
+}
+
+// setting is a value that can be given by a flag, an environment variable
+// or the config file, in that order of precedence.
+type setting struct {
+	flag   string
+	value  *string
+	config string
+}
+
+// env returns the name of the environment variable for a flag.
+func env(flag string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(flag, "-", "_"))
+}
+
+// resolve fills in the settings whose flags were not given from the
+// environment, then from the config file.
+func resolve(settings []setting, set map[string]bool, getenv func(string) string) {
+	for _, s := range settings {
+		if set[s.flag] {
+			continue
+		}
+		if v := getenv(env(s.flag)); v != "" {
+			*s.value = v
+			continue
+		}
+		if s.config != "" {
+			*s.value = s.config
+		}
+	}
+}
+
+// newClient returns a client with the API key and user agent settings,
+// sharing the HTTP client of base.
+func newClient(base *openmeteogo.Client, apiKey, userAgent string) *openmeteogo.Client {
+	c := base
+	if apiKey != "" {
+		c = openmeteogo.NewClientWithKey(apiKey)
+		c.HTTPClient = base.HTTPClient
+		c.UserAgent = base.UserAgent
+	}
+	if userAgent != "" {
+		if c == base {
+			copied := *base
+			c = &copied
+		}
+		c.UserAgent = userAgent
+	}
+	return c
+}
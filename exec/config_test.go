@@ -0,0 +1,177 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	openmeteogo "github.com/tpryan/openmeteogo"
+)
+
+// writeConfig writes a config file in a temporary directory and returns its
+// path.
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+const testConfig = `
+api_key: secret
+temperature_unit: fahrenheit
+windspeed_unit: mph
+timezone: America/Chicago
+models: [gfs_seamless]
+metrics:
+  forecast:
+    hourly: [temperature_2m]
+locations:
+  hq:
+    latitude: 41.88
+    longitude: -87.63
+    timezone: America/Chicago
+`
+
+func TestRun_Config(t *testing.T) {
+	a, stdout, stderr, queries := newApp(t)
+	env := map[string]string{
+		"OPENMETEO_CONFIG":         writeConfig(t, testConfig),
+		"OPENMETEO_WINDSPEED_UNIT": "kn",
+	}
+	a.getenv = func(k string) string { return env[k] }
+
+	code := a.run([]string{"forecast", "--timezone", "Europe/London", "hq"})
+	require.Equal(t, 0, code, stderr.String())
+	assert.NotContains(t, queries, "/v1/search", "named locations are not geocoded")
+	assert.Contains(t, stdout.String(), "hq (52.52, 13.42")
+
+	q := queries["/v1/forecast"]
+	assert.Equal(t, "41.88", q.Get("latitude"))
+	assert.Equal(t, "-87.63", q.Get("longitude"))
+	assert.Equal(t, "secret", q.Get("apikey"), "from the config file")
+	assert.Equal(t, "fahrenheit", q.Get("temperature_unit"), "from the config file")
+	assert.Equal(t, "kn", q.Get("windspeed_unit"), "the environment wins over the config file")
+	assert.Equal(t, "Europe/London", q.Get("timezone"), "the flag wins over the environment and config file")
+	assert.Equal(t, "gfs_seamless", q.Get("models"))
+	assert.Equal(t, "temperature_2m", q.Get("hourly"))
+	assert.Empty(t, q.Get("daily"), "the config file replaces the command's default metrics")
+}
+
+func TestRun_ConfigFlag(t *testing.T) {
+	a, _, stderr, queries := newApp(t)
+
+	code := a.run([]string{"current", "--config", writeConfig(t, testConfig), "--temperature-unit", "celsius", "hq"})
+	require.Equal(t, 0, code, stderr.String())
+	q := queries["/v1/forecast"]
+	assert.Equal(t, "41.88", q.Get("latitude"))
+	assert.Equal(t, "celsius", q.Get("temperature_unit"))
+	assert.Equal(t, "America/Chicago", q.Get("timezone"))
+	assert.Contains(t, q.Get("current"), "temperature_2m", "commands without configured metrics keep their defaults")
+}
+
+func TestRun_ConfigGeocode(t *testing.T) {
+	a, stdout, stderr, queries := newApp(t)
+
+	code := a.run([]string{"geocode", "--config", writeConfig(t, "api_key: secret\nformat: csv\n"), "Berlin"})
+	require.Equal(t, 0, code, stderr.String())
+	assert.Equal(t, "secret", queries["/v1/search"].Get("apikey"))
+	assert.Contains(t, stdout.String(), "name,admin1,country")
+}
+
+func TestRun_ConfigErrors(t *testing.T) {
+	tests := map[string]struct {
+		config string
+		args   []string
+	}{
+		"unknown setting": {
+			config: "temprature_unit: fahrenheit\n",
+			args:   []string{"forecast", "Berlin"},
+		},
+		"unknown command": {
+			config: "metrics:\n  tomorrow:\n    hourly: [temperature_2m]\n",
+			args:   []string{"forecast", "Berlin"},
+		},
+		"unknown section": {
+			config: "metrics:\n  forecast:\n    yearly: [temperature_2m]\n",
+			args:   []string{"forecast", "Berlin"},
+		},
+		"bad unit": {
+			config: "temperature_unit: kelvin\n",
+			args:   []string{"forecast", "Berlin"},
+		},
+		"bad metric": {
+			config: "metrics:\n  forecast:\n    hourly: [nope]\n",
+			args:   []string{"forecast", "Berlin"},
+		},
+		"bad format": {
+			config: "format: xml\n",
+			args:   []string{"geocode", "Berlin"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			a, _, _, _ := newApp(t)
+			args := append(tc.args, "--config", writeConfig(t, tc.config))
+			assert.Equal(t, 1, a.run(args))
+		})
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		a, _, _, _ := newApp(t)
+		assert.Equal(t, 1, a.run([]string{"forecast", "--config", filepath.Join(t.TempDir(), "nope.yaml"), "Berlin"}))
+	})
+}
+
+func TestLoadConfig_MissingDefault(t *testing.T) {
+	cfg, err := loadConfig(filepath.Join(t.TempDir(), "config.yaml"), false)
+	require.NoError(t, err)
+	assert.Equal(t, &config{}, cfg)
+}
+
+func TestConfigPath(t *testing.T) {
+	env := map[string]string{}
+	getenv := func(k string) string { return env[k] }
+
+	path, explicit := configPath("flag.yaml", getenv)
+	assert.Equal(t, "flag.yaml", path)
+	assert.True(t, explicit)
+
+	env["OPENMETEO_CONFIG"] = "env.yaml"
+	path, explicit = configPath("", getenv)
+	assert.Equal(t, "env.yaml", path)
+	assert.True(t, explicit)
+
+	delete(env, "OPENMETEO_CONFIG")
+	path, explicit = configPath("", getenv)
+	assert.False(t, explicit)
+	if path != "" {
+		assert.Equal(t, filepath.Join("openmeteo", "config.yaml"), filepath.Join(filepath.Base(filepath.Dir(path)), filepath.Base(path)))
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	base := openmeteogo.NewClient()
+	assert.Same(t, base, newClient(base, "", ""))
+
+	c := newClient(base, "", "ops-dashboard")
+	assert.Equal(t, "ops-dashboard", c.UserAgent)
+	assert.Equal(t, "OpenMeteoGo-Client", base.UserAgent, "the base client is not changed")
+}
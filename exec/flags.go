@@ -0,0 +1,322 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	openmeteogo "github.com/tpryan/openmeteogo"
+)
+
+// requestFlags holds the flags shared by the commands that fetch weather
+// data. Each maps onto an OptionsBuilder method.
+type requestFlags struct {
+	lat, lon float64
+
+	temperatureUnit   string
+	windspeedUnit     string
+	precipitationUnit string
+	lengthUnit        string
+	pressureUnit      string
+	visibilityUnit    string
+	timezone          string
+
+	pastDays           int
+	forecastDays       int
+	pastHours          int
+	forecastHours      int
+	pastMinutely15     int
+	forecastMinutely15 int
+
+	start, end                     string
+	startHour, endHour             string
+	startMinutely15, endMinutely15 string
+
+	models     string
+	current    string
+	minutely15 string
+	hourly     string
+	daily      string
+	weekly     string
+	monthly    string
+
+	tilt, azimuth float64
+
+	format    string
+	chart     bool
+	ascii     bool
+	width     int
+	apiKey    string
+	userAgent string
+	config    string
+
+	// set records which flags were given on the command line.
+	set map[string]bool
+}
+
+func (f *requestFlags) register(fs *flag.FlagSet) {
+	fs.Float64Var(&f.lat, "lat", 0, "latitude, instead of a place")
+	fs.Float64Var(&f.lon, "lon", 0, "longitude, instead of a place")
+
+	fs.StringVar(&f.temperatureUnit, "temperature-unit", "", "temperature unit: celsius or fahrenheit")
+	fs.StringVar(&f.windspeedUnit, "windspeed-unit", "", "wind speed unit: kmh, ms, mph, kn or bft")
+	fs.StringVar(&f.precipitationUnit, "precipitation-unit", "", "precipitation unit: mm or inch")
+	fs.StringVar(&f.lengthUnit, "length-unit", "", "marine length units: metric or imperial")
+	fs.StringVar(&f.pressureUnit, "pressure-unit", "", "pressure unit: hPa, kPa or inHg")
+	fs.StringVar(&f.visibilityUnit, "visibility-unit", "", "visibility unit: m, km, ft or mi")
+	fs.StringVar(&f.timezone, "timezone", "", "IANA timezone for timestamps, e.g. Europe/Berlin (default: the place's timezone, or UTC)")
+
+	fs.IntVar(&f.pastDays, "past-days", 0, "number of past days to include")
+	fs.IntVar(&f.forecastDays, "days", 0, "number of forecast days")
+	fs.IntVar(&f.pastHours, "past-hours", 0, "number of past hours to include")
+	fs.IntVar(&f.forecastHours, "hours", 0, "number of forecast hours")
+	fs.IntVar(&f.pastMinutely15, "past-minutely-15", 0, "number of past 15-minute steps to include")
+	fs.IntVar(&f.forecastMinutely15, "forecast-minutely-15", 0, "number of forecast 15-minute steps")
+
+	fs.StringVar(&f.start, "start", "", "start date, YYYY-MM-DD")
+	fs.StringVar(&f.end, "end", "", "end date, YYYY-MM-DD")
+	fs.StringVar(&f.startHour, "start-hour", "", "first hour of hourly data, YYYY-MM-DDTHH:MM")
+	fs.StringVar(&f.endHour, "end-hour", "", "last hour of hourly data, YYYY-MM-DDTHH:MM")
+	fs.StringVar(&f.startMinutely15, "start-minutely-15", "", "first step of 15-minutely data, YYYY-MM-DDTHH:MM")
+	fs.StringVar(&f.endMinutely15, "end-minutely-15", "", "last step of 15-minutely data, YYYY-MM-DDTHH:MM")
+
+	fs.StringVar(&f.models, "models", "", "comma-separated weather models, e.g. icon_seamless,gfs_seamless")
+	fs.StringVar(&f.current, "current", "", "comma-separated current metrics")
+	fs.StringVar(&f.minutely15, "minutely-15", "", "comma-separated 15-minutely metrics")
+	fs.StringVar(&f.hourly, "hourly", "", "comma-separated hourly metrics")
+	fs.StringVar(&f.daily, "daily", "", "comma-separated daily metrics")
+	fs.StringVar(&f.weekly, "weekly", "", "comma-separated weekly metrics (seasonal)")
+	fs.StringVar(&f.monthly, "monthly", "", "comma-separated monthly metrics (seasonal)")
+
+	fs.Float64Var(&f.tilt, "tilt", 0, "panel tilt in degrees for global_tilted_irradiance")
+	fs.Float64Var(&f.azimuth, "azimuth", 0, "panel azimuth in degrees for global_tilted_irradiance, 0 is south")
+
+	fs.StringVar(&f.format, "format", "table", "output format: table, json or csv")
+	fs.BoolVar(&f.chart, "chart", false, "draw charts instead of tables, with the table format")
+	fs.BoolVar(&f.ascii, "ascii", false, "draw charts with ASCII characters only")
+	fs.IntVar(&f.width, "width", 0, "width of charts in columns (default: $COLUMNS, or 80)")
+	fs.StringVar(&f.apiKey, "api-key", "", "commercial API key")
+	fs.StringVar(&f.userAgent, "user-agent", "", "User-Agent header for requests")
+	fs.StringVar(&f.config, "config", "", "config file (default: openmeteo/config.yaml in the user config directory)")
+
+	f.set = map[string]bool{}
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: openmeteo %s [flags] <place | lat,lon | location>\n\nFlags:\n", fs.Name())
+		fs.PrintDefaults()
+		fmt.Fprintf(fs.Output(), "\nFlags without a value on the command line are read from %s<FLAG>, e.g.\n"+
+			"%s, then from the config file.\n", envPrefix, env("temperature-unit"))
+	}
+}
+
+// settings returns the flags that can also be set in the environment or the
+// config file.
+func (f *requestFlags) settings(cfg *config) []setting {
+	return []setting{
+		{"temperature-unit", &f.temperatureUnit, cfg.TemperatureUnit},
+		{"windspeed-unit", &f.windspeedUnit, cfg.WindspeedUnit},
+		{"precipitation-unit", &f.precipitationUnit, cfg.PrecipitationUnit},
+		{"length-unit", &f.lengthUnit, cfg.LengthUnit},
+		{"pressure-unit", &f.pressureUnit, cfg.PressureUnit},
+		{"visibility-unit", &f.visibilityUnit, cfg.VisibilityUnit},
+		{"timezone", &f.timezone, cfg.Timezone},
+		{"models", &f.models, strings.Join(cfg.Models, ",")},
+		{"format", &f.format, cfg.Format},
+		{"api-key", &f.apiKey, cfg.APIKey},
+		{"user-agent", &f.userAgent, cfg.UserAgent},
+	}
+}
+
+// options builds the request Options for cmd at loc.
+func (f *requestFlags) options(cmd command, loc openmeteogo.Location) (*openmeteogo.Options, error) {
+	b := openmeteogo.NewOptionsBuilder().Latitude(loc.Latitude).Longitude(loc.Longitude)
+	if cmd.setup != nil {
+		cmd.setup(b)
+	}
+
+	units := []struct {
+		flag, value string
+		allowed     []string
+		apply       func(string)
+	}{
+		{"temperature-unit", f.temperatureUnit, []string{"celsius", "fahrenheit"},
+			func(v string) { b.TemperatureUnit(openmeteogo.TemperatureUnit(v)) }},
+		{"windspeed-unit", f.windspeedUnit, []string{"kmh", "ms", "mph", "kn", "bft"},
+			func(v string) { b.WindspeedUnit(openmeteogo.WindSpeedUnit(v)) }},
+		{"precipitation-unit", f.precipitationUnit, []string{"mm", "inch"},
+			func(v string) { b.PrecipitationUnit(openmeteogo.PrecipitationUnit(v)) }},
+		{"length-unit", f.lengthUnit, []string{"metric", "imperial"},
+			func(v string) { b.LengthUnit(openmeteogo.LengthUnit(v)) }},
+		{"pressure-unit", f.pressureUnit, []string{"hPa", "kPa", "inHg"},
+			func(v string) { b.PressureUnit(openmeteogo.PressureUnit(v)) }},
+		{"visibility-unit", f.visibilityUnit, []string{"m", "km", "ft", "mi"},
+			func(v string) { b.VisibilityUnit(openmeteogo.VisibilityUnit(v)) }},
+	}
+	for _, u := range units {
+		if u.value == "" {
+			continue
+		}
+		if !slices.Contains(u.allowed, u.value) {
+			return nil, fmt.Errorf("--%s must be one of %s, got %q", u.flag, strings.Join(u.allowed, ", "), u.value)
+		}
+		u.apply(u.value)
+	}
+
+	tz := f.timezone
+	if tz == "" {
+		tz = loc.Timezone
+	}
+	// Hour flags are wall clock times in the request timezone.
+	zone := time.UTC
+	if tz != "" {
+		l, err := time.LoadLocation(tz)
+		if err != nil {
+			return nil, fmt.Errorf("--timezone: %w", err)
+		}
+		b.Timezone(*l)
+		zone = l
+	}
+
+	counts := []struct {
+		flag  string
+		value int
+		apply func(int) *openmeteogo.OptionsBuilder
+	}{
+		{"past-days", f.pastDays, b.PastDays},
+		{"days", f.forecastDays, b.ForcastDays},
+		{"past-hours", f.pastHours, b.PastHours},
+		{"hours", f.forecastHours, b.ForecastHours},
+		{"past-minutely-15", f.pastMinutely15, b.PastMinutely15},
+		{"forecast-minutely-15", f.forecastMinutely15, b.ForecastMinutely15},
+	}
+	for _, c := range counts {
+		if c.value < 0 {
+			return nil, fmt.Errorf("--%s must not be negative", c.flag)
+		}
+		c.apply(c.value)
+	}
+
+	times := []struct {
+		flag, value, layout string
+		apply               func(time.Time) *openmeteogo.OptionsBuilder
+	}{
+		{"start", f.start, openmeteogo.DateFormat, b.Start},
+		{"end", f.end, openmeteogo.DateFormat, b.End},
+		{"start-hour", f.startHour, openmeteogo.HourFormat, b.StartHour},
+		{"end-hour", f.endHour, openmeteogo.HourFormat, b.EndHour},
+		{"start-minutely-15", f.startMinutely15, openmeteogo.HourFormat, b.StartMinutely15},
+		{"end-minutely-15", f.endMinutely15, openmeteogo.HourFormat, b.EndMinutely15},
+	}
+	for _, t := range times {
+		if t.value == "" {
+			continue
+		}
+		v, err := time.ParseInLocation(t.layout, t.value, zone)
+		if err != nil {
+			return nil, fmt.Errorf("--%s: %w", t.flag, err)
+		}
+		t.apply(v)
+	}
+
+	if f.models != "" {
+		b.Models(split(f.models))
+	}
+
+	if err := f.metrics(b, cmd); err != nil {
+		return nil, err
+	}
+
+	b.Tilt(f.tilt).Azimuth(f.azimuth)
+
+	return b.Build(), nil
+}
+
+// metrics applies the metric flags, or the command's defaults if none were
+// given. Metrics are checked against the lists the library knows, except
+// for marine requests, whose metrics are not in those lists.
+func (f *requestFlags) metrics(b *openmeteogo.OptionsBuilder, cmd command) error {
+	sections := []struct {
+		name  string
+		value string
+		apply func(openmeteogo.Metrics) *openmeteogo.OptionsBuilder
+	}{
+		{"current", f.current, b.CurrentMetrics},
+		{"minutely_15", f.minutely15, b.Minutely15Metrics},
+		{"hourly", f.hourly, b.HourlyMetrics},
+		{"daily", f.daily, b.DailyMetrics},
+		{"weekly", f.weekly, b.WeeklyMetrics},
+		{"monthly", f.monthly, b.MonthlyMetrics},
+	}
+
+	given := false
+	for _, s := range sections {
+		given = given || s.value != ""
+	}
+	for _, s := range sections {
+		metrics := cmd.defaults[s.name]
+		if given {
+			metrics = nil
+			for _, m := range split(s.value) {
+				metrics = append(metrics, openmeteogo.Metric(m))
+			}
+		}
+		if len(metrics) == 0 {
+			continue
+		}
+		if cmd.name != "marine" {
+			var err error
+			if metrics, err = openmeteogo.NewMetrics(s.name, metrics...); err != nil {
+				return fmt.Errorf("--%s: %w", strings.ReplaceAll(s.name, "_", "-"), err)
+			}
+		}
+		s.apply(metrics)
+	}
+	return nil
+}
+
+// metricSections are the sections metrics can be requested for.
+var metricSections = []string{"current", "minutely_15", "hourly", "daily", "weekly", "monthly"}
+
+// split splits a comma-separated list, dropping empty items.
+func split(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseCoordinates parses "lat,lon".
+func parseCoordinates(s string) (float64, float64, bool) {
+	latText, lonText, ok := strings.Cut(s, ",")
+	if !ok {
+		return 0, 0, false
+	}
+	lat, err := strconv.ParseFloat(strings.TrimSpace(latText), 64)
+	if err != nil || lat < -90 || lat > 90 {
+		return 0, 0, false
+	}
+	lon, err := strconv.ParseFloat(strings.TrimSpace(lonText), 64)
+	if err != nil || lon < -180 || lon > 180 {
+		return 0, 0, false
+	}
+	return lat, lon, true
+}
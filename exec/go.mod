@@ -4,4 +4,14 @@ go 1.24.5
 
 replace github.com/tpryan/openmeteogo => ../
 
-require github.com/tpryan/openmeteogo v0.0.0-00010101000000-000000000000
+require (
+	github.com/stretchr/testify v1.10.0
+	github.com/tpryan/openmeteogo v0.0.0-00010101000000-000000000000
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/google/flatbuffers v25.12.19+incompatible // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+)
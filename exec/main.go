@@ -12,83 +12,371 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
+// Command openmeteo fetches weather data from Open-Meteo.
+//
+// Usage:
+//
+//	openmeteo <command> [flags] <place | lat,lon | location>
+//
+// The commands are current, forecast, history, marine, seasonal and geocode.
+// Places are looked up with the geocoding API, unless they name a location
+// in the config file. Run "openmeteo <command> -h" for the flags of a
+// command.
+//
+// Settings are taken, in order of precedence, from flags, OPENMETEO_*
+// environment variables, the config file and the command's defaults. The
+// config file is openmeteo/config.yaml in the user's config directory, e.g.
+// ~/.config/openmeteo/config.yaml, or the file named by --config or
+// OPENMETEO_CONFIG.
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
-	"log"
-	"time"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	_ "time/tzdata"
 
 	openmeteogo "github.com/tpryan/openmeteogo"
 )
 
+// command is a subcommand that fetches weather data.
+type command struct {
+	name    string
+	summary string
+	// defaults are the metrics requested, by section, when no metric flags
+	// are given.
+	defaults map[string]openmeteogo.Metrics
+	// setup applies the options specific to the command.
+	setup func(b *openmeteogo.OptionsBuilder)
+	// check validates the flags for the command.
+	check func(f *requestFlags) error
+}
+
+var commands = []command{
+	{
+		name:    "current",
+		summary: "current conditions",
+		defaults: map[string]openmeteogo.Metrics{
+			"current": {
+				openmeteogo.Temperature2m,
+				openmeteogo.RelativeHumidity2m,
+				openmeteogo.ApparentTemperature,
+				openmeteogo.Precipitation,
+				openmeteogo.WeatherCode,
+				openmeteogo.CloudCover,
+				openmeteogo.WindSpeed10m,
+				openmeteogo.WindDirection10m,
+				openmeteogo.WindGusts10m,
+			},
+		},
+	},
+	{
+		name:    "forecast",
+		summary: "hourly and daily forecast",
+		defaults: map[string]openmeteogo.Metrics{
+			"hourly": {
+				openmeteogo.Temperature2m,
+				openmeteogo.PrecipitationProbability,
+				openmeteogo.Precipitation,
+				openmeteogo.WeatherCode,
+				openmeteogo.WindSpeed10m,
+				openmeteogo.WindDirection10m,
+			},
+			"daily": {
+				openmeteogo.WeatherCode,
+				openmeteogo.Temperature2mMax,
+				openmeteogo.Temperature2mMin,
+				openmeteogo.PrecipitationSum,
+			},
+		},
+	},
+	{
+		name:    "history",
+		summary: "historical weather between --start and --end",
+		defaults: map[string]openmeteogo.Metrics{
+			"daily": {
+				openmeteogo.WeatherCode,
+				openmeteogo.Temperature2mMax,
+				openmeteogo.Temperature2mMin,
+				openmeteogo.PrecipitationSum,
+			},
+		},
+		check: func(f *requestFlags) error {
+			if f.start == "" || f.end == "" {
+				return errors.New("history needs --start and --end")
+			}
+			return nil
+		},
+	},
+	{
+		name:    "marine",
+		summary: "wave and swell forecast",
+		defaults: map[string]openmeteogo.Metrics{
+			"hourly": {
+				openmeteogo.WaveHeight,
+				openmeteogo.WaveDirection,
+				openmeteogo.WavePeriod,
+				openmeteogo.SeaSurfaceTemperature,
+			},
+			"daily": {
+				openmeteogo.WaveHeightMax,
+				openmeteogo.WaveDirectionDominant,
+				openmeteogo.WavePeriodMax,
+			},
+		},
+		setup: func(b *openmeteogo.OptionsBuilder) { b.Marine(true) },
+	},
+	{
+		name:    "seasonal",
+		summary: "weekly and monthly seasonal forecast",
+		defaults: map[string]openmeteogo.Metrics{
+			"weekly": {
+				openmeteogo.Temperature2mMean,
+				openmeteogo.Temperature2mAnomaly,
+				openmeteogo.PrecipitationMean,
+				openmeteogo.PrecipitationAnomaly,
+			},
+		},
+		setup: func(b *openmeteogo.OptionsBuilder) { b.Seasonal(true) },
+	},
+}
+
+// app runs the command line tool.
+type app struct {
+	client *openmeteogo.Client
+	stdout io.Writer
+	stderr io.Writer
+	getenv func(string) string
+}
+
 func main() {
-	// Create a new client
-	c := openmeteogo.NewClient()
-
-	// Set the options for the weather data we want.
-	// Let's get the current temperature and weather code for San Francisco,
-	// as well as the forecast for today.
-	opts := openmeteogo.NewOptionsBuilder().
-		Latitude(37.7749).Longitude(-122.4194).
-		TemperatureUnit(openmeteogo.Fahrenheit).
-		ForcastDays(1).
-		CurrentMetrics(openmeteogo.Metrics{
-			openmeteogo.Temperature2m,
-			openmeteogo.WeatherCode,
-		}).
-		DailyMetrics(openmeteogo.Metrics{
-			openmeteogo.Temperature2mMax,
-			openmeteogo.Temperature2mMin,
-			openmeteogo.WeatherCode,
-		}).
-		Build()
-
-	// Make the API call
-	weather, err := c.Get(opts)
+	a := &app{
+		client: openmeteogo.NewClient(),
+		stdout: os.Stdout,
+		stderr: os.Stderr,
+		getenv: os.Getenv,
+	}
+	os.Exit(a.run(os.Args[1:]))
+}
+
+// run runs the command in args and returns the exit code.
+func (a *app) run(args []string) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		a.usage()
+		if len(args) == 0 {
+			return 2
+		}
+		return 0
+	}
+
+	var err error
+	if args[0] == "geocode" {
+		err = a.geocode(args[1:])
+	} else {
+		cmd, ok := lookup(args[0])
+		if !ok {
+			fmt.Fprintf(a.stderr, "openmeteo: unknown command %q\n\n", args[0])
+			a.usage()
+			return 2
+		}
+		err = a.fetch(cmd, args[1:])
+	}
+
+	switch {
+	case errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.Is(err, errUsage):
+		return 2
+	case err != nil:
+		fmt.Fprintf(a.stderr, "openmeteo: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func (a *app) usage() {
+	fmt.Fprintln(a.stderr, "Usage: openmeteo <command> [flags] <place | lat,lon | location>")
+	fmt.Fprintln(a.stderr)
+	fmt.Fprintln(a.stderr, "Commands:")
+	for _, c := range commands {
+		fmt.Fprintf(a.stderr, "  %-10s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintf(a.stderr, "  %-10s %s\n", "geocode", "look up places by name")
+	fmt.Fprintln(a.stderr)
+	fmt.Fprintln(a.stderr, `Run "openmeteo <command> -h" for the flags of a command.`)
+}
+
+func lookup(name string) (command, bool) {
+	for _, c := range commands {
+		if c.name == name {
+			return c, true
+		}
+	}
+	return command{}, false
+}
+
+// fetch runs a command that fetches weather data.
+func (a *app) fetch(cmd command, args []string) error {
+	fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	var f requestFlags
+	f.register(fs)
+	place, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	fs.Visit(func(fl *flag.Flag) { f.set[fl.Name] = true })
+	if cmd.check != nil {
+		if err := cmd.check(&f); err != nil {
+			return err
+		}
+	}
+
+	cfg, err := loadConfig(configPath(f.config, a.getenv))
 	if err != nil {
-		log.Fatalf("Failed to get weather data: %v", err)
-	}
-
-	// Print the current weather
-	fmt.Printf("Current Weather in San Francisco (Lat: %.2f, Lon: %.2f)\n", weather.Latitude, weather.Longitude)
-	fmt.Printf("Time: %s\n", weather.Current.Time)
-	fmt.Printf("Temperature: %.2f%s\n", weather.Current.Temperature2m, weather.CurrentUnits.Temperature2m)
-	fmt.Printf("Weather: %s\n\n", openmeteogo.DescribeCode(weather.Current.WeatherCode))
-
-	// Print the daily forecast for today
-	if len(weather.Daily.Time) > 0 {
-		fmt.Printf("Forecast for %s:\n", weather.Daily.Time[0])
-		fmt.Printf("  Max Temperature: %.2f%s\n", weather.Daily.Temperature2mMax[0], weather.DailyUnits.Temperature2mMax)
-		fmt.Printf("  Min Temperature: %.2f%s\n", weather.Daily.Temperature2mMin[0], weather.DailyUnits.Temperature2mMin)
-		fmt.Printf("  Weather: %s\n", openmeteogo.DescribeCode(weather.Daily.WeatherCode[0]))
-	}
-
-	pastOpts := openmeteogo.NewOptionsBuilder().
-		Latitude(37.7749).Longitude(-122.4194).
-		TemperatureUnit(openmeteogo.Fahrenheit).
-		DailyMetrics(openmeteogo.Metrics{
-			openmeteogo.Temperature2mMax,
-			openmeteogo.Temperature2mMin,
-			openmeteogo.WeatherCode,
-		}).
-		Start(time.Date(2025, 7, 15, 0, 0, 0, 0, time.UTC)).End(time.Date(2025, 7, 26, 0, 0, 0, 0, time.UTC)).
-		Build()
-
-	weatherPast, err := c.Get(pastOpts)
+		return err
+	}
+	resolve(f.settings(cfg), f.set, a.getenv)
+	cmd.defaults = cfg.metrics(cmd)
+	if err := checkFormat(f.format); err != nil {
+		return err
+	}
+	chart, err := f.charts(a.getenv)
 	if err != nil {
-		log.Fatalf("Failed to get weather data: %v", err)
+		return err
 	}
 
-	// Print the current weather
-	fmt.Printf("Past Weather in San Francisco (Lat: %.2f, Lon: %.2f)\n", weatherPast.Latitude, weatherPast.Longitude)
+	client := newClient(a.client, f.apiKey, f.userAgent)
+	loc, err := a.locate(client, cfg, place, &f)
+	if err != nil {
+		return err
+	}
+	opts, err := f.options(cmd, loc)
+	if err != nil {
+		return err
+	}
+
+	wd, err := client.Get(opts)
+	if err != nil {
+		return fmt.Errorf("fetching %s: %w", cmd.name, err)
+	}
+	return write(a.stdout, f.format, chart, loc, wd, opts)
+}
 
-	// Print the daily forecast for today
-	if len(weatherPast.Daily.Time) > 0 {
-		fmt.Printf("Forecast for %s:\n", weatherPast.Daily.Time[0])
-		fmt.Printf("  Max Temperature: %.2f%s\n", weatherPast.Daily.Temperature2mMax[0], weatherPast.DailyUnits.Temperature2mMax)
-		fmt.Printf("  Min Temperature: %.2f%s\n", weatherPast.Daily.Temperature2mMin[0], weatherPast.DailyUnits.Temperature2mMin)
-		fmt.Printf("  Weather: %s\n", openmeteogo.DescribeCode(weatherPast.Daily.WeatherCode[0]))
+// geocode runs the geocode command.
+func (a *app) geocode(args []string) error {
+	fs := flag.NewFlagSet("geocode", flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	count := fs.Int("count", 10, "maximum number of places to return")
+	format := fs.String("format", "table", "output format: table, json or csv")
+	apiKey := fs.String("api-key", "", "commercial API key")
+	userAgent := fs.String("user-agent", "", "User-Agent header for requests")
+	configFile := fs.String("config", "", "config file (default: openmeteo/config.yaml in the user config directory)")
+	name, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		fmt.Fprintln(a.stderr, "geocode needs a place name")
+		return errUsage
+	}
+
+	cfg, err := loadConfig(configPath(*configFile, a.getenv))
+	if err != nil {
+		return err
+	}
+	set := map[string]bool{}
+	fs.Visit(func(fl *flag.Flag) { set[fl.Name] = true })
+	resolve([]setting{
+		{"format", format, cfg.Format},
+		{"api-key", apiKey, cfg.APIKey},
+		{"user-agent", userAgent, cfg.UserAgent},
+	}, set, a.getenv)
+	if err := checkFormat(*format); err != nil {
+		return err
 	}
 
+	locations, err := newClient(a.client, *apiKey, *userAgent).Geocode(name, *count)
+	if err != nil {
+		return fmt.Errorf("looking up %q: %w", name, err)
+	}
+	if len(locations) == 0 {
+		return fmt.Errorf("no places found for %q", name)
+	}
+	return writeLocations(a.stdout, *format, locations)
+}
+
+// locate resolves the place argument or the --lat and --lon flags to a
+// location. Places that are neither coordinates nor locations in the config
+// file are looked up with the geocoding API, taking the most relevant
+// result.
+func (a *app) locate(client *openmeteogo.Client, cfg *config, place string, f *requestFlags) (openmeteogo.Location, error) {
+	if f.set["lat"] || f.set["lon"] {
+		if !f.set["lat"] || !f.set["lon"] || place != "" {
+			return openmeteogo.Location{}, errors.New("give either a place, or both --lat and --lon")
+		}
+		return openmeteogo.Location{Latitude: f.lat, Longitude: f.lon}, nil
+	}
+	if place == "" {
+		return openmeteogo.Location{}, errors.New("no place given")
+	}
+	if l, ok := cfg.Locations[place]; ok {
+		return openmeteogo.Location{Name: place, Latitude: l.Latitude, Longitude: l.Longitude, Timezone: l.Timezone}, nil
+	}
+	if lat, lon, ok := parseCoordinates(place); ok {
+		return openmeteogo.Location{Latitude: lat, Longitude: lon}, nil
+	}
+
+	locations, err := client.Geocode(place, 1)
+	if err != nil {
+		return openmeteogo.Location{}, fmt.Errorf("looking up %q: %w", place, err)
+	}
+	if len(locations) == 0 {
+		return openmeteogo.Location{}, fmt.Errorf("no places found for %q", place)
+	}
+	return locations[0], nil
+}
+
+// errUsage reports that the usage message has been printed.
+var errUsage = errors.New("usage")
+
+// parse parses flags, allowing them before and after the positional
+// arguments, which are joined with spaces so that place names need no
+// quoting. Southern or western coordinates such as -33.87,151.21 look like
+// flags, so flags are only parsed up to the next pair of coordinates.
+func parse(fs *flag.FlagSet, args []string) (string, error) {
+	var words []string
+	for {
+		end := slices.IndexFunc(args, func(arg string) bool {
+			_, _, ok := parseCoordinates(arg)
+			return ok
+		})
+		if end < 0 {
+			end = len(args)
+		}
+		segment := args[:end]
+		for {
+			if err := fs.Parse(segment); err != nil {
+				if errors.Is(err, flag.ErrHelp) {
+					return "", err
+				}
+				return "", errUsage
+			}
+			if fs.NArg() == 0 {
+				break
+			}
+			words = append(words, fs.Arg(0))
+			segment = fs.Args()[1:]
+		}
+		if end == len(args) {
+			break
+		}
+		words = append(words, args[end])
+		args = args[end+1:]
+	}
+	return strings.Join(words, " "), nil
 }
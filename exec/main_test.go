@@ -0,0 +1,328 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	openmeteogo "github.com/tpryan/openmeteogo"
+)
+
+// rewriteTransport sends every request to a test server.
+type rewriteTransport struct {
+	target *url.URL
+}
+
+func (t rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+const (
+	berlin = `{"results": [{"name": "Berlin", "latitude": 52.52437, "longitude": 13.41053, "elevation": 74,
+		"country": "Germany", "admin1": "Land Berlin", "timezone": "Europe/Berlin"}]}`
+	forecast = `{
+		"latitude": 52.52, "longitude": 13.42, "elevation": 38, "timezone": "Europe/Berlin",
+		"timezone_abbreviation": "CET", "utc_offset_seconds": 3600,
+		"current_units": {"time": "iso8601", "temperature_2m": "°C", "weather_code": "wmo code"},
+		"current": {"time": "2025-01-01T12:00", "temperature_2m": 3.5, "weather_code": 61},
+		"hourly_units": {"time": "iso8601", "temperature_2m": "°C", "weather_code": "wmo code"},
+		"hourly": {"time": ["2025-01-01T00:00", "2025-01-01T01:00"], "temperature_2m": [1.5, 2], "weather_code": [3, 95]},
+		"daily_units": {"time": "iso8601", "temperature_2m_max": "°C", "sunrise": "iso8601"},
+		"daily": {"time": ["2025-01-01"], "temperature_2m_max": [4.2], "sunrise": ["2025-01-01T08:17"]}
+	}`
+)
+
+// newApp returns an app whose client talks to a test server, and the
+// queries the server received, keyed by path.
+func newApp(t *testing.T) (*app, *bytes.Buffer, *bytes.Buffer, map[string]url.Values) {
+	t.Helper()
+	queries := map[string]url.Values{}
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		queries[req.URL.Path] = req.URL.Query()
+		if req.URL.Path == "/v1/search" {
+			if req.URL.Query().Get("name") == "Atlantis" {
+				rw.Write([]byte(`{}`))
+				return
+			}
+			rw.Write([]byte(berlin))
+			return
+		}
+		rw.Write([]byte(forecast))
+	}))
+	t.Cleanup(server.Close)
+
+	target, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	client := openmeteogo.NewClient()
+	client.HTTPClient = &http.Client{Transport: rewriteTransport{target}}
+
+	// Keep the user's config file out of the tests.
+	env := map[string]string{"OPENMETEO_CONFIG": writeConfig(t, "")}
+
+	var stdout, stderr bytes.Buffer
+	a := &app{client: client, stdout: &stdout, stderr: &stderr, getenv: func(k string) string { return env[k] }}
+	return a, &stdout, &stderr, queries
+}
+
+func TestRun_ForecastTable(t *testing.T) {
+	a, stdout, stderr, queries := newApp(t)
+
+	code := a.run([]string{"forecast", "--days", "2", "Berlin", "--hourly", "temperature_2m,weather_code", "--daily", "temperature_2m_max,sunrise"})
+	require.Equal(t, 0, code, stderr.String())
+
+	q := queries["/v1/forecast"]
+	assert.Equal(t, "52.52437", q.Get("latitude"))
+	assert.Equal(t, "13.41053", q.Get("longitude"))
+	assert.Equal(t, "Europe/Berlin", q.Get("timezone"))
+	assert.Equal(t, "2", q.Get("forecast_days"))
+	assert.Equal(t, "temperature_2m,weather_code", q.Get("hourly"))
+	assert.Equal(t, "temperature_2m_max,sunrise", q.Get("daily"))
+
+	want := `Berlin, Land Berlin, Germany (52.52, 13.42, 38 m, Europe/Berlin)
+
+Hourly
+time              temperature_2m (°C)  weather_code (wmo code)
+2025-01-01T00:00  1.5                  3 Overcast
+2025-01-01T01:00  2                    95 Thunderstorm: Slight or moderate
+
+Daily
+time        temperature_2m_max (°C)  sunrise (iso8601)
+2025-01-01  4.2                      2025-01-01T08:17
+`
+	assert.Equal(t, want, stdout.String())
+}
+
+func TestRun_CurrentTable(t *testing.T) {
+	a, stdout, stderr, queries := newApp(t)
+
+	code := a.run([]string{"current", "--current", "temperature_2m,weather_code", "52.52,13.42"})
+	require.Equal(t, 0, code, stderr.String())
+	assert.NotContains(t, queries, "/v1/search", "coordinates are not geocoded")
+	assert.Empty(t, queries["/v1/forecast"].Get("timezone"))
+
+	want := `52.52, 13.42, 38 m, Europe/Berlin
+
+Current
+time            2025-01-01T12:00
+temperature_2m  3.5 °C
+weather_code    61 Rain: Slight intensity
+`
+	assert.Equal(t, want, stdout.String())
+}
+
+func TestRun_NegativeCoordinates(t *testing.T) {
+	a, stdout, stderr, queries := newApp(t)
+
+	code := a.run([]string{"current", "-33.87,151.21", "--current", "temperature_2m"})
+	require.Equal(t, 0, code, stderr.String())
+	assert.NotContains(t, queries, "/v1/search", "coordinates are not geocoded")
+	assert.Equal(t, "-33.87", queries["/v1/forecast"].Get("latitude"))
+	assert.Equal(t, "151.21", queries["/v1/forecast"].Get("longitude"))
+	assert.Equal(t, "temperature_2m", queries["/v1/forecast"].Get("current"))
+	assert.Contains(t, stdout.String(), "Current")
+}
+
+func TestParse(t *testing.T) {
+	tests := map[string]struct {
+		args     []string
+		want     string
+		wantDays int
+	}{
+		"place":               {args: []string{"New", "York", "--days", "3"}, want: "New York", wantDays: 3},
+		"southern latitude":   {args: []string{"-33.87,151.21"}, want: "-33.87,151.21"},
+		"western longitude":   {args: []string{"--days", "3", "40.71,-74.01"}, want: "40.71,-74.01", wantDays: 3},
+		"flags on both sides": {args: []string{"--past-days", "1", "-33.87,-70.65", "--days", "3"}, want: "-33.87,-70.65", wantDays: 3},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			fs := flag.NewFlagSet("forecast", flag.ContinueOnError)
+			days := fs.Int("days", 0, "")
+			fs.Int("past-days", 0, "")
+			got, err := parse(fs, tc.args)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+			assert.Equal(t, tc.wantDays, *days)
+		})
+	}
+}
+
+func TestRun_CSV(t *testing.T) {
+	a, stdout, stderr, queries := newApp(t)
+
+	code := a.run([]string{"history", "--lat", "52.52", "--lon", "13.42", "--start", "2025-01-01", "--end", "2025-01-01",
+		"--daily", "temperature_2m_max", "--format", "csv"})
+	require.Equal(t, 0, code, stderr.String())
+	assert.Equal(t, "2025-01-01", queries["/v1/archive"].Get("start_date"))
+
+	want := `latitude,longitude,elevation,utc_offset_seconds,timezone,timezone_abbreviation
+52.52,13.42,38,3600,Europe/Berlin,CET
+
+daily
+time,temperature_2m_max
+iso8601,°C
+2025-01-01,4.2
+`
+	assert.Equal(t, want, stdout.String())
+}
+
+func TestRun_JSON(t *testing.T) {
+	a, stdout, stderr, _ := newApp(t)
+
+	code := a.run([]string{"current", "--format", "json", "Berlin"})
+	require.Equal(t, 0, code, stderr.String())
+
+	var wd openmeteogo.WeatherData
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &wd))
+	assert.Equal(t, 3.5, wd.Current.Temperature2m)
+}
+
+func TestRun_Geocode(t *testing.T) {
+	a, stdout, stderr, queries := newApp(t)
+
+	code := a.run([]string{"geocode", "--count", "3", "Berlin"})
+	require.Equal(t, 0, code, stderr.String())
+	assert.Equal(t, "3", queries["/v1/search"].Get("count"))
+
+	want := `name    region       country  latitude  longitude  elevation  timezone
+Berlin  Land Berlin  Germany  52.52437  13.41053   74 m       Europe/Berlin
+`
+	assert.Equal(t, want, stdout.String())
+}
+
+func TestRun_Errors(t *testing.T) {
+	tests := map[string]struct {
+		args []string
+		code int
+	}{
+		"no command":        {nil, 2},
+		"unknown command":   {[]string{"tomorrow"}, 2},
+		"unknown flag":      {[]string{"forecast", "--nope", "Berlin"}, 2},
+		"no place":          {[]string{"forecast"}, 1},
+		"place not found":   {[]string{"forecast", "Atlantis"}, 1},
+		"lat without lon":   {[]string{"forecast", "--lat", "52"}, 1},
+		"history no dates":  {[]string{"history", "Berlin"}, 1},
+		"bad unit":          {[]string{"forecast", "--temperature-unit", "kelvin", "Berlin"}, 1},
+		"bad format":        {[]string{"forecast", "--format", "xml", "Berlin"}, 1},
+		"bad metric":        {[]string{"forecast", "--hourly", "nope", "Berlin"}, 1},
+		"chart not table":   {[]string{"forecast", "--chart", "--format", "csv", "Berlin"}, 1},
+		"bad date":          {[]string{"history", "--start", "yesterday", "--end", "2025-01-01", "Berlin"}, 1},
+		"bad timezone":      {[]string{"forecast", "--timezone", "Mars/Olympus", "Berlin"}, 1},
+		"geocode no name":   {[]string{"geocode"}, 2},
+		"geocode not found": {[]string{"geocode", "Atlantis"}, 1},
+		"help":              {[]string{"forecast", "-h"}, 0},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			a, _, _, _ := newApp(t)
+			assert.Equal(t, tc.code, a.run(tc.args))
+		})
+	}
+}
+
+func TestRequestFlags_Options(t *testing.T) {
+	fs := flag.NewFlagSet("marine", flag.ContinueOnError)
+	var f requestFlags
+	f.register(fs)
+	place, err := parse(fs, []string{
+		"--length-unit", "imperial", "--windspeed-unit", "bft", "--pressure-unit", "inHg",
+		"--timezone", "America/New_York", "--past-days", "1", "--hours", "12",
+		"--start-hour", "2025-01-01T06:00", "--models", "ecmwf_wam025, gwam", "--hourly", "wave_height",
+		"--tilt", "30", "--azimuth", "-15", "Cape", "Cod",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Cape Cod", place)
+
+	cmd, _ := lookup("marine")
+	o, err := f.options(cmd, openmeteogo.Location{Latitude: 41.7, Longitude: -70.3, Timezone: "Europe/Berlin"})
+	require.NoError(t, err)
+
+	assert.Equal(t, 41.7, o.Latitude)
+	assert.True(t, o.Marine)
+	assert.Equal(t, openmeteogo.LengthImperial, o.LengthUnit)
+	assert.Equal(t, openmeteogo.BFT, o.WindspeedUnit)
+	assert.Equal(t, openmeteogo.INHG, o.PressureUnit)
+	assert.Equal(t, "America/New_York", o.Timezone.String(), "the flag wins over the place's timezone")
+	assert.Equal(t, 1, o.PastDays)
+	assert.Equal(t, 12, o.ForecastHours)
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	assert.True(t, time.Date(2025, 1, 1, 6, 0, 0, 0, newYork).Equal(o.StartHour), "hours are read in the request timezone")
+	assert.Equal(t, []string{"ecmwf_wam025", "gwam"}, o.Models)
+	assert.Equal(t, openmeteogo.Metrics{openmeteogo.WaveHeight}, o.HourlyMetrics)
+	assert.Nil(t, o.DailyMetrics, "defaults are dropped when metrics are given")
+	assert.Equal(t, 30.0, o.Tilt)
+	assert.Equal(t, -15.0, o.Azimuth)
+}
+
+func TestSections_Models(t *testing.T) {
+	wd := &openmeteogo.WeatherData{
+		ByModel: map[string]*openmeteogo.ModelData{
+			"icon_seamless": {
+				HourlyUnits: openmeteogo.HourlyUnits{Temperature2m: "°C"},
+				Hourly:      openmeteogo.Hourly{Time: []string{"2025-01-01T00:00"}, Temperature2m: []float64{1}},
+			},
+			"gfs_seamless": {
+				HourlyUnits: openmeteogo.HourlyUnits{Temperature2m: "°C"},
+				Hourly:      openmeteogo.Hourly{Time: []string{"2025-01-01T00:00"}, Temperature2m: []float64{2}},
+			},
+		},
+	}
+	o := openmeteogo.NewOptionsBuilder().HourlyMetrics(openmeteogo.Metrics{openmeteogo.Temperature2m}).Build()
+
+	got, err := sections(wd, o)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, []string{"2025-01-01T00:00"}, got[0].time)
+	assert.Equal(t, []column{
+		{name: "temperature_2m_gfs_seamless", unit: "°C", values: []any{2.0}},
+		{name: "temperature_2m_icon_seamless", unit: "°C", values: []any{1.0}},
+	}, got[0].columns)
+}
+
+func TestParseCoordinates(t *testing.T) {
+	tests := map[string]struct {
+		in       string
+		lat, lon float64
+		ok       bool
+	}{
+		"coordinates":  {"52.52,13.41", 52.52, 13.41, true},
+		"spaces":       {"-33.87, 151.21", -33.87, 151.21, true},
+		"place":        {"Berlin", 0, 0, false},
+		"place, comma": {"Paris, Texas", 0, 0, false},
+		"out of range": {"91,0", 0, 0, false},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			lat, lon, ok := parseCoordinates(tc.in)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.lat, lat)
+			assert.Equal(t, tc.lon, lon)
+		})
+	}
+}
@@ -0,0 +1,312 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	openmeteogo "github.com/tpryan/openmeteogo"
+)
+
+var formats = []string{"table", "json", "csv"}
+
+func checkFormat(format string) error {
+	if !slices.Contains(formats, format) {
+		return fmt.Errorf("--format must be one of %s, got %q", strings.Join(formats, ", "), format)
+	}
+	return nil
+}
+
+// column is one requested metric of a section.
+type column struct {
+	name   string
+	unit   string
+	values []any
+}
+
+// header returns the column heading, e.g. "temperature_2m (°C)".
+func (c column) header() string {
+	if c.unit == "" {
+		return c.name
+	}
+	return fmt.Sprintf("%s (%s)", c.name, c.unit)
+}
+
+// section is a block of the response, such as the hourly data, holding the
+// requested metrics in request order.
+type section struct {
+	name    string
+	time    []string
+	columns []column
+}
+
+var sectionTitles = map[string]string{
+	"current":     "Current",
+	"minutely_15": "15-Minutely",
+	"hourly":      "Hourly",
+	"daily":       "Daily",
+	"weekly":      "Weekly",
+	"monthly":     "Monthly",
+}
+
+// sections returns the sections of wd holding the metrics requested in o,
+// skipping empty ones. When several models were requested, each metric has
+// a column per model, named as in the API, e.g. temperature_2m_gfs_seamless.
+func sections(wd *openmeteogo.WeatherData, o *openmeteogo.Options) ([]section, error) {
+	type part struct {
+		name        string
+		data, units any
+		metrics     openmeteogo.Metrics
+		models      func(*openmeteogo.ModelData) (data, units any)
+	}
+	parts := []part{
+		{"current", wd.Current, wd.CurrentUnits, o.CurrentMetrics,
+			func(m *openmeteogo.ModelData) (any, any) { return m.Current, m.CurrentUnits }},
+		{"minutely_15", wd.Minutely15, wd.Minutely15Units, o.Minutely15Metrics,
+			func(m *openmeteogo.ModelData) (any, any) { return m.Minutely15, m.Minutely15Units }},
+		{"hourly", wd.Hourly, wd.HourlyUnits, o.HourlyMetrics,
+			func(m *openmeteogo.ModelData) (any, any) { return m.Hourly, m.HourlyUnits }},
+		{"daily", wd.Daily, wd.DailyUnits, o.DailyMetrics,
+			func(m *openmeteogo.ModelData) (any, any) { return m.Daily, m.DailyUnits }},
+		{"weekly", wd.Weekly, wd.WeeklyUnits, o.WeeklyMetrics, nil},
+		{"monthly", wd.Monthly, wd.MonthlyUnits, o.MonthlyMetrics, nil},
+	}
+
+	models := make([]string, 0, len(wd.ByModel))
+	for m := range wd.ByModel {
+		models = append(models, m)
+	}
+	slices.Sort(models)
+
+	var result []section
+	for _, p := range parts {
+		if len(p.metrics) == 0 {
+			continue
+		}
+		s := section{name: p.name}
+		if len(models) == 0 || p.models == nil {
+			if err := s.add(p.data, p.units, p.metrics, ""); err != nil {
+				return nil, fmt.Errorf("%s: %w", p.name, err)
+			}
+		} else {
+			for _, m := range models {
+				data, units := p.models(wd.ByModel[m])
+				if err := s.add(data, units, p.metrics, "_"+m); err != nil {
+					return nil, fmt.Errorf("%s: %w", p.name, err)
+				}
+			}
+		}
+		if len(s.time) > 0 && len(s.columns) > 0 {
+			result = append(result, s)
+		}
+	}
+	return result, nil
+}
+
+// add appends the columns of data, a section struct, for the given metrics,
+// suffixing their names. It goes through the JSON encoding so that every
+// field, including pressure level variables, is found by its API name.
+func (s *section) add(data, units any, metrics openmeteogo.Metrics, suffix string) error {
+	var fields map[string]json.RawMessage
+	if err := roundTrip(data, &fields); err != nil {
+		return err
+	}
+	var labels map[string]any
+	if err := roundTrip(units, &labels); err != nil {
+		return err
+	}
+
+	if raw, ok := fields["time"]; ok && s.time == nil {
+		var t string
+		if err := json.Unmarshal(raw, &s.time); err != nil {
+			if err := json.Unmarshal(raw, &t); err != nil {
+				return fmt.Errorf("time: %w", err)
+			}
+			s.time = []string{t}
+		}
+	}
+
+	for _, m := range metrics {
+		raw, ok := fields[string(m)]
+		if !ok || string(raw) == "null" {
+			continue
+		}
+		var values []any
+		if err := json.Unmarshal(raw, &values); err != nil {
+			var v any
+			if err := json.Unmarshal(raw, &v); err != nil {
+				return fmt.Errorf("%s: %w", m, err)
+			}
+			values = []any{v}
+		}
+		unit, _ := labels[string(m)].(string)
+		s.columns = append(s.columns, column{name: string(m) + suffix, unit: unit, values: values})
+	}
+	return nil
+}
+
+func roundTrip(in, out any) error {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+// write writes wd in the given format. The table and CSV formats hold the
+// metrics requested in o. If chart is not nil, tables of time series are
+// drawn as charts.
+func write(w io.Writer, format string, chart *chartOptions, loc openmeteogo.Location, wd *openmeteogo.WeatherData, o *openmeteogo.Options) error {
+	switch format {
+	case "json":
+		return writeJSON(w, wd)
+	case "csv":
+		return openmeteogo.WriteCSV(w, wd, o)
+	}
+	secs, err := sections(wd, o)
+	if err != nil {
+		return err
+	}
+	return writeTable(w, chart, loc, wd, secs)
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// writeTable writes a heading for the place followed by a table, or a chart,
+// for each section.
+func writeTable(w io.Writer, chart *chartOptions, loc openmeteogo.Location, wd *openmeteogo.WeatherData, secs []section) error {
+	fmt.Fprintln(w, placeName(loc, wd))
+	for _, s := range secs {
+		fmt.Fprintf(w, "\n%s\n", sectionTitles[s.name])
+		if chart != nil && s.name != "current" {
+			if err := writeChart(w, s, *chart); err != nil {
+				return err
+			}
+			continue
+		}
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		if s.name == "current" {
+			fmt.Fprintf(tw, "time\t%s\n", s.time[0])
+			for _, c := range s.columns {
+				fmt.Fprintf(tw, "%s\t%s\n", c.name, withUnit(cell(c, 0), c.unit))
+			}
+		} else {
+			headers := []string{"time"}
+			for _, c := range s.columns {
+				headers = append(headers, c.header())
+			}
+			fmt.Fprintln(tw, strings.Join(headers, "\t"))
+			for i, t := range s.time {
+				row := []string{t}
+				for _, c := range s.columns {
+					row = append(row, cell(c, i))
+				}
+				fmt.Fprintln(tw, strings.Join(row, "\t"))
+			}
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeLocations writes the results of the geocode command.
+func writeLocations(w io.Writer, format string, locations []openmeteogo.Location) error {
+	switch format {
+	case "json":
+		return writeJSON(w, locations)
+	case "csv":
+		cw := csv.NewWriter(w)
+		cw.Write([]string{"name", "admin1", "country", "latitude", "longitude", "elevation", "timezone", "population"})
+		for _, l := range locations {
+			cw.Write([]string{l.Name, l.Admin1, l.Country, formatFloat(l.Latitude), formatFloat(l.Longitude),
+				formatFloat(l.Elevation), l.Timezone, strconv.Itoa(l.Population)})
+		}
+		cw.Flush()
+		return cw.Error()
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "name\tregion\tcountry\tlatitude\tlongitude\televation\ttimezone")
+	for _, l := range locations {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s m\t%s\n", l.Name, l.Admin1, l.Country,
+			formatFloat(l.Latitude), formatFloat(l.Longitude), formatFloat(l.Elevation), l.Timezone)
+	}
+	return tw.Flush()
+}
+
+// placeName describes the place, e.g.
+// "Berlin, Land Berlin, Germany (52.52, 13.42, 38 m, Europe/Berlin)".
+func placeName(loc openmeteogo.Location, wd *openmeteogo.WeatherData) string {
+	var parts []string
+	for _, p := range []string{loc.Name, loc.Admin1, loc.Country} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	coords := fmt.Sprintf("%s, %s, %s m", formatFloat(wd.Latitude), formatFloat(wd.Longitude), formatFloat(wd.Elevation))
+	if wd.Timezone != "" {
+		coords += ", " + wd.Timezone
+	}
+	if len(parts) == 0 {
+		return coords
+	}
+	return fmt.Sprintf("%s (%s)", strings.Join(parts, ", "), coords)
+}
+
+// cell formats the i-th value of a column for a table. Missing values are
+// "-", and weather codes have their descriptions.
+func cell(c column, i int) string {
+	var v any
+	if i < len(c.values) {
+		v = c.values[i]
+	}
+	switch v := v.(type) {
+	case nil:
+		return "-"
+	case float64:
+		if strings.HasPrefix(c.name, string(openmeteogo.WeatherCode)) {
+			return fmt.Sprintf("%s %s", formatFloat(v), openmeteogo.DescribeCode(int(v)))
+		}
+		return formatFloat(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// withUnit appends unit to a table value, unless the value is missing or
+// the unit only labels a code or timestamp.
+func withUnit(value, unit string) string {
+	if value == "-" || unit == "" || unit == "wmo code" || unit == "iso8601" || unit == "unixtime" {
+		return value
+	}
+	return value + " " + unit
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,243 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/tpryan/openmeteogo"
+)
+
+// Current takes GeoJSON properties from WeatherData.Current.
+const Current Section = "current"
+
+// The properties of a GeoJSON feature besides the metrics.
+const (
+	// PropertyName names the location, as Location.Name.
+	PropertyName = "name"
+	// PropertyTime is the timestamp of the values, as in the response. In a
+	// time series it is an array.
+	PropertyTime = "time"
+	// PropertyUnits maps each metric property to its unit label.
+	PropertyUnits = "units"
+)
+
+// FeatureCollection is a GeoJSON FeatureCollection. It encodes as GeoJSON
+// with encoding/json.
+type FeatureCollection struct {
+	Type     string    `json:"type"`
+	Features []Feature `json:"features"`
+}
+
+// Feature is a GeoJSON Feature for one location.
+type Feature struct {
+	Type       string         `json:"type"`
+	Geometry   Geometry       `json:"geometry"`
+	Properties map[string]any `json:"properties"`
+}
+
+// Geometry is a GeoJSON Point.
+type Geometry struct {
+	Type string `json:"type"`
+	// Coordinates are the longitude, latitude and elevation in metres.
+	Coordinates []float64 `json:"coordinates"`
+}
+
+// GeoJSONConfig describes the properties of a GeoJSON export.
+type GeoJSONConfig struct {
+	// Section is the series the properties are taken from. The default is
+	// Current for GeoJSON and Hourly for GeoJSONTimeSeries.
+	Section Section
+	// Time selects the timestep of an Hourly or Daily snapshot: the hour, or
+	// the day in the location's timezone, containing Time. If zero, the
+	// first timestep is used. Time series ignore it.
+	Time time.Time
+	// Metrics are the metric properties. If empty, every numeric metric
+	// returned for a location is included.
+	Metrics openmeteogo.Metrics
+}
+
+// GeoJSON returns a FeatureCollection with a Point feature per location,
+// for map layers. The properties of each feature are PropertyName,
+// PropertyTime, PropertyUnits and the value of each metric at the
+// selected timestep, null where it is NaN.
+func GeoJSON(locations []Location, cfg GeoJSONConfig) (*FeatureCollection, error) {
+	if cfg.Section == "" {
+		cfg.Section = Current
+	}
+	fc := &FeatureCollection{Type: "FeatureCollection", Features: []Feature{}}
+	for i, l := range locations {
+		if l.Data == nil {
+			return nil, fmt.Errorf("location %d has no data", i)
+		}
+		times, units, values, err := geoSeries(l.Data, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("location %s: %w", name(l), err)
+		}
+		step, err := timestep(l.Data, cfg, times)
+		if err != nil {
+			return nil, fmt.Errorf("location %s: %w", name(l), err)
+		}
+
+		f := newFeature(l, times[step], units)
+		for m, v := range values {
+			f.Properties[string(m)] = jsonValue(v[step])
+		}
+		fc.Features = append(fc.Features, f)
+	}
+	return fc, nil
+}
+
+// GeoJSONTimeSeries returns a FeatureCollection with a Point feature per
+// location, for animated maps. As with GeoJSON, but PropertyTime and each
+// metric property are arrays holding the whole series.
+func GeoJSONTimeSeries(locations []Location, cfg GeoJSONConfig) (*FeatureCollection, error) {
+	if cfg.Section == "" {
+		cfg.Section = Hourly
+	}
+	if cfg.Section == Current {
+		return nil, fmt.Errorf("unsupported section %q for a time series", cfg.Section)
+	}
+	fc := &FeatureCollection{Type: "FeatureCollection", Features: []Feature{}}
+	for i, l := range locations {
+		if l.Data == nil {
+			return nil, fmt.Errorf("location %d has no data", i)
+		}
+		times, units, values, err := geoSeries(l.Data, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("location %s: %w", name(l), err)
+		}
+
+		f := newFeature(l, times, units)
+		for m, v := range values {
+			series := make([]any, len(v))
+			for j, x := range v {
+				series[j] = jsonValue(x)
+			}
+			f.Properties[string(m)] = series
+		}
+		fc.Features = append(fc.Features, f)
+	}
+	return fc, nil
+}
+
+// newFeature returns the feature for a location with its name, time and
+// units properties.
+func newFeature(l Location, ts any, units map[openmeteogo.Metric]string) Feature {
+	return Feature{
+		Type: "Feature",
+		Geometry: Geometry{
+			Type:        "Point",
+			Coordinates: []float64{l.Data.Longitude, l.Data.Latitude, l.Data.Elevation},
+		},
+		Properties: map[string]any{
+			PropertyName:  name(l),
+			PropertyTime:  ts,
+			PropertyUnits: units,
+		},
+	}
+}
+
+// geoSeries returns the timestamps of the section, and the unit label and
+// series of each metric in cfg, or of every numeric metric if it has none.
+// The current section is a series of one.
+func geoSeries(wd *openmeteogo.WeatherData, cfg GeoJSONConfig) ([]string, map[openmeteogo.Metric]string, map[openmeteogo.Metric][]float64, error) {
+	var sec *section
+	switch cfg.Section {
+	case Current:
+		sec = &section{
+			times: []string{wd.Current.Time},
+			unit:  wd.CurrentUnits.Unit,
+			values: func(m openmeteogo.Metric) ([]float64, error) {
+				if !slices.Contains(sec.metrics, m) {
+					return nil, fmt.Errorf("no data for metric: %s", m)
+				}
+				v, err := wd.Current.Value(m)
+				return []float64{v}, err
+			},
+		}
+		for _, m := range labelled(wd.CurrentUnits) {
+			if m != "interval" {
+				sec.metrics = append(sec.metrics, m)
+			}
+		}
+	case Hourly, Daily:
+		sec = sectionSeries(wd, cfg.Section)
+	default:
+		return nil, nil, nil, fmt.Errorf("unsupported section %q", cfg.Section)
+	}
+	if len(sec.times) == 0 || sec.times[0] == "" {
+		return nil, nil, nil, fmt.Errorf("no %s data", cfg.Section)
+	}
+
+	units := map[openmeteogo.Metric]string{}
+	values := map[openmeteogo.Metric][]float64{}
+	metrics := cfg.Metrics
+	if len(metrics) == 0 {
+		metrics = sec.metrics
+	}
+	for _, m := range metrics {
+		v, err := sec.values(m)
+		if err != nil {
+			if len(cfg.Metrics) > 0 {
+				return nil, nil, nil, err
+			}
+			// Not returned, or not numeric, such as sunrise.
+			continue
+		}
+		unit, err := sec.unit(m)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		units[m], values[m] = unit.String(), v
+	}
+	return sec.times, units, values, nil
+}
+
+// timestep returns the index of the timestep cfg selects.
+func timestep(wd *openmeteogo.WeatherData, cfg GeoJSONConfig, times []string) (int, error) {
+	if cfg.Time.IsZero() || cfg.Section == Current {
+		return 0, nil
+	}
+	loc := timezone(wd)
+	if cfg.Section == Daily {
+		i := slices.Index(times, cfg.Time.In(loc).Format(openmeteogo.DateFormat))
+		if i < 0 {
+			return 0, fmt.Errorf("no daily data for %s", cfg.Time.In(loc).Format(openmeteogo.DateFormat))
+		}
+		return i, nil
+	}
+	for i, ts := range times {
+		at, err := time.ParseInLocation(openmeteogo.HourFormat, ts, loc)
+		if err != nil {
+			return 0, fmt.Errorf("time %q: %w", ts, err)
+		}
+		if !cfg.Time.Before(at) && cfg.Time.Before(at.Add(time.Hour)) {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("no hourly data for %s", cfg.Time.Format(time.RFC3339))
+}
+
+// jsonValue returns v, or nil for NaN, which JSON cannot encode.
+func jsonValue(v float64) any {
+	if math.IsNaN(v) {
+		return nil
+	}
+	return v
+}
@@ -0,0 +1,170 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tpryan/openmeteogo"
+)
+
+// geoJSON encodes fc for comparison with the expected GeoJSON.
+func geoJSON(t *testing.T, fc *FeatureCollection) string {
+	t.Helper()
+	data, err := json.Marshal(fc)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestGeoJSON_Current(t *testing.T) {
+	current := *berlin
+	current.CurrentUnits = openmeteogo.CurrentUnits{Time: "iso8601", Interval: "seconds", Temperature2m: "°C", WeatherCode: "wmo code"}
+	current.Current = openmeteogo.Current{Time: "2025-01-01T12:00", Interval: 900, Temperature2m: 3.5, WeatherCode: 61}
+
+	fc, err := GeoJSON([]Location{{Name: "berlin", Data: &current}}, GeoJSONConfig{})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "FeatureCollection",
+		"features": [{
+			"type": "Feature",
+			"geometry": {"type": "Point", "coordinates": [13.42, 52.52, 38]},
+			"properties": {
+				"name": "berlin",
+				"time": "2025-01-01T12:00",
+				"units": {"temperature_2m": "°C", "weather_code": "wmo code"},
+				"temperature_2m": 3.5,
+				"weather_code": 61
+			}
+		}]
+	}`, geoJSON(t, fc))
+
+	_, err = GeoJSON([]Location{{Name: "berlin", Data: &current}}, GeoJSONConfig{Metrics: openmeteogo.Metrics{openmeteogo.Rain}})
+	assert.EqualError(t, err, "location berlin: no data for metric: rain", "not a zero without a unit")
+
+	current.CurrentUnits.WindSpeed10m = "furlong/fortnight"
+	fc, err = GeoJSON([]Location{{Name: "berlin", Data: &current}}, GeoJSONConfig{Metrics: openmeteogo.Metrics{openmeteogo.Temperature2m}})
+	require.NoError(t, err, "only the label of temperature_2m is parsed")
+	assert.Equal(t, map[openmeteogo.Metric]string{openmeteogo.Temperature2m: "°C"}, fc.Features[0].Properties[PropertyUnits])
+}
+
+func TestGeoJSON_Timestep(t *testing.T) {
+	locations := []Location{{Name: "berlin", Data: berlin}, {Data: denver}}
+	// Midnight UTC is 01:00 in Berlin and 17:00 the day before in Denver.
+	at := time.Date(2025, 1, 1, 0, 30, 0, 0, time.UTC)
+
+	fc, err := GeoJSON(locations, GeoJSONConfig{Section: Hourly, Time: at})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "FeatureCollection",
+		"features": [{
+			"type": "Feature",
+			"geometry": {"type": "Point", "coordinates": [13.42, 52.52, 38]},
+			"properties": {
+				"name": "berlin",
+				"time": "2025-01-01T01:00",
+				"units": {"temperature_2m": "°C", "precipitation": "mm"},
+				"temperature_2m": null,
+				"precipitation": 0.3
+			}
+		}, {
+			"type": "Feature",
+			"geometry": {"type": "Point", "coordinates": [-104.99, 39.74, 1609]},
+			"properties": {
+				"name": "39.74,-104.99",
+				"time": "2024-12-31T17:00",
+				"units": {"temperature_2m": "°C", "relative_humidity_2m": "%"},
+				"temperature_2m": -3,
+				"relative_humidity_2m": 40
+			}
+		}]
+	}`, geoJSON(t, fc))
+
+	fc, err = GeoJSON(locations[:1], GeoJSONConfig{Section: Daily, Time: at, Metrics: openmeteogo.Metrics{openmeteogo.Temperature2mMax}})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"name":               "berlin",
+		"time":               "2025-01-01",
+		"units":              map[openmeteogo.Metric]string{openmeteogo.Temperature2mMax: "°C"},
+		"temperature_2m_max": 4.2,
+	}, fc.Features[0].Properties)
+}
+
+func TestGeoJSONTimeSeries(t *testing.T) {
+	fc, err := GeoJSONTimeSeries([]Location{{Name: "berlin", Data: berlin}}, GeoJSONConfig{})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "FeatureCollection",
+		"features": [{
+			"type": "Feature",
+			"geometry": {"type": "Point", "coordinates": [13.42, 52.52, 38]},
+			"properties": {
+				"name": "berlin",
+				"time": ["2025-01-01T00:00", "2025-01-01T01:00"],
+				"units": {"temperature_2m": "°C", "precipitation": "mm"},
+				"temperature_2m": [1.5, null],
+				"precipitation": [0, 0.3]
+			}
+		}]
+	}`, geoJSON(t, fc))
+}
+
+func TestGeoJSON_Errors(t *testing.T) {
+	tests := map[string]struct {
+		fn      func([]Location, GeoJSONConfig) (*FeatureCollection, error)
+		cfg     GeoJSONConfig
+		wantErr string
+	}{
+		"no current data": {
+			fn:      GeoJSON,
+			wantErr: "location berlin: no current data",
+		},
+		"hour not returned": {
+			fn:      GeoJSON,
+			cfg:     GeoJSONConfig{Section: Hourly, Time: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)},
+			wantErr: "location berlin: no hourly data for 2025-01-02T00:00:00Z",
+		},
+		"day not returned": {
+			fn:      GeoJSON,
+			cfg:     GeoJSONConfig{Section: Daily, Time: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)},
+			wantErr: "location berlin: no daily data for 2025-01-02",
+		},
+		"metric not returned": {
+			fn:      GeoJSON,
+			cfg:     GeoJSONConfig{Section: Hourly, Metrics: openmeteogo.Metrics{openmeteogo.Rain}},
+			wantErr: "location berlin: no data for metric: rain",
+		},
+		"current time series": {
+			fn:      GeoJSONTimeSeries,
+			cfg:     GeoJSONConfig{Section: Current},
+			wantErr: `unsupported section "current" for a time series`,
+		},
+		"unknown section": {
+			fn:      GeoJSONTimeSeries,
+			cfg:     GeoJSONConfig{Section: "weekly"},
+			wantErr: `location berlin: unsupported section "weekly"`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := tc.fn([]Location{{Name: "berlin", Data: berlin}}, tc.cfg)
+			assert.EqualError(t, err, tc.wantErr)
+		})
+	}
+}
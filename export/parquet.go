@@ -0,0 +1,531 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package export writes WeatherData for one or many locations to Parquet,
+// with one row per location and timestamp, for loading into data platforms.
+// HourlyWriter writes the chunks of a streamed hourly series as they arrive.
+// WeatherData decodes the API's nulls as zero, so WriteParquet writes them as
+// zero; HourlyWriter writes them as null.
+// GeoJSON and GeoJSONTimeSeries return locations as a GeoJSON
+// FeatureCollection for map layers.
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/compress"
+	"github.com/parquet-go/parquet-go/encoding"
+	"github.com/parquet-go/parquet-go/encoding/thrift"
+	"github.com/parquet-go/parquet-go/format"
+	"github.com/tpryan/openmeteogo"
+)
+
+// The columns written before the metric columns, in order.
+const (
+	// ColumnLocation names the location, as Location.Name.
+	ColumnLocation = "location"
+	// ColumnLatitude, ColumnLongitude and ColumnElevation are the
+	// coordinates of the location, as returned by the API.
+	ColumnLatitude  = "latitude"
+	ColumnLongitude = "longitude"
+	ColumnElevation = "elevation"
+	// ColumnTime is the UTC timestamp of the row, with millisecond
+	// precision. Daily rows are at local midnight.
+	ColumnTime = "time"
+)
+
+// UnitKey is the key in the column chunk metadata of each metric column
+// that holds its unit, e.g. "°C".
+const UnitKey = "unit"
+
+// UnitKeyPrefix prefixes the file metadata keys that also hold the unit of
+// each metric column, for readers that do not expose column chunk metadata,
+// e.g. "openmeteo.unit.temperature_2m" is "°C".
+const UnitKeyPrefix = "openmeteo.unit."
+
+// Section selects the series written as rows.
+type Section string
+
+const (
+	// Hourly writes a row per hour, from WeatherData.Hourly.
+	Hourly Section = "hourly"
+	// Daily writes a row per day, from WeatherData.Daily.
+	Daily Section = "daily"
+)
+
+// Location is the data for one location.
+type Location struct {
+	// Name identifies the location in the location column, e.g. a site ID.
+	// If empty, it is "latitude,longitude".
+	Name string
+	// Data is the response for the location.
+	Data *openmeteogo.WeatherData
+}
+
+// Config describes what to write.
+type Config struct {
+	// Section is the series written as rows. The default is Hourly.
+	Section Section
+	// Metrics are the metric columns, in order. If empty, every numeric
+	// metric returned for any location is written, ordered by name, so the
+	// schema depends only on the metrics and not on the order of the
+	// locations.
+	Metrics openmeteogo.Metrics
+}
+
+// table is the rows and schema of an export.
+type table struct {
+	schema  *parquet.Schema
+	metrics openmeteogo.Metrics
+	units   map[openmeteogo.Metric]string
+	rows    []row
+}
+
+// row is a row before conversion to Parquet values, so that rows can be
+// partitioned by date.
+type row struct {
+	location            string
+	lat, lon, elevation float64
+	time                time.Time
+	// date is the partition of the row, formatted as DateFormat.
+	date    string
+	values  []float64
+	present []bool
+}
+
+// WriteParquet writes the locations to w as a Parquet file with a row per
+// location and timestamp. The columns are ColumnLocation, ColumnLatitude,
+// ColumnLongitude, ColumnElevation and ColumnTime, followed by a nullable
+// double column per metric, named as in the API. Values are null where a
+// location has no data for a metric, or the value is NaN. Individual nulls
+// in a JSON response are decoded into WeatherData as zero, so they are
+// written as zero; stream the response to a HourlyWriter to keep them.
+//
+// The unit of each metric column is stored in its column chunk metadata
+// under UnitKey, and in the file's key/value metadata under UnitKeyPrefix.
+// A metric must have the same unit for every location; use
+// openmeteogo.Convert to bring them into line. Only the unit labels of the
+// metrics written are parsed.
+func WriteParquet(w io.Writer, locations []Location, cfg Config) error {
+	t, err := newTable(locations, cfg)
+	if err != nil {
+		return err
+	}
+	return t.write(w, t.rows)
+}
+
+// WriteParquetPartitioned writes the locations under dir as one Parquet file
+// per date, in the Hive layout many query engines read as a date
+// partition, e.g. dir/date=2025-01-01/data.parquet. Hourly rows are
+// partitioned by their UTC date. Daily rows are partitioned by the date in
+// the response, as their time is midnight in the location's timezone,
+// which is the day before in UTC east of Greenwich. The files are as
+// written by WriteParquet, and all have the same schema. It returns the
+// paths of the files written.
+func WriteParquetPartitioned(dir string, locations []Location, cfg Config) ([]string, error) {
+	t, err := newTable(locations, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	partitions := map[string][]row{}
+	var dates []string
+	for _, r := range t.rows {
+		if _, ok := partitions[r.date]; !ok {
+			dates = append(dates, r.date)
+		}
+		partitions[r.date] = append(partitions[r.date], r)
+	}
+	slices.Sort(dates)
+
+	var paths []string
+	for _, date := range dates {
+		path := filepath.Join(dir, "date="+date, "data.parquet")
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return paths, err
+		}
+		f, err := os.Create(path)
+		if err != nil {
+			return paths, err
+		}
+		err = t.write(f, partitions[date])
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return paths, fmt.Errorf("writing %s: %w", path, err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// newTable builds the rows and schema for the locations.
+func newTable(locations []Location, cfg Config) (*table, error) {
+	if cfg.Section == "" {
+		cfg.Section = Hourly
+	}
+	if cfg.Section != Hourly && cfg.Section != Daily {
+		return nil, fmt.Errorf("unsupported section %q", cfg.Section)
+	}
+
+	t := &table{metrics: cfg.Metrics, units: map[openmeteogo.Metric]string{}}
+	discover := len(t.metrics) == 0
+	type series struct {
+		times  []string
+		values map[openmeteogo.Metric][]float64
+	}
+	all := make([]series, len(locations))
+	for i, l := range locations {
+		if l.Data == nil {
+			return nil, fmt.Errorf("location %d has no data", i)
+		}
+		sec := sectionSeries(l.Data, cfg.Section)
+		all[i] = series{sec.times, map[openmeteogo.Metric][]float64{}}
+		for _, m := range sec.metrics {
+			if !discover && !slices.Contains(t.metrics, m) {
+				continue
+			}
+			v, err := sec.values(m)
+			if err != nil {
+				// Not returned, or not numeric, such as sunrise.
+				if !discover {
+					return nil, fmt.Errorf("location %s: %w", name(l), err)
+				}
+				continue
+			}
+			parsed, err := sec.unit(m)
+			if err != nil {
+				return nil, fmt.Errorf("location %s: %w", name(l), err)
+			}
+			unit := parsed.String()
+			if u, ok := t.units[m]; ok && u != unit {
+				return nil, fmt.Errorf("location %s: %s is in %s, other locations in %s", name(l), m, unit, u)
+			}
+			t.units[m] = unit
+			all[i].values[m] = v
+			if discover && !slices.Contains(t.metrics, m) {
+				t.metrics = append(t.metrics, m)
+			}
+		}
+	}
+	if discover {
+		slices.Sort(t.metrics)
+	}
+	for _, m := range t.metrics {
+		if slices.Contains([]string{ColumnLocation, ColumnLatitude, ColumnLongitude, ColumnElevation, ColumnTime}, string(m)) {
+			return nil, fmt.Errorf("metric %s clashes with a location column", m)
+		}
+	}
+
+	for i, l := range locations {
+		loc := timezone(l.Data)
+		for j, ts := range all[i].times {
+			parse := openmeteogo.HourFormat
+			if cfg.Section == Daily {
+				parse = openmeteogo.DateFormat
+			}
+			at, err := time.ParseInLocation(parse, ts, loc)
+			if err != nil {
+				return nil, fmt.Errorf("location %s: time %q: %w", name(l), ts, err)
+			}
+			r := row{
+				location:  name(l),
+				lat:       l.Data.Latitude,
+				lon:       l.Data.Longitude,
+				elevation: l.Data.Elevation,
+				time:      at.UTC(),
+				date:      at.UTC().Format(openmeteogo.DateFormat),
+				values:    make([]float64, len(t.metrics)),
+				present:   make([]bool, len(t.metrics)),
+			}
+			if cfg.Section == Daily {
+				r.date = ts
+			}
+			for k, m := range t.metrics {
+				v := all[i].values[m]
+				if j < len(v) && !math.IsNaN(v[j]) {
+					r.values[k], r.present[k] = v[j], true
+				}
+			}
+			t.rows = append(t.rows, r)
+		}
+	}
+
+	t.schema = newSchema(t.metrics)
+	return t, nil
+}
+
+// newSchema returns the schema for the location columns followed by the
+// metric columns.
+func newSchema(metrics openmeteogo.Metrics) *parquet.Schema {
+	fields := []parquet.Field{
+		field{parquet.String(), ColumnLocation},
+		field{parquet.Leaf(parquet.DoubleType), ColumnLatitude},
+		field{parquet.Leaf(parquet.DoubleType), ColumnLongitude},
+		field{parquet.Leaf(parquet.DoubleType), ColumnElevation},
+		field{parquet.Timestamp(parquet.Millisecond), ColumnTime},
+	}
+	for _, m := range metrics {
+		fields = append(fields, field{parquet.Optional(parquet.Leaf(parquet.DoubleType)), string(m)})
+	}
+	return parquet.NewSchema("weather", group{fields})
+}
+
+// write writes rows of the table as a Parquet file.
+func (t *table) write(w io.Writer, rows []row) error {
+	fw := newWriter(w, t.schema, t.units)
+	if err := writeRows(fw.Writer, rows); err != nil {
+		return err
+	}
+	return fw.Close()
+}
+
+// fileWriter is a Parquet writer that also stores the unit of each metric
+// column in its column chunk metadata, which the parquet package does not
+// write. The footer is rewritten with the units on Close.
+type fileWriter struct {
+	*parquet.Writer
+	out   *tailWriter
+	units map[openmeteogo.Metric]string
+}
+
+// newWriter returns a Parquet writer for the schema, with the units of the
+// metric columns in the column chunk and file metadata.
+func newWriter(w io.Writer, schema *parquet.Schema, units map[openmeteogo.Metric]string) *fileWriter {
+	options := []parquet.WriterOption{schema, parquet.Compression(&parquet.Snappy)}
+	for m, unit := range units {
+		options = append(options, parquet.KeyValueMetadata(UnitKeyPrefix+string(m), unit))
+	}
+	out := &tailWriter{w: w}
+	return &fileWriter{parquet.NewWriter(out, options...), out, units}
+}
+
+// Close writes the remaining rows, then the footer with the units added to
+// the metadata of each metric column chunk.
+func (fw *fileWriter) Close() error {
+	// Write the last row group through, so that only the page index and
+	// the footer are held back.
+	if err := fw.Writer.Flush(); err != nil {
+		return err
+	}
+	fw.out.tail = new(bytes.Buffer)
+	if err := fw.Writer.Close(); err != nil {
+		return err
+	}
+
+	tail := fw.out.tail.Bytes()
+	end := len(tail) - 8
+	if end < 0 || string(tail[end+4:]) != "PAR1" {
+		return fmt.Errorf("parquet footer not found")
+	}
+	start := end - int(binary.LittleEndian.Uint32(tail[end:]))
+	if start < 0 {
+		return fmt.Errorf("parquet footer not found")
+	}
+	var md format.FileMetaData
+	if err := thrift.Unmarshal(new(thrift.CompactProtocol), tail[start:end], &md); err != nil {
+		return fmt.Errorf("reading parquet footer: %w", err)
+	}
+	for i := range md.RowGroups {
+		for j := range md.RowGroups[i].Columns {
+			c := &md.RowGroups[i].Columns[j].MetaData
+			if len(c.PathInSchema) != 1 {
+				continue
+			}
+			if unit, ok := fw.units[openmeteogo.Metric(c.PathInSchema[0])]; ok {
+				c.KeyValueMetadata = append(c.KeyValueMetadata, format.KeyValue{Key: UnitKey, Value: unit})
+			}
+		}
+	}
+	footer, err := thrift.Marshal(new(thrift.CompactProtocol), &md)
+	if err != nil {
+		return fmt.Errorf("writing parquet footer: %w", err)
+	}
+
+	footer = binary.LittleEndian.AppendUint32(footer, uint32(len(footer)))
+	for _, b := range [][]byte{tail[:start], footer, []byte("PAR1")} {
+		if _, err := fw.out.w.Write(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tailWriter passes writes through to w until tail is set, and then holds
+// them in tail.
+type tailWriter struct {
+	w    io.Writer
+	tail *bytes.Buffer
+}
+
+func (t *tailWriter) Write(p []byte) (int, error) {
+	if t.tail != nil {
+		return t.tail.Write(p)
+	}
+	return t.w.Write(p)
+}
+
+// writeRows writes rows to pw.
+func writeRows(pw *parquet.Writer, rows []row) error {
+	const fixed = 5
+	buf := make([]parquet.Row, 0, 1024)
+	flush := func() error {
+		if _, err := pw.WriteRows(buf); err != nil {
+			return err
+		}
+		buf = buf[:0]
+		return nil
+	}
+	for _, r := range rows {
+		values := make(parquet.Row, 0, fixed+len(r.values))
+		values = append(values,
+			parquet.ByteArrayValue([]byte(r.location)).Level(0, 0, 0),
+			parquet.DoubleValue(r.lat).Level(0, 0, 1),
+			parquet.DoubleValue(r.lon).Level(0, 0, 2),
+			parquet.DoubleValue(r.elevation).Level(0, 0, 3),
+			parquet.Int64Value(r.time.UnixMilli()).Level(0, 0, 4),
+		)
+		for k, v := range r.values {
+			if r.present[k] {
+				values = append(values, parquet.DoubleValue(v).Level(0, 1, fixed+k))
+			} else {
+				values = append(values, parquet.NullValue().Level(0, 0, fixed+k))
+			}
+		}
+		buf = append(buf, values)
+		if len(buf) == cap(buf) {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return flush()
+}
+
+// section is a series section of a response.
+type section struct {
+	// times holds the timestamps of the section.
+	times []string
+	// metrics holds every metric with a unit label, other than time.
+	metrics openmeteogo.Metrics
+	// unit parses the label of a single metric.
+	unit func(openmeteogo.Metric) (openmeteogo.Unit, error)
+	// values returns the series of a metric.
+	values func(openmeteogo.Metric) ([]float64, error)
+}
+
+// sectionSeries returns a section of wd. Unit labels are only parsed when
+// section.unit is called, so that an unrecognised label only matters if
+// its metric is written.
+func sectionSeries(wd *openmeteogo.WeatherData, s Section) *section {
+	if s == Daily {
+		return &section{wd.Daily.Time, labelled(wd.DailyUnits), wd.DailyUnits.Unit, wd.Daily.Values}
+	}
+	metrics := labelled(wd.HourlyUnits)
+	for level, lu := range wd.HourlyUnits.PressureLevels {
+		for _, v := range labelled(*lu) {
+			if m, err := openmeteogo.PressureLevelMetric(openmeteogo.PressureVariable(v), level); err == nil {
+				metrics = append(metrics, m)
+			}
+		}
+	}
+	return &section{wd.Hourly.Time, metrics, wd.HourlyUnits.Unit, wd.Hourly.Values}
+}
+
+// labelled returns the metrics with a unit label in units, a *Units struct,
+// other than time.
+func labelled(units any) openmeteogo.Metrics {
+	v := reflect.ValueOf(units)
+	var metrics openmeteogo.Metrics
+	for i := 0; i < v.NumField(); i++ {
+		if v.Field(i).Kind() != reflect.String || v.Field(i).String() == "" {
+			continue
+		}
+		name, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("json"), ",")
+		if name != "time" {
+			metrics = append(metrics, openmeteogo.Metric(name))
+		}
+	}
+	return metrics
+}
+
+// timezone returns the timezone of the timestamps in wd. If the named
+// timezone is not available, the UTC offset is used.
+func timezone(wd *openmeteogo.WeatherData) *time.Location {
+	if wd.Timezone != "" {
+		if loc, err := time.LoadLocation(wd.Timezone); err == nil {
+			return loc
+		}
+	}
+	return time.FixedZone(wd.TimezoneAbbreviation, wd.UtcOffsetSeconds)
+}
+
+// name returns the name of a location.
+func name(l Location) string {
+	if l.Name != "" {
+		return l.Name
+	}
+	return strconv.FormatFloat(l.Data.Latitude, 'f', -1, 64) + "," + strconv.FormatFloat(l.Data.Longitude, 'f', -1, 64)
+}
+
+// group is a Parquet group node that keeps its fields in order, unlike
+// parquet.Group, which sorts them by name.
+type group struct {
+	fields []parquet.Field
+}
+
+func (g group) ID() int                     { return 0 }
+func (g group) String() string              { return parquet.Group(g.nodes()).String() }
+func (g group) Type() parquet.Type          { return parquet.Group{}.Type() }
+func (g group) Optional() bool              { return false }
+func (g group) Repeated() bool              { return false }
+func (g group) Required() bool              { return true }
+func (g group) Leaf() bool                  { return false }
+func (g group) Fields() []parquet.Field     { return g.fields }
+func (g group) Encoding() encoding.Encoding { return nil }
+func (g group) Compression() compress.Codec { return nil }
+func (g group) GoType() reflect.Type        { return parquet.Group(g.nodes()).GoType() }
+
+func (g group) nodes() map[string]parquet.Node {
+	nodes := make(map[string]parquet.Node, len(g.fields))
+	for _, f := range g.fields {
+		nodes[f.Name()] = f
+	}
+	return nodes
+}
+
+// field is a named field of a group.
+type field struct {
+	parquet.Node
+	name string
+}
+
+func (f field) Name() string { return f.name }
+
+func (f field) Value(base reflect.Value) reflect.Value {
+	return base.MapIndex(reflect.ValueOf(f.name))
+}
@@ -0,0 +1,224 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tpryan/openmeteogo"
+)
+
+var (
+	berlin = &openmeteogo.WeatherData{
+		Latitude: 52.52, Longitude: 13.42, Elevation: 38,
+		Timezone: "Europe/Berlin", TimezoneAbbreviation: "CET", UtcOffsetSeconds: 3600,
+		HourlyUnits: openmeteogo.HourlyUnits{Time: "iso8601", Temperature2m: "°C", Precipitation: "mm"},
+		Hourly: openmeteogo.Hourly{
+			Time:          []string{"2025-01-01T00:00", "2025-01-01T01:00"},
+			Temperature2m: []float64{1.5, math.NaN()},
+			Precipitation: []float64{0, 0.3},
+		},
+		DailyUnits: openmeteogo.DailyUnits{Time: "iso8601", Temperature2mMax: "°C", Sunrise: "iso8601"},
+		Daily: openmeteogo.Daily{
+			Time:             []string{"2025-01-01"},
+			Temperature2mMax: []float64{4.2},
+			Sunrise:          []string{"2025-01-01T08:17"},
+		},
+	}
+	denver = &openmeteogo.WeatherData{
+		Latitude: 39.74, Longitude: -104.99, Elevation: 1609,
+		Timezone: "America/Denver", TimezoneAbbreviation: "MST", UtcOffsetSeconds: -7 * 3600,
+		HourlyUnits: openmeteogo.HourlyUnits{Time: "iso8601", Temperature2m: "°C", RelativeHumidity2m: "%"},
+		Hourly: openmeteogo.Hourly{
+			Time:               []string{"2024-12-31T17:00"},
+			Temperature2m:      []float64{-3},
+			RelativeHumidity2m: []int{40},
+		},
+	}
+)
+
+// readParquet returns the column names, rows and file of a Parquet file.
+func readParquet(t *testing.T, data []byte) ([]string, [][]any, *parquet.File) {
+	t.Helper()
+	f, err := parquet.OpenFile(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+
+	var columns []string
+	for _, c := range f.Schema().Columns() {
+		columns = append(columns, c[0])
+	}
+
+	var rows [][]any
+	r := parquet.NewReader(f)
+	buf := make([]parquet.Row, 16)
+	for {
+		n, err := r.ReadRows(buf)
+		for _, row := range buf[:n] {
+			var values []any
+			for _, v := range row {
+				switch {
+				case v.IsNull():
+					values = append(values, nil)
+				case v.Kind() == parquet.ByteArray:
+					values = append(values, v.String())
+				case v.Kind() == parquet.Int64:
+					values = append(values, time.UnixMilli(v.Int64()).UTC())
+				default:
+					values = append(values, v.Double())
+				}
+			}
+			rows = append(rows, values)
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+	}
+	return columns, rows, f
+}
+
+// columnUnits returns the UnitKey metadata of each column chunk, by row
+// group and column name.
+func columnUnits(f *parquet.File) []map[string]string {
+	var units []map[string]string
+	for _, rg := range f.Metadata().RowGroups {
+		group := map[string]string{}
+		for _, c := range rg.Columns {
+			for _, kv := range c.MetaData.KeyValueMetadata {
+				if kv.Key == UnitKey {
+					group[c.MetaData.PathInSchema[0]] = kv.Value
+				}
+			}
+		}
+		units = append(units, group)
+	}
+	return units
+}
+
+func TestWriteParquet(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteParquet(&buf, []Location{{Name: "berlin", Data: berlin}, {Data: denver}}, Config{}))
+	columns, rows, f := readParquet(t, buf.Bytes())
+
+	assert.Equal(t, []string{"location", "latitude", "longitude", "elevation", "time",
+		"precipitation", "relative_humidity_2m", "temperature_2m"}, columns,
+		"location columns first, then every metric by name")
+
+	utc := func(s string) time.Time {
+		at, err := time.Parse(openmeteogo.HourFormat, s)
+		require.NoError(t, err)
+		return at
+	}
+	want := [][]any{
+		{"berlin", 52.52, 13.42, 38.0, utc("2024-12-31T23:00"), 0.0, nil, 1.5},
+		{"berlin", 52.52, 13.42, 38.0, utc("2025-01-01T00:00"), 0.3, nil, nil},
+		{"39.74,-104.99", 39.74, -104.99, 1609.0, utc("2025-01-01T00:00"), nil, 40.0, -3.0},
+	}
+	assert.Equal(t, want, rows)
+
+	unit, ok := f.Lookup(UnitKeyPrefix + "temperature_2m")
+	assert.True(t, ok)
+	assert.Equal(t, "°C", unit)
+	unit, _ = f.Lookup(UnitKeyPrefix + "relative_humidity_2m")
+	assert.Equal(t, "%", unit)
+	assert.Equal(t, []map[string]string{{"precipitation": "mm", "relative_humidity_2m": "%", "temperature_2m": "°C"}}, columnUnits(f),
+		"units in the column chunk metadata of the metric columns")
+	assert.True(t, f.Schema().Fields()[4].Type().LogicalType().Timestamp.IsAdjustedToUTC)
+}
+
+func TestWriteParquet_Metrics(t *testing.T) {
+	var buf bytes.Buffer
+	cfg := Config{Section: Daily, Metrics: openmeteogo.Metrics{openmeteogo.Temperature2mMax}}
+	require.NoError(t, WriteParquet(&buf, []Location{{Name: "berlin", Data: berlin}}, cfg))
+	columns, rows, _ := readParquet(t, buf.Bytes())
+
+	assert.Equal(t, []string{"location", "latitude", "longitude", "elevation", "time", "temperature_2m_max"}, columns)
+	assert.Equal(t, [][]any{{"berlin", 52.52, 13.42, 38.0, time.Date(2024, 12, 31, 23, 0, 0, 0, time.UTC), 4.2}}, rows,
+		"daily rows are at local midnight")
+}
+
+func TestWriteParquet_UnrelatedUnit(t *testing.T) {
+	data := *berlin
+	data.HourlyUnits.WindSpeed10m = "furlong/fortnight"
+	var buf bytes.Buffer
+	cfg := Config{Metrics: openmeteogo.Metrics{openmeteogo.Temperature2m}}
+	require.NoError(t, WriteParquet(&buf, []Location{{Name: "berlin", Data: &data}}, cfg),
+		"only the labels of the metrics written are parsed")
+	columns, _, _ := readParquet(t, buf.Bytes())
+	assert.Equal(t, "temperature_2m", columns[len(columns)-1])
+}
+
+func TestWriteParquet_Errors(t *testing.T) {
+	fahrenheit := *denver
+	fahrenheit.HourlyUnits.Temperature2m = "°F"
+
+	tests := map[string]struct {
+		locations []Location
+		cfg       Config
+	}{
+		"mixed units":     {[]Location{{Data: berlin}, {Data: &fahrenheit}}, Config{}},
+		"not numeric":     {[]Location{{Data: berlin}}, Config{Section: Daily, Metrics: openmeteogo.Metrics{openmeteogo.Sunrise}}},
+		"no data":         {[]Location{{Name: "nowhere"}}, Config{}},
+		"unknown section": {[]Location{{Data: berlin}}, Config{Section: "weekly"}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Error(t, WriteParquet(io.Discard, tc.locations, tc.cfg))
+		})
+	}
+}
+
+func TestWriteParquetPartitioned(t *testing.T) {
+	dir := t.TempDir()
+	paths, err := WriteParquetPartitioned(dir, []Location{{Name: "berlin", Data: berlin}, {Name: "denver", Data: denver}}, Config{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "date=2024-12-31", "data.parquet"),
+		filepath.Join(dir, "date=2025-01-01", "data.parquet"),
+	}, paths, "partitioned by UTC date")
+
+	data, err := os.ReadFile(paths[1])
+	require.NoError(t, err)
+	columns, rows, _ := readParquet(t, data)
+	assert.Len(t, columns, 8, "every partition has the full schema")
+	require.Len(t, rows, 2)
+	assert.Equal(t, "berlin", rows[0][0])
+	assert.Equal(t, "denver", rows[1][0])
+}
+
+func TestWriteParquetPartitioned_Daily(t *testing.T) {
+	dir := t.TempDir()
+	paths, err := WriteParquetPartitioned(dir, []Location{{Name: "berlin", Data: berlin}}, Config{Section: Daily})
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "date=2025-01-01", "data.parquet")}, paths,
+		"partitioned by local date, not the UTC date of local midnight")
+
+	data, err := os.ReadFile(paths[0])
+	require.NoError(t, err)
+	_, rows, _ := readParquet(t, data)
+	require.Len(t, rows, 1)
+	assert.Equal(t, time.Date(2024, 12, 31, 23, 0, 0, 0, time.UTC), rows[0][4])
+}
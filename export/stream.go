@@ -0,0 +1,104 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/tpryan/openmeteogo"
+)
+
+// HourlyWriter writes the chunks of a streamed hourly series to a Parquet
+// file, with the columns of WriteParquet. Each chunk is written as a row
+// group, so only one chunk is held in memory. Pass its WriteHourly method to
+// openmeteogo.Client.StreamHourly, and call Close when the stream ends.
+type HourlyWriter struct {
+	w       io.Writer
+	name    string
+	metrics openmeteogo.Metrics
+	units   []string
+	pw      *fileWriter
+}
+
+// NewHourlyWriter returns a HourlyWriter that writes to w. name fills the
+// location column; if empty, it is "latitude,longitude".
+func NewHourlyWriter(w io.Writer, name string) *HourlyWriter {
+	return &HourlyWriter{w: w, name: name}
+}
+
+// WriteHourly writes the rows of a chunk. The schema is taken from the
+// first chunk, and every later chunk must have the same metrics and units.
+// Metrics are written under their names in the response, and NaN values
+// are null.
+func (w *HourlyWriter) WriteHourly(c *openmeteogo.HourlyChunk) error {
+	if w.pw == nil {
+		units := map[openmeteogo.Metric]string{}
+		for i, m := range c.Metrics {
+			if slices.Contains([]string{ColumnLocation, ColumnLatitude, ColumnLongitude, ColumnElevation, ColumnTime}, string(m)) {
+				return fmt.Errorf("metric %s clashes with a location column", m)
+			}
+			units[m] = c.Units[i]
+		}
+		w.metrics, w.units = slices.Clone(c.Metrics), slices.Clone(c.Units)
+		w.pw = newWriter(w.w, newSchema(w.metrics), units)
+	} else if !slices.Equal(w.metrics, c.Metrics) {
+		return fmt.Errorf("chunk metrics %v differ from %v", c.Metrics, w.metrics)
+	} else if !slices.Equal(w.units, c.Units) {
+		return fmt.Errorf("chunk units %v differ from %v", c.Units, w.units)
+	}
+
+	loc := timezone(c.Data)
+	location := name(Location{Name: w.name, Data: c.Data})
+	rows := make([]row, len(c.Time))
+	for i, ts := range c.Time {
+		at, err := time.ParseInLocation(openmeteogo.HourFormat, ts, loc)
+		if err != nil {
+			return fmt.Errorf("time %q: %w", ts, err)
+		}
+		r := row{
+			location:  location,
+			lat:       c.Data.Latitude,
+			lon:       c.Data.Longitude,
+			elevation: c.Data.Elevation,
+			time:      at.UTC(),
+			values:    make([]float64, len(c.Metrics)),
+			present:   make([]bool, len(c.Metrics)),
+		}
+		for k, values := range c.Values {
+			if v := values[i]; !math.IsNaN(v) {
+				r.values[k], r.present[k] = v, true
+			}
+		}
+		rows[i] = r
+	}
+	if err := writeRows(w.pw.Writer, rows); err != nil {
+		return err
+	}
+	return w.pw.Flush()
+}
+
+// Close writes the file footer. It returns an error if no chunk was
+// written, as the schema is unknown.
+func (w *HourlyWriter) Close() error {
+	if w.pw == nil {
+		return errors.New("no chunks written")
+	}
+	return w.pw.Close()
+}
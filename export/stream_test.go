@@ -0,0 +1,85 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tpryan/openmeteogo"
+)
+
+const streamResponse = `{
+	"latitude": 52.52, "longitude": 13.42, "elevation": 38, "utc_offset_seconds": 3600,
+	"timezone": "Europe/Berlin", "timezone_abbreviation": "CET",
+	"hourly_units": {"time": "iso8601", "temperature_2m": "°C", "precipitation": "mm"},
+	"hourly": {"time": ["2025-01-01T00:00", "2025-01-01T01:00", "2025-01-01T02:00"],
+		"temperature_2m": [1.5, null, 2], "precipitation": [0, 0.3, 0.1]}
+}`
+
+func TestHourlyWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewHourlyWriter(&buf, "berlin")
+	require.NoError(t, openmeteogo.DecodeHourlyStream(strings.NewReader(streamResponse), 2, w.WriteHourly))
+	require.NoError(t, w.Close())
+	columns, rows, f := readParquet(t, buf.Bytes())
+
+	assert.Equal(t, []string{"location", "latitude", "longitude", "elevation", "time", "temperature_2m", "precipitation"}, columns,
+		"metrics in response order")
+	utc := func(h int) time.Time { return time.Date(2024, 12, 31, 23+h, 0, 0, 0, time.UTC) }
+	assert.Equal(t, [][]any{
+		{"berlin", 52.52, 13.42, 38.0, utc(0), 1.5, 0.0},
+		{"berlin", 52.52, 13.42, 38.0, utc(1), nil, 0.3},
+		{"berlin", 52.52, 13.42, 38.0, utc(2), 2.0, 0.1},
+	}, rows)
+	assert.Len(t, f.RowGroups(), 2, "a row group per chunk")
+	unit, _ := f.Lookup(UnitKeyPrefix + "precipitation")
+	assert.Equal(t, "mm", unit)
+	want := map[string]string{"temperature_2m": "°C", "precipitation": "mm"}
+	assert.Equal(t, []map[string]string{want, want}, columnUnits(f), "units in every column chunk")
+}
+
+func TestHourlyWriter_Errors(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewHourlyWriter(&buf, "")
+	assert.EqualError(t, w.Close(), "no chunks written")
+
+	data := &openmeteogo.WeatherData{Timezone: "GMT"}
+	chunk := &openmeteogo.HourlyChunk{
+		Data:    data,
+		Metrics: openmeteogo.Metrics{openmeteogo.Temperature2m},
+		Units:   []string{"°C"},
+		Time:    []string{"2025-01-01T00:00"},
+		Values:  [][]float64{{1.5}},
+	}
+	require.NoError(t, w.WriteHourly(chunk))
+
+	fahrenheit := *chunk
+	fahrenheit.Units = []string{"°F"}
+	assert.EqualError(t, w.WriteHourly(&fahrenheit), "chunk units [°F] differ from [°C]")
+
+	rain := *chunk
+	rain.Metrics = openmeteogo.Metrics{openmeteogo.Rain}
+	assert.EqualError(t, w.WriteHourly(&rain), "chunk metrics [rain] differ from [temperature_2m]")
+
+	clash := NewHourlyWriter(&buf, "")
+	location := *chunk
+	location.Metrics = openmeteogo.Metrics{ColumnLatitude}
+	assert.EqualError(t, clash.WriteHourly(&location), "metric latitude clashes with a location column")
+}
@@ -0,0 +1,479 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openmeteogo
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+
+	flatbuffers "github.com/google/flatbuffers/go"
+)
+
+// The field slots of the tables in the API's FlatBuffers schema
+// (weather_api.fbs in github.com/open-meteo/sdk). Only the fields the
+// decoder reads are listed.
+const (
+	fbResponseLatitude             flatbuffers.VOffsetT = 4
+	fbResponseLongitude            flatbuffers.VOffsetT = 6
+	fbResponseElevation            flatbuffers.VOffsetT = 8
+	fbResponseGenerationTime       flatbuffers.VOffsetT = 10
+	fbResponseUTCOffset            flatbuffers.VOffsetT = 16
+	fbResponseTimezone             flatbuffers.VOffsetT = 18
+	fbResponseTimezoneAbbreviation flatbuffers.VOffsetT = 20
+	fbResponseCurrent              flatbuffers.VOffsetT = 22
+	fbResponseDaily                flatbuffers.VOffsetT = 24
+	fbResponseHourly               flatbuffers.VOffsetT = 26
+	fbResponseMinutely15           flatbuffers.VOffsetT = 28
+
+	fbSeriesTime      flatbuffers.VOffsetT = 4
+	fbSeriesTimeEnd   flatbuffers.VOffsetT = 6
+	fbSeriesInterval  flatbuffers.VOffsetT = 8
+	fbSeriesVariables flatbuffers.VOffsetT = 10
+
+	fbVariableUnit        flatbuffers.VOffsetT = 6
+	fbVariableValue       flatbuffers.VOffsetT = 8
+	fbVariableValues      flatbuffers.VOffsetT = 10
+	fbVariableValuesInt64 flatbuffers.VOffsetT = 12
+)
+
+// fbUnits maps the Unit enum of the FlatBuffers schema to the labels the
+// JSON format uses.
+var fbUnits = []string{
+	"",          // undefined
+	"°C",        // celsius
+	"cm",        // centimetre
+	"m³/m³",     // cubic_metre_per_cubic_metre
+	"m³/s",      // cubic_metre_per_second
+	"°",         // degree_direction
+	"",          // dimensionless_integer
+	"",          // dimensionless
+	"EAQI",      // european_air_quality_index
+	"°F",        // fahrenheit
+	"ft",        // feet
+	"",          // fraction
+	"GDD °C",    // gdd_celsius
+	"m",         // geopotential_metre
+	"grains/m³", // grains_per_cubic_metre
+	"g/kg",      // gram_per_kilogram
+	"hPa",       // hectopascal
+	"h",         // hours
+	"inch",      // inch
+	"iso8601",   // iso8601
+	"J/kg",      // joule_per_kilogram
+	"K",         // kelvin
+	"kPa",       // kilopascal
+	"kg/m²",     // kilogram_per_square_metre
+	"km/h",      // kilometres_per_hour
+	"kn",        // knots
+	"MJ/m²",     // megajoule_per_square_metre
+	"m/s",       // metre_per_second_not_unit_converted
+	"m",         // metre
+	"m/s",       // metre_per_second
+	"mp/h",      // miles_per_hour
+	"mm",        // millimetre
+	"μg/m³",     // micrograms_per_cubic_metre
+	"mi",        // miles
+	"%",         // percentage
+	"s",         // seconds
+	"unixtime",  // unix_time
+	"USAQI",     // us_air_quality_index
+	"W/m²",      // watt_per_square_metre
+	"wmo code",  // wmo_code
+	"ppm",       // parts_per_million
+}
+
+// fbTable is a table in a FlatBuffers message.
+type fbTable struct {
+	flatbuffers.Table
+}
+
+// has reports whether the field in slot is present.
+func (t fbTable) has(slot flatbuffers.VOffsetT) bool {
+	return t.Offset(slot) != 0
+}
+
+// table returns the table in slot, or false if it is absent.
+func (t fbTable) table(slot flatbuffers.VOffsetT) (fbTable, bool) {
+	o := t.Offset(slot)
+	if o == 0 {
+		return fbTable{}, false
+	}
+	return fbTable{flatbuffers.Table{Bytes: t.Bytes, Pos: t.Indirect(flatbuffers.UOffsetT(o) + t.Pos)}}, true
+}
+
+// string returns the string in slot.
+func (t fbTable) string(slot flatbuffers.VOffsetT) string {
+	o := t.Offset(slot)
+	if o == 0 {
+		return ""
+	}
+	return t.String(flatbuffers.UOffsetT(o) + t.Pos)
+}
+
+// vector returns the position and length of the vector in slot.
+func (t fbTable) vector(slot flatbuffers.VOffsetT) (flatbuffers.UOffsetT, int) {
+	o := flatbuffers.UOffsetT(t.Offset(slot))
+	if o == 0 {
+		return 0, 0
+	}
+	return t.Vector(o), t.VectorLen(o)
+}
+
+// tables returns the vector of tables in slot.
+func (t fbTable) tables(slot flatbuffers.VOffsetT) []fbTable {
+	start, n := t.vector(slot)
+	result := make([]fbTable, n)
+	for i := range result {
+		result[i] = fbTable{flatbuffers.Table{Bytes: t.Bytes, Pos: t.Indirect(start + flatbuffers.UOffsetT(i*4))}}
+	}
+	return result
+}
+
+// float32s returns the float vector in slot.
+func (t fbTable) float32s(slot flatbuffers.VOffsetT) []float32 {
+	start, n := t.vector(slot)
+	data := t.Bytes[start : int(start)+n*4]
+	result := make([]float32, n)
+	for i := range result {
+		result[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[i*4:]))
+	}
+	return result
+}
+
+// int64s returns the int64 vector in slot.
+func (t fbTable) int64s(slot flatbuffers.VOffsetT) []int64 {
+	start, n := t.vector(slot)
+	data := t.Bytes[start : int(start)+n*8]
+	result := make([]int64, n)
+	for i := range result {
+		result[i] = int64(binary.LittleEndian.Uint64(data[i*8:]))
+	}
+	return result
+}
+
+// decodeFlatBuffers reads a FlatBuffers response into WeatherData. The
+// response holds one size-prefixed message per model, in the order of
+// o.Models. Variables are matched to the requested metrics by position, as
+// the format carries no names.
+func decodeFlatBuffers(r io.Reader, o *Options) (wd *WeatherData, err error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []fbTable
+	for len(data) > 0 {
+		if len(data) < 4 {
+			return nil, errors.New("truncated flatbuffers message")
+		}
+		size := binary.LittleEndian.Uint32(data)
+		if uint64(size) > uint64(len(data)-4) || size < 4 {
+			return nil, errors.New("truncated flatbuffers message")
+		}
+		msg := data[4 : 4+size]
+		messages = append(messages, fbTable{flatbuffers.Table{Bytes: msg, Pos: flatbuffers.GetUOffsetT(msg)}})
+		data = data[4+size:]
+	}
+
+	want := max(len(o.Models), 1)
+	if len(messages) != want {
+		return nil, fmt.Errorf("got %d flatbuffers messages, want one per model (%d)", len(messages), want)
+	}
+
+	// The flatbuffers package does not check offsets, so a corrupt message
+	// panics with an index out of range.
+	defer func() {
+		if p := recover(); p != nil {
+			wd, err = nil, fmt.Errorf("malformed flatbuffers message: %v", p)
+		}
+	}()
+
+	wd = &WeatherData{}
+	m := messages[0]
+	wd.Latitude = fbFloat(m.GetFloat32Slot(fbResponseLatitude, 0))
+	wd.Longitude = fbFloat(m.GetFloat32Slot(fbResponseLongitude, 0))
+	wd.Elevation = fbFloat(m.GetFloat32Slot(fbResponseElevation, 0))
+	wd.GenerationtimeMs = fbFloat(m.GetFloat32Slot(fbResponseGenerationTime, 0))
+	wd.UtcOffsetSeconds = int(m.GetInt32Slot(fbResponseUTCOffset, 0))
+	wd.Timezone = m.string(fbResponseTimezone)
+	wd.TimezoneAbbreviation = m.string(fbResponseTimezoneAbbreviation)
+
+	// As in the JSON format, the one UTC offset of the response applies to
+	// every timestamp, even across a daylight saving change.
+	loc := time.FixedZone(wd.TimezoneAbbreviation, wd.UtcOffsetSeconds)
+
+	if len(o.Models) < 2 {
+		md, err := decodeFlatBuffersMessage(m, o, loc)
+		if err != nil {
+			return nil, err
+		}
+		wd.CurrentUnits, wd.Current = md.CurrentUnits, md.Current
+		wd.Minutely15Units, wd.Minutely15 = md.Minutely15Units, md.Minutely15
+		wd.HourlyUnits, wd.Hourly = md.HourlyUnits, md.Hourly
+		wd.DailyUnits, wd.Daily = md.DailyUnits, md.Daily
+		return wd, nil
+	}
+
+	// As with JSON, the top level sections of a multi-model response only
+	// hold the time steps.
+	wd.ByModel = map[string]*ModelData{}
+	for i, model := range o.Models {
+		md, err := decodeFlatBuffersMessage(messages[i], o, loc)
+		if err != nil {
+			return nil, fmt.Errorf("model %s: %w", model, err)
+		}
+		wd.ByModel[model] = md
+	}
+	md := wd.ByModel[o.Models[0]]
+	wd.CurrentUnits = CurrentUnits{Time: md.CurrentUnits.Time, Interval: md.CurrentUnits.Interval}
+	wd.Current = Current{Time: md.Current.Time, Interval: md.Current.Interval}
+	wd.Minutely15Units = Minutely15Units{Time: md.Minutely15Units.Time}
+	wd.Minutely15 = Minutely15{Time: md.Minutely15.Time}
+	wd.HourlyUnits = HourlyUnits{Time: md.HourlyUnits.Time}
+	wd.Hourly = Hourly{Time: md.Hourly.Time}
+	wd.DailyUnits = DailyUnits{Time: md.DailyUnits.Time}
+	wd.Daily = Daily{Time: md.Daily.Time}
+
+	return wd, nil
+}
+
+// decodeFlatBuffersMessage decodes the sections of one message.
+func decodeFlatBuffersMessage(m fbTable, o *Options, loc *time.Location) (*ModelData, error) {
+	var md ModelData
+
+	if t, ok := m.table(fbResponseCurrent); ok {
+		if err := decodeFlatBuffersCurrent(t, o.CurrentMetrics, &md, loc); err != nil {
+			return nil, fmt.Errorf("current: %w", err)
+		}
+	}
+
+	sections := []struct {
+		name    string
+		slot    flatbuffers.VOffsetT
+		metrics Metrics
+		data    any
+		units   any
+		format  string
+	}{
+		{"minutely_15", fbResponseMinutely15, o.Minutely15Metrics, &md.Minutely15, &md.Minutely15Units, HourFormat},
+		{"hourly", fbResponseHourly, o.HourlyMetrics, &md.Hourly, &md.HourlyUnits, HourFormat},
+		{"daily", fbResponseDaily, o.DailyMetrics, &md.Daily, &md.DailyUnits, DateFormat},
+	}
+	for _, s := range sections {
+		t, ok := m.table(s.slot)
+		if !ok {
+			continue
+		}
+		if err := decodeFlatBuffersSeries(t, s.metrics, s.data, s.units, s.format, loc); err != nil {
+			return nil, fmt.Errorf("%s: %w", s.name, err)
+		}
+	}
+
+	return &md, nil
+}
+
+// decodeFlatBuffersCurrent decodes the current conditions, which hold a
+// single value per variable.
+func decodeFlatBuffersCurrent(t fbTable, metrics Metrics, md *ModelData, loc *time.Location) error {
+	variables := t.tables(fbSeriesVariables)
+	if len(variables) != len(metrics) {
+		return fmt.Errorf("got %d variables, requested %d", len(variables), len(metrics))
+	}
+
+	md.Current.Time = time.Unix(t.GetInt64Slot(fbSeriesTime, 0), 0).In(loc).Format(HourFormat)
+	md.Current.Interval = int(t.GetInt32Slot(fbSeriesInterval, 0))
+	md.CurrentUnits.Time = UnitISO8601.String()
+	md.CurrentUnits.Interval = "seconds"
+
+	data := reflect.ValueOf(&md.Current).Elem()
+	units := reflect.ValueOf(&md.CurrentUnits).Elem()
+	for i, v := range variables {
+		name := string(metrics[i])
+		field, ok := fieldByJSONName(data, name)
+		if !ok {
+			return fmt.Errorf("unknown metric: %s", name)
+		}
+		value := v.GetFloat32Slot(fbVariableValue, 0)
+		switch field.Kind() {
+		case reflect.Float64:
+			field.SetFloat(fbFloat(value))
+		case reflect.Int:
+			field.SetInt(fbInt(value))
+		default:
+			return fmt.Errorf("unsupported metric: %s", name)
+		}
+		if label, ok := fieldByJSONName(units, name); ok {
+			label.SetString(fbUnit(v))
+		}
+	}
+	return nil
+}
+
+// decodeFlatBuffersSeries decodes a section of time series into data and
+// units, pointers to the section struct and its *Units struct. Timestamps
+// are formatted with format in loc.
+func decodeFlatBuffersSeries(t fbTable, metrics Metrics, data, units any, format string, loc *time.Location) error {
+	variables := t.tables(fbSeriesVariables)
+	if len(variables) != len(metrics) {
+		return fmt.Errorf("got %d variables, requested %d", len(variables), len(metrics))
+	}
+
+	start := t.GetInt64Slot(fbSeriesTime, 0)
+	end := t.GetInt64Slot(fbSeriesTimeEnd, 0)
+	interval := int64(t.GetInt32Slot(fbSeriesInterval, 0))
+	if interval <= 0 {
+		return fmt.Errorf("invalid interval: %d", interval)
+	}
+	times := make([]string, 0, max((end-start)/interval, 0))
+	first := time.Unix(start, 0).In(loc)
+	for i := range cap(times) {
+		ts := first.Add(time.Duration(int64(i)*interval) * time.Second)
+		times = append(times, ts.Format(format))
+	}
+
+	dv := reflect.ValueOf(data).Elem()
+	uv := reflect.ValueOf(units).Elem()
+	dv.FieldByName("Time").Set(reflect.ValueOf(times))
+	uv.FieldByName("Time").SetString(UnitISO8601.String())
+
+	for i, v := range variables {
+		name := string(metrics[i])
+		section, sectionUnits := dv, uv
+		if variable, level, ok := parsePressureLevelMetric(name); ok {
+			h, ok := data.(*Hourly)
+			if !ok {
+				return fmt.Errorf("unknown metric: %s", name)
+			}
+			hu := units.(*HourlyUnits)
+			if h.PressureLevels == nil {
+				h.PressureLevels = map[int]*PressureLevel{}
+				hu.PressureLevels = map[int]*PressureLevelUnits{}
+			}
+			if h.PressureLevels[level] == nil {
+				h.PressureLevels[level] = &PressureLevel{}
+				hu.PressureLevels[level] = &PressureLevelUnits{}
+			}
+			section = reflect.ValueOf(h.PressureLevels[level]).Elem()
+			sectionUnits = reflect.ValueOf(hu.PressureLevels[level]).Elem()
+			name = string(variable)
+		}
+
+		field, ok := fieldByJSONName(section, name)
+		if !ok || field.Kind() != reflect.Slice {
+			return fmt.Errorf("unknown metric: %s", metrics[i])
+		}
+		if err := setFlatBuffersSeries(field, v, loc); err != nil {
+			return fmt.Errorf("%s: %w", metrics[i], err)
+		}
+		if label, ok := fieldByJSONName(sectionUnits, name); ok {
+			label.SetString(fbUnit(v))
+		}
+	}
+	return nil
+}
+
+// setFlatBuffersSeries stores the values of a variable in field, a slice of
+// float64, int or string. Strings, such as sunrise times, are read from the
+// int64 values as Unix times.
+func setFlatBuffersSeries(field reflect.Value, v fbTable, loc *time.Location) error {
+	switch field.Type().Elem().Kind() {
+	case reflect.Float64:
+		values := v.float32s(fbVariableValues)
+		series := make([]float64, len(values))
+		for i, f := range values {
+			series[i] = fbFloat(f)
+		}
+		field.Set(reflect.ValueOf(series))
+	case reflect.Int:
+		values := v.float32s(fbVariableValues)
+		series := make([]int, len(values))
+		for i, f := range values {
+			series[i] = int(fbInt(f))
+		}
+		field.Set(reflect.ValueOf(series))
+	case reflect.String:
+		if !v.has(fbVariableValuesInt64) {
+			return errors.New("no int64 values")
+		}
+		values := v.int64s(fbVariableValuesInt64)
+		series := make([]string, len(values))
+		for i, t := range values {
+			series[i] = time.Unix(t, 0).In(loc).Format(HourFormat)
+		}
+		field.Set(reflect.ValueOf(series))
+	default:
+		return errors.New("unsupported field type")
+	}
+	return nil
+}
+
+// fieldByJSONName returns the field of the struct v with the given JSON name.
+func fieldByJSONName(v reflect.Value, name string) (reflect.Value, bool) {
+	for i := 0; i < v.NumField(); i++ {
+		if jsonName(v.Type().Field(i)) == name {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// fbUnit returns the label of the unit of a variable.
+func fbUnit(v fbTable) string {
+	if u := int(v.GetUint8Slot(fbVariableUnit, 0)); u < len(fbUnits) {
+		return fbUnits[u]
+	}
+	return ""
+}
+
+// fbScales are the powers of ten tried by fbFloat.
+var fbScales = []float64{1, 10, 100, 1e3, 1e4, 1e5, 1e6}
+
+// fbFloat widens a float32 to the float64 with the shortest decimal form
+// that rounds to it, so that 16.3 decodes as 16.3 as it does from JSON,
+// rather than as 16.299999237060547.
+func fbFloat(f float32) float64 {
+	d := float64(f)
+	if math.IsNaN(d) || math.IsInf(d, 0) {
+		return d
+	}
+	// Most values have a few decimal places, which are found by scaling
+	// without formatting. Dividing the rounded integer by the power of ten
+	// gives the float64 nearest the decimal, as parsing it would.
+	if math.Abs(d) < 1e9 {
+		for _, scale := range fbScales {
+			if v := math.Round(d*scale) / scale; float32(v) == f {
+				return v
+			}
+		}
+	}
+	var buf [32]byte
+	v, _ := strconv.ParseFloat(string(strconv.AppendFloat(buf[:0], float64(f), 'g', -1, 32)), 64)
+	return v
+}
+
+// fbInt rounds a float32 to an integer. Missing values, which are NaN, are
+// zero, as JSON nulls are.
+func fbInt(f float32) int64 {
+	if math.IsNaN(float64(f)) {
+		return 0
+	}
+	return int64(math.Round(float64(f)))
+}
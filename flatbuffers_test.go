@@ -0,0 +1,401 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openmeteogo
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	flatbuffers "github.com/google/flatbuffers/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// encodeFlatBuffers encodes the sections of md as a size-prefixed
+// WeatherApiResponse message, as the API would for the metrics in o.
+func encodeFlatBuffers(tb testing.TB, wd *WeatherData, md *ModelData, o *Options) []byte {
+	tb.Helper()
+	loc := time.FixedZone(wd.TimezoneAbbreviation, wd.UtcOffsetSeconds)
+
+	b := flatbuffers.NewBuilder(1024)
+	var current, minutely15, hourly, daily flatbuffers.UOffsetT
+	if len(o.CurrentMetrics) > 0 {
+		current = encodeFlatBuffersCurrent(tb, b, &md.Current, &md.CurrentUnits, o.CurrentMetrics, loc)
+	}
+	if len(o.Minutely15Metrics) > 0 {
+		minutely15 = encodeFlatBuffersSeries(tb, b, &md.Minutely15, &md.Minutely15Units, o.Minutely15Metrics, HourFormat, 900, loc)
+	}
+	if len(o.HourlyMetrics) > 0 {
+		hourly = encodeFlatBuffersSeries(tb, b, &md.Hourly, &md.HourlyUnits, o.HourlyMetrics, HourFormat, 3600, loc)
+	}
+	if len(o.DailyMetrics) > 0 {
+		daily = encodeFlatBuffersSeries(tb, b, &md.Daily, &md.DailyUnits, o.DailyMetrics, DateFormat, 86400, loc)
+	}
+	timezone := b.CreateString(wd.Timezone)
+	abbreviation := b.CreateString(wd.TimezoneAbbreviation)
+
+	b.StartObject(14)
+	b.PrependFloat32Slot(0, float32(wd.Latitude), 0)
+	b.PrependFloat32Slot(1, float32(wd.Longitude), 0)
+	b.PrependFloat32Slot(2, float32(wd.Elevation), 0)
+	b.PrependFloat32Slot(3, float32(wd.GenerationtimeMs), 0)
+	b.PrependInt32Slot(6, int32(wd.UtcOffsetSeconds), 0)
+	b.PrependUOffsetTSlot(7, timezone, 0)
+	b.PrependUOffsetTSlot(8, abbreviation, 0)
+	b.PrependUOffsetTSlot(9, current, 0)
+	b.PrependUOffsetTSlot(10, daily, 0)
+	b.PrependUOffsetTSlot(11, hourly, 0)
+	b.PrependUOffsetTSlot(12, minutely15, 0)
+	b.FinishSizePrefixed(b.EndObject())
+	return b.FinishedBytes()
+}
+
+func encodeFlatBuffersCurrent(tb testing.TB, b *flatbuffers.Builder, c *Current, cu *CurrentUnits, metrics Metrics, loc *time.Location) flatbuffers.UOffsetT {
+	tb.Helper()
+	ts, err := time.ParseInLocation(HourFormat, c.Time, loc)
+	require.NoError(tb, err)
+
+	data := reflect.ValueOf(c).Elem()
+	units := reflect.ValueOf(cu).Elem()
+	variables := make([]flatbuffers.UOffsetT, len(metrics))
+	for i, m := range metrics {
+		field, ok := fieldByJSONName(data, string(m))
+		require.True(tb, ok, m)
+		label, _ := fieldByJSONName(units, string(m))
+		value := float32(0)
+		if field.Kind() == reflect.Int {
+			value = float32(field.Int())
+		} else {
+			value = float32(field.Float())
+		}
+		b.StartObject(12)
+		b.PrependUint8Slot(1, fbUnitIndex(label.String()), 0)
+		b.PrependFloat32Slot(2, value, 0)
+		variables[i] = b.EndObject()
+	}
+	return encodeFlatBuffersVariables(b, variables, ts.Unix(), ts.Unix()+int64(c.Interval), int32(c.Interval))
+}
+
+func encodeFlatBuffersSeries(tb testing.TB, b *flatbuffers.Builder, data, units any, metrics Metrics, format string, interval int32, loc *time.Location) flatbuffers.UOffsetT {
+	tb.Helper()
+	dv := reflect.ValueOf(data).Elem()
+	uv := reflect.ValueOf(units).Elem()
+	times := dv.FieldByName("Time").Interface().([]string)
+	start, err := time.ParseInLocation(format, times[0], loc)
+	require.NoError(tb, err)
+
+	variables := make([]flatbuffers.UOffsetT, len(metrics))
+	for i, m := range metrics {
+		section, sectionUnits, name := dv, uv, string(m)
+		if variable, level, ok := parsePressureLevelMetric(name); ok {
+			section = reflect.ValueOf(data.(*Hourly).PressureLevels[level]).Elem()
+			sectionUnits = reflect.ValueOf(units.(*HourlyUnits).PressureLevels[level]).Elem()
+			name = string(variable)
+		}
+		field, ok := fieldByJSONName(section, name)
+		require.True(tb, ok, m)
+		label, _ := fieldByJSONName(sectionUnits, name)
+
+		var values flatbuffers.UOffsetT
+		slot := 3
+		if field.Type().Elem().Kind() == reflect.String {
+			slot = 4
+			b.StartVector(8, field.Len(), 8)
+			for j := field.Len() - 1; j >= 0; j-- {
+				ts, err := time.ParseInLocation(HourFormat, field.Index(j).String(), loc)
+				require.NoError(tb, err)
+				b.PrependInt64(ts.Unix())
+			}
+			values = b.EndVector(field.Len())
+		} else {
+			b.StartVector(4, field.Len(), 4)
+			for j := field.Len() - 1; j >= 0; j-- {
+				if e := field.Index(j); e.Kind() == reflect.Int {
+					b.PrependFloat32(float32(e.Int()))
+				} else {
+					b.PrependFloat32(float32(e.Float()))
+				}
+			}
+			values = b.EndVector(field.Len())
+		}
+
+		b.StartObject(12)
+		b.PrependUint8Slot(1, fbUnitIndex(label.String()), 0)
+		b.PrependUOffsetTSlot(slot, values, 0)
+		variables[i] = b.EndObject()
+	}
+	end := start.Unix() + int64(len(times))*int64(interval)
+	return encodeFlatBuffersVariables(b, variables, start.Unix(), end, interval)
+}
+
+func encodeFlatBuffersVariables(b *flatbuffers.Builder, variables []flatbuffers.UOffsetT, start, end int64, interval int32) flatbuffers.UOffsetT {
+	b.StartVector(4, len(variables), 4)
+	for i := len(variables) - 1; i >= 0; i-- {
+		b.PrependUOffsetT(variables[i])
+	}
+	vector := b.EndVector(len(variables))
+
+	b.StartObject(4)
+	b.PrependInt64Slot(0, start, 0)
+	b.PrependInt64Slot(1, end, 0)
+	b.PrependInt32Slot(2, interval, 0)
+	b.PrependUOffsetTSlot(3, vector, 0)
+	return b.EndObject()
+}
+
+// fbUnitIndex returns the schema enum for a unit label.
+func fbUnitIndex(label string) uint8 {
+	return uint8(slices.Index(fbUnits, label))
+}
+
+// fixtureMetrics returns the metrics present in a section struct, in field
+// order. For the current section every field is taken.
+func fixtureMetrics(data any) Metrics {
+	v := reflect.ValueOf(data).Elem()
+	var metrics Metrics
+	for i := 0; i < v.NumField(); i++ {
+		name := jsonName(v.Type().Field(i))
+		if name == "time" || name == "interval" || name == "" || name == "-" {
+			continue
+		}
+		if f := v.Field(i); f.Kind() == reflect.Slice && f.Len() == 0 {
+			continue
+		}
+		metrics = append(metrics, Metric(name))
+	}
+	return metrics
+}
+
+// fixtureOptions returns Options requesting every metric in the fixture.
+func fixtureOptions(wd *WeatherData) *Options {
+	return NewOptionsBuilder().
+		CurrentMetrics(fixtureMetrics(&wd.Current)).
+		HourlyMetrics(fixtureMetrics(&wd.Hourly)).
+		DailyMetrics(fixtureMetrics(&wd.Daily)).
+		FlatBuffers(true).
+		Build()
+}
+
+func TestDecodeFlatBuffers(t *testing.T) {
+	wd := loadWeatherData(t, "test_data/all_params.json")
+	o := fixtureOptions(wd)
+	md := &ModelData{
+		CurrentUnits: wd.CurrentUnits, Current: wd.Current,
+		HourlyUnits: wd.HourlyUnits, Hourly: wd.Hourly,
+		DailyUnits: wd.DailyUnits, Daily: wd.Daily,
+	}
+	msg := encodeFlatBuffers(t, wd, md, o)
+
+	got, err := decodeFlatBuffers(bytes.NewReader(msg), o)
+	require.NoError(t, err)
+	// The generation time is a float32 in the schema, but a full-precision
+	// float64 in the JSON.
+	wd.GenerationtimeMs = fbFloat(float32(wd.GenerationtimeMs))
+	assert.Equal(t, wd, got)
+}
+
+func TestDecodeFlatBuffers_PressureLevels(t *testing.T) {
+	wd := &WeatherData{
+		Timezone: "Europe/Berlin",
+		HourlyUnits: HourlyUnits{
+			Time:          "iso8601",
+			Temperature2m: "°C",
+			PressureLevels: map[int]*PressureLevelUnits{
+				850: {Temperature: "°C", WindDirection: "°"},
+			},
+		},
+		Hourly: Hourly{
+			// The clocks go forward at 02:00 on 30 March, but the API keeps
+			// the offset at the start of the response for every hour.
+			Time:          []string{"2025-03-30T01:00", "2025-03-30T02:00", "2025-03-30T03:00"},
+			Temperature2m: []float64{4.2, float64(float32(math.NaN())), 3.9},
+			PressureLevels: map[int]*PressureLevel{
+				850: {Temperature: []float64{-3.5, -4, -4.1}, WindDirection: []int{270, 280, 285}},
+			},
+		},
+		DailyUnits: DailyUnits{Time: "iso8601", Sunrise: "iso8601"},
+		Daily: Daily{
+			Time:    []string{"2025-03-29", "2025-03-30", "2025-03-31"},
+			Sunrise: []string{"2025-03-29T05:51", "2025-03-30T05:49", "2025-03-31T05:46"},
+		},
+	}
+	wd.UtcOffsetSeconds = 3600
+	wd.TimezoneAbbreviation = "GMT+1"
+	o := NewOptionsBuilder().
+		HourlyMetrics(Metrics{Temperature2m, "temperature_850hPa", "wind_direction_850hPa"}).
+		DailyMetrics(Metrics{Sunrise}).
+		Build()
+	md := &ModelData{HourlyUnits: wd.HourlyUnits, Hourly: wd.Hourly, DailyUnits: wd.DailyUnits, Daily: wd.Daily}
+	msg := encodeFlatBuffers(t, wd, md, o)
+
+	got, err := decodeFlatBuffers(bytes.NewReader(msg), o)
+	require.NoError(t, err)
+	assert.Equal(t, wd.Hourly.Time, got.Hourly.Time, "no hour is skipped at the daylight saving change")
+	assert.Equal(t, 4.2, got.Hourly.Temperature2m[0])
+	assert.True(t, math.IsNaN(got.Hourly.Temperature2m[1]), "missing values are NaN")
+	assert.Equal(t, wd.Hourly.PressureLevels, got.Hourly.PressureLevels)
+	assert.Equal(t, wd.HourlyUnits, got.HourlyUnits)
+	assert.Equal(t, wd.Daily, got.Daily)
+}
+
+func TestDecodeFlatBuffers_Errors(t *testing.T) {
+	wd := loadWeatherData(t, "test_data/all_params.json")
+	o := fixtureOptions(wd)
+	md := &ModelData{HourlyUnits: wd.HourlyUnits, Hourly: wd.Hourly}
+	hourly := NewOptionsBuilder().HourlyMetrics(o.HourlyMetrics).Build()
+	msg := encodeFlatBuffers(t, wd, md, hourly)
+
+	tests := map[string]struct {
+		input   []byte
+		options *Options
+		wantErr string
+	}{
+		"truncated prefix": {
+			input:   msg[:2],
+			options: hourly,
+			wantErr: "truncated flatbuffers message",
+		},
+		"truncated message": {
+			input:   msg[:len(msg)-8],
+			options: hourly,
+			wantErr: "truncated flatbuffers message",
+		},
+		"too few messages": {
+			input:   msg,
+			options: NewOptionsBuilder().Models([]string{"icon_seamless", "gfs_seamless"}).HourlyMetrics(o.HourlyMetrics).Build(),
+			wantErr: "got 1 flatbuffers messages, want one per model (2)",
+		},
+		"variable count": {
+			input:   msg,
+			options: NewOptionsBuilder().HourlyMetrics(o.HourlyMetrics[:3]).Build(),
+			wantErr: "hourly: got 41 variables, requested 3",
+		},
+		"unknown metric": {
+			input:   msg,
+			options: NewOptionsBuilder().HourlyMetrics(append(Metrics{"not_a_metric"}, o.HourlyMetrics[1:]...)).Build(),
+			wantErr: "hourly: unknown metric: not_a_metric",
+		},
+		"corrupt": {
+			input:   append(msg[:4:4], bytes.Repeat([]byte{0xff}, len(msg)-4)...),
+			options: hourly,
+			wantErr: "malformed flatbuffers message",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := decodeFlatBuffers(bytes.NewReader(tc.input), tc.options)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.wantErr)
+		})
+	}
+}
+
+func TestFbFloat(t *testing.T) {
+	tests := []float64{0, 16.3, -3.5, 0.1, 1012.7, 0.00012, 123456.7, 3.3779144, 1.234e-9, 2e12}
+	for _, want := range tests {
+		assert.Equal(t, want, fbFloat(float32(want)), "%v", want)
+	}
+	assert.True(t, math.IsNaN(fbFloat(float32(math.NaN()))))
+}
+
+func TestClient_Get_FlatBuffers(t *testing.T) {
+	wd := &WeatherData{
+		Latitude:  52.52,
+		Longitude: 13.41,
+		Timezone:  "GMT",
+	}
+	icon := &ModelData{
+		HourlyUnits: HourlyUnits{Time: "iso8601", Temperature2m: "°C"},
+		Hourly:      Hourly{Time: []string{"2025-01-01T00:00", "2025-01-01T01:00"}, Temperature2m: []float64{1.5, 1}},
+	}
+	gfs := &ModelData{
+		HourlyUnits: HourlyUnits{Time: "iso8601", Temperature2m: "°C"},
+		Hourly:      Hourly{Time: []string{"2025-01-01T00:00", "2025-01-01T01:00"}, Temperature2m: []float64{2.5, 2}},
+	}
+
+	opts := NewOptionsBuilder().
+		Models([]string{"icon_seamless", "gfs_seamless"}).
+		HourlyMetrics(Metrics{Temperature2m}).
+		FlatBuffers(true).
+		Build()
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Query().Get("format") != "flatbuffers" {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		rw.Write(encodeFlatBuffers(t, wd, icon, opts))
+		rw.Write(encodeFlatBuffers(t, wd, gfs, opts))
+	}))
+	defer server.Close()
+
+	client := NewClient()
+	client.HTTPClient = server.Client()
+	urlParts := strings.Split(server.URL, "://")
+	client.scheme = urlParts[0]
+	client.host = urlParts[1]
+
+	got, err := client.Get(opts)
+	require.NoError(t, err)
+	assert.Equal(t, 52.52, got.Latitude)
+	assert.Equal(t, Hourly{Time: icon.Hourly.Time}, got.Hourly)
+	assert.Equal(t, icon, got.ByModel["icon_seamless"])
+	assert.Equal(t, gfs, got.ByModel["gfs_seamless"])
+
+	weekly := NewOptionsBuilder().WeeklyMetrics(Metrics{Temperature2mMean}).FlatBuffers(true).Build()
+	_, err = client.Get(weekly)
+	assert.EqualError(t, err, "weekly and monthly metrics are not available with FlatBuffers")
+}
+
+func BenchmarkDecode(b *testing.B) {
+	data, err := os.ReadFile("test_data/all_params.json")
+	require.NoError(b, err)
+	var wd WeatherData
+	require.NoError(b, json.Unmarshal(data, &wd))
+	o := fixtureOptions(&wd)
+	md := &ModelData{
+		CurrentUnits: wd.CurrentUnits, Current: wd.Current,
+		HourlyUnits: wd.HourlyUnits, Hourly: wd.Hourly,
+		DailyUnits: wd.DailyUnits, Daily: wd.Daily,
+	}
+	msg := encodeFlatBuffers(b, &wd, md, o)
+
+	b.Run("json", func(b *testing.B) {
+		b.SetBytes(int64(len(data)))
+		for b.Loop() {
+			if _, err := decode(bytes.NewReader(data), o); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("flatbuffers", func(b *testing.B) {
+		b.SetBytes(int64(len(msg)))
+		for b.Loop() {
+			if _, err := decodeFlatBuffers(bytes.NewReader(msg), o); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
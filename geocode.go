@@ -0,0 +1,92 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openmeteogo
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Location is a place returned by the geocoding API.
+type Location struct {
+	ID          int     `json:"id"`
+	Name        string  `json:"name"`
+	Latitude    float64 `json:"latitude"`
+	Longitude   float64 `json:"longitude"`
+	Elevation   float64 `json:"elevation"`
+	FeatureCode string  `json:"feature_code"`
+	CountryCode string  `json:"country_code"`
+	Country     string  `json:"country"`
+	// Admin1 is the first-level administrative area, such as a state.
+	Admin1 string `json:"admin1"`
+	// Timezone is the IANA time zone of the place, e.g. "Europe/Berlin".
+	Timezone   string `json:"timezone"`
+	Population int    `json:"population"`
+}
+
+// Geocode searches the geocoding API for places matching name, which may be
+// a place name or a postal code, and returns up to count results ordered by
+// relevance. A count of zero or less returns the API default of 10.
+func (c *Client) Geocode(name string, count int) ([]Location, error) {
+	req, err := http.NewRequest("GET", c.geocodeURL(name, count), nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", c.UserAgent)
+
+	res, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("sending request: %w", err)
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("server http error: %d", res.StatusCode)
+	}
+
+	var result struct {
+		Results []Location `json:"results"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decoding response: %w", err)
+	}
+
+	return result.Results, nil
+}
+
+func (c *Client) geocodeURL(name string, count int) string {
+	host := c.geocodingHost
+	q := url.Values{}
+	if c.apiKey != "" {
+		host = "customer-" + host
+		q.Set("apikey", c.apiKey)
+	}
+	q.Set("name", name)
+	if count > 0 {
+		q.Set("count", fmt.Sprintf("%v", count))
+	}
+
+	u := url.URL{
+		Scheme:   c.scheme,
+		Host:     host,
+		Path:     "/v1/search",
+		RawQuery: q.Encode(),
+	}
+	return u.String()
+}
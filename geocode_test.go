@@ -0,0 +1,123 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openmeteogo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Geocode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "/v1/search", req.URL.Path)
+		assert.Equal(t, "Berlin", req.URL.Query().Get("name"))
+		assert.Equal(t, "1", req.URL.Query().Get("count"))
+		rw.Write([]byte(`{"results": [{
+			"id": 2950159, "name": "Berlin", "latitude": 52.52437, "longitude": 13.41053,
+			"elevation": 74.0, "feature_code": "PPLC", "country_code": "DE",
+			"timezone": "Europe/Berlin", "population": 3426354, "country": "Germany", "admin1": "Land Berlin"
+		}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient()
+	client.HTTPClient = server.Client()
+	urlParts := strings.Split(server.URL, "://")
+	client.scheme = urlParts[0]
+	client.geocodingHost = urlParts[1]
+
+	got, err := client.Geocode("Berlin", 1)
+	require.NoError(t, err)
+	want := []Location{{
+		ID:          2950159,
+		Name:        "Berlin",
+		Latitude:    52.52437,
+		Longitude:   13.41053,
+		Elevation:   74,
+		FeatureCode: "PPLC",
+		CountryCode: "DE",
+		Country:     "Germany",
+		Admin1:      "Land Berlin",
+		Timezone:    "Europe/Berlin",
+		Population:  3426354,
+	}}
+	assert.Equal(t, want, got)
+}
+
+func TestClient_Geocode_NoResults(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Write([]byte(`{"generationtime_ms": 0.5}`))
+	}))
+	defer server.Close()
+
+	client := NewClient()
+	client.HTTPClient = server.Client()
+	urlParts := strings.Split(server.URL, "://")
+	client.scheme = urlParts[0]
+	client.geocodingHost = urlParts[1]
+
+	got, err := client.Geocode("Nowhere", 0)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func TestGeocodeURL(t *testing.T) {
+	tests := map[string]struct {
+		client *Client
+		count  int
+		want   string
+	}{
+		"basic": {
+			client: NewClient(),
+			want:   "https://geocoding-api.open-meteo.com/v1/search?name=New+York",
+		},
+		"count": {
+			client: NewClient(),
+			count:  5,
+			want:   "https://geocoding-api.open-meteo.com/v1/search?count=5&name=New+York",
+		},
+		"api key": {
+			client: NewClientWithKey("secret"),
+			want:   "https://customer-geocoding-api.open-meteo.com/v1/search?apikey=secret&name=New+York",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.client.geocodeURL("New York", tc.count))
+		})
+	}
+}
+
+func TestClient_Geocode_ServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	client := NewClient()
+	client.HTTPClient = server.Client()
+	urlParts := strings.Split(server.URL, "://")
+	client.scheme = urlParts[0]
+	client.geocodingHost = urlParts[1]
+
+	_, err := client.Geocode("x", 1)
+	assert.ErrorContains(t, err, "400")
+}
@@ -0,0 +1,100 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openmeteogo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestURL_Minutely15(t *testing.T) {
+	tests := map[string]struct {
+		client  *Client
+		options Options
+		want    string
+	}{
+		"minutely_15 metrics": {
+			client:  NewClient(),
+			options: *NewOptionsBuilder().Minutely15Metrics(Metrics{Precipitation, Rain}).Build(),
+			want:    "https://api.open-meteo.com/v1/forecast?latitude=0&longitude=0&minutely_15=precipitation%2Crain",
+		},
+		"minutely_15 steps": {
+			client:  NewClient(),
+			options: *NewOptionsBuilder().PastMinutely15(4).ForecastMinutely15(8).Build(),
+			want:    "https://api.open-meteo.com/v1/forecast?forecast_minutely_15=8&latitude=0&longitude=0&past_minutely_15=4",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := tc.client.url(&tc.options)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestNewMetrics_Minutely15(t *testing.T) {
+	got, err := NewMetrics("minutely_15", Precipitation, LightningPotential)
+	require.NoError(t, err)
+	assert.Equal(t, Metrics{Precipitation, LightningPotential}, got)
+
+	_, err = NewMetrics("minutely_15", SoilMoisture0To1cm)
+	assert.Error(t, err)
+}
+
+func TestClient_Get_Minutely15(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		rw.Write([]byte(`{
+			"latitude": 52.52,
+			"longitude": 13.41,
+			"minutely_15_units": {
+				"time": "iso8601",
+				"precipitation": "mm",
+				"weather_code": "wmo code"
+			},
+			"minutely_15": {
+				"time": ["2025-01-01T00:00", "2025-01-01T00:15"],
+				"precipitation": [0.0, 0.4],
+				"weather_code": [3, 61]
+			}
+		}`))
+	}))
+	defer server.Close()
+
+	client := NewClient()
+	client.HTTPClient = server.Client()
+	urlParts := strings.Split(server.URL, "://")
+	client.scheme = urlParts[0]
+	client.host = urlParts[1]
+
+	opts := NewOptionsBuilder().
+		Latitude(52.52).
+		Longitude(13.41).
+		Minutely15Metrics(Metrics{Precipitation, WeatherCode}).
+		Build()
+
+	wd, err := client.Get(opts)
+	require.NoError(t, err)
+	assert.Equal(t, "mm", wd.Minutely15Units.Precipitation)
+	assert.Equal(t, 2, len(wd.Minutely15.Time))
+	assert.Equal(t, 0.4, wd.Minutely15.Precipitation[1])
+	assert.Equal(t, 61, wd.Minutely15.WeatherCode[1])
+}
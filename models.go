@@ -0,0 +1,257 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openmeteogo
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Model identifies a weather model that can be requested with Options.Models.
+type Model string
+
+const (
+	// BestMatch lets Open-Meteo pick the most suitable models for the location.
+	BestMatch Model = "best_match"
+
+	// Forecast Models
+	EcmwfIfs04              Model = "ecmwf_ifs04"
+	EcmwfIfs025             Model = "ecmwf_ifs025"
+	EcmwfAifs025            Model = "ecmwf_aifs025"
+	CmaGrapesGlobal         Model = "cma_grapes_global"
+	BomAccessGlobal         Model = "bom_access_global"
+	GfsSeamless             Model = "gfs_seamless"
+	GfsGlobal               Model = "gfs_global"
+	GfsHrrr                 Model = "gfs_hrrr"
+	GfsGraphcast025         Model = "gfs_graphcast025"
+	JmaSeamless             Model = "jma_seamless"
+	JmaMsm                  Model = "jma_msm"
+	JmaGsm                  Model = "jma_gsm"
+	IconSeamless            Model = "icon_seamless"
+	IconGlobal              Model = "icon_global"
+	IconEu                  Model = "icon_eu"
+	IconD2                  Model = "icon_d2"
+	GemSeamless             Model = "gem_seamless"
+	GemGlobal               Model = "gem_global"
+	GemRegional             Model = "gem_regional"
+	GemHrdpsContinental     Model = "gem_hrdps_continental"
+	MeteofranceSeamless     Model = "meteofrance_seamless"
+	MeteofranceArpegeWorld  Model = "meteofrance_arpege_world"
+	MeteofranceArpegeEurope Model = "meteofrance_arpege_europe"
+	MeteofranceAromeFrance  Model = "meteofrance_arome_france"
+	MetnoSeamless           Model = "metno_seamless"
+	MetnoNordic             Model = "metno_nordic"
+	KnmiSeamless            Model = "knmi_seamless"
+	DmiSeamless             Model = "dmi_seamless"
+	UkmoSeamless            Model = "ukmo_seamless"
+
+	// Archive Models
+	Era5     Model = "era5"
+	Era5Land Model = "era5_land"
+	Cerra    Model = "cerra"
+	EcmwfIfs Model = "ecmwf_ifs"
+
+	// Seasonal Models
+	EcmwfSeas5 Model = "ecmwf_seas5"
+	EcmwfEc46  Model = "ecmwf_ec46"
+	NcepCfsv2  Model = "cfsv2"
+
+	// Marine Models
+	MeteofranceWave     Model = "meteofrance_wave"
+	EcmwfWam025         Model = "ecmwf_wam025"
+	NcepGfswave025      Model = "ncep_gfswave025"
+	DwdEwam             Model = "dwd_ewam"
+	DwdGwam             Model = "dwd_gwam"
+	Era5Ocean           Model = "era5_ocean"
+	MeteofranceCurrents Model = "meteofrance_currents"
+)
+
+var forecastModels = []Model{
+	BestMatch,
+	EcmwfIfs04,
+	EcmwfIfs025,
+	EcmwfAifs025,
+	CmaGrapesGlobal,
+	BomAccessGlobal,
+	GfsSeamless,
+	GfsGlobal,
+	GfsHrrr,
+	GfsGraphcast025,
+	JmaSeamless,
+	JmaMsm,
+	JmaGsm,
+	IconSeamless,
+	IconGlobal,
+	IconEu,
+	IconD2,
+	GemSeamless,
+	GemGlobal,
+	GemRegional,
+	GemHrdpsContinental,
+	MeteofranceSeamless,
+	MeteofranceArpegeWorld,
+	MeteofranceArpegeEurope,
+	MeteofranceAromeFrance,
+	MetnoSeamless,
+	MetnoNordic,
+	KnmiSeamless,
+	DmiSeamless,
+	UkmoSeamless,
+}
+
+var archiveModels = []Model{
+	BestMatch,
+	Era5,
+	Era5Land,
+	Cerra,
+	EcmwfIfs,
+}
+
+var seasonalModels = []Model{
+	EcmwfSeas5,
+	EcmwfEc46,
+	NcepCfsv2,
+}
+
+var marineModels = []Model{
+	BestMatch,
+	MeteofranceWave,
+	EcmwfWam025,
+	NcepGfswave025,
+	DwdEwam,
+	DwdGwam,
+	Era5Ocean,
+	MeteofranceCurrents,
+}
+
+// NewModels validates models against those known for the given endpoint
+// ("forecast", "archive", "seasonal" or "marine") and returns them in the
+// form expected by Options.Models.
+func NewModels(endpoint string, models ...Model) ([]string, error) {
+	var allowed []Model
+	switch endpoint {
+	case "forecast":
+		allowed = forecastModels
+	case "archive":
+		allowed = archiveModels
+	case "seasonal":
+		allowed = seasonalModels
+	case "marine":
+		allowed = marineModels
+	default:
+		return nil, fmt.Errorf("unknown endpoint: %s", endpoint)
+	}
+
+	result := []string{}
+	for _, model := range models {
+		if !slices.Contains(allowed, model) {
+			return nil, fmt.Errorf("invalid for %s models: %s ", endpoint, model)
+		}
+		result = append(result, string(model))
+	}
+
+	return result, nil
+}
+
+// hasSeasonalModel reports whether any of the requested models is only
+// served by the seasonal API.
+func hasSeasonalModel(models []string) bool {
+	for _, model := range models {
+		if slices.Contains(seasonalModels, Model(model)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ModelData holds the series returned for a single model when several
+// models are requested at once.
+type ModelData struct {
+	CurrentUnits    CurrentUnits    `json:"current_units"`
+	Current         Current         `json:"current"`
+	Minutely15Units Minutely15Units `json:"minutely_15_units"`
+	Minutely15      Minutely15      `json:"minutely_15"`
+	HourlyUnits     HourlyUnits     `json:"hourly_units"`
+	Hourly          Hourly          `json:"hourly"`
+	DailyUnits      DailyUnits      `json:"daily_units"`
+	Daily           Daily           `json:"daily"`
+}
+
+// modelSections are the response sections in which the API suffixes
+// variable names with the model identifier.
+var modelSections = []string{
+	"current_units",
+	"current",
+	"minutely_15_units",
+	"minutely_15",
+	"hourly_units",
+	"hourly",
+	"daily_units",
+	"daily",
+}
+
+// splitModels groups the model-suffixed fields of a multi-model response
+// (e.g. "temperature_2m_gfs_seamless") into one ModelData per model.
+func splitModels(data []byte, models []string) (map[string]*ModelData, error) {
+	var top map[string]json.RawMessage
+	if err := json.Unmarshal(data, &top); err != nil {
+		return nil, err
+	}
+
+	sections := map[string]map[string]json.RawMessage{}
+	for _, name := range modelSections {
+		raw, ok := top[name]
+		if !ok {
+			continue
+		}
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &fields); err != nil {
+			return nil, fmt.Errorf("section %s: %w", name, err)
+		}
+		sections[name] = fields
+	}
+
+	result := map[string]*ModelData{}
+	for _, model := range models {
+		suffix := "_" + model
+		perModel := map[string]map[string]json.RawMessage{}
+		for name, fields := range sections {
+			stripped := map[string]json.RawMessage{}
+			for key, val := range fields {
+				switch {
+				case key == "time" || key == "interval":
+					stripped[key] = val
+				case strings.HasSuffix(key, suffix):
+					stripped[strings.TrimSuffix(key, suffix)] = val
+				}
+			}
+			perModel[name] = stripped
+		}
+
+		buf, err := json.Marshal(perModel)
+		if err != nil {
+			return nil, err
+		}
+		var md ModelData
+		if err := json.Unmarshal(buf, &md); err != nil {
+			return nil, fmt.Errorf("model %s: %w", model, err)
+		}
+		result[model] = &md
+	}
+
+	return result, nil
+}
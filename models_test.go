@@ -0,0 +1,164 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openmeteogo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewModels(t *testing.T) {
+	tests := map[string]struct {
+		endpoint string
+		input    []Model
+		want     []string
+		wantErr  bool
+	}{
+		"forecast": {
+			endpoint: "forecast",
+			input:    []Model{IconSeamless, GfsSeamless},
+			want:     []string{"icon_seamless", "gfs_seamless"},
+		},
+		"seasonal": {
+			endpoint: "seasonal",
+			input:    []Model{EcmwfSeas5},
+			want:     []string{"ecmwf_seas5"},
+		},
+		"wrong endpoint for model": {
+			endpoint: "forecast",
+			input:    []Model{EcmwfSeas5},
+			wantErr:  true,
+		},
+		"typo": {
+			endpoint: "forecast",
+			input:    []Model{"icon_seamles"},
+			wantErr:  true,
+		},
+		"unknown endpoint": {
+			endpoint: "nowhere",
+			input:    []Model{BestMatch},
+			wantErr:  true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := NewModels(tc.endpoint, tc.input...)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestURL_Models(t *testing.T) {
+	tests := map[string]struct {
+		client  *Client
+		options Options
+		want    string
+	}{
+		"forecast models": {
+			client:  NewClient(),
+			options: *NewOptionsBuilder().Models([]string{"icon_seamless", "gfs_seamless"}).Build(),
+			want:    "https://api.open-meteo.com/v1/forecast?latitude=0&longitude=0&models=icon_seamless%2Cgfs_seamless",
+		},
+		"marine models": {
+			client:  NewClient(),
+			options: *NewOptionsBuilder().Marine(true).Models([]string{"ecmwf_wam025"}).Build(),
+			want:    "https://marine-api.open-meteo.com/v1/marine?latitude=0&longitude=0&models=ecmwf_wam025",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := tc.client.url(&tc.options)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestClient_Get_Models(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/v1/forecast" {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+		rw.Header().Set("Content-Type", "application/json")
+		rw.Write([]byte(`{
+			"latitude": 52.52,
+			"longitude": 13.41,
+			"hourly_units": {
+				"time": "iso8601",
+				"temperature_2m_icon_seamless": "°C",
+				"temperature_2m_gfs_seamless": "°C"
+			},
+			"hourly": {
+				"time": ["2025-01-01T00:00", "2025-01-01T01:00"],
+				"temperature_2m_icon_seamless": [1.5, 1.0],
+				"temperature_2m_gfs_seamless": [2.5, 2.0]
+			},
+			"daily_units": {
+				"time": "iso8601",
+				"temperature_2m_max_icon_seamless": "°C",
+				"temperature_2m_max_gfs_seamless": "°C"
+			},
+			"daily": {
+				"time": ["2025-01-01"],
+				"temperature_2m_max_icon_seamless": [4.0],
+				"temperature_2m_max_gfs_seamless": [5.0]
+			}
+		}`))
+	}))
+	defer server.Close()
+
+	client := NewClient()
+	client.HTTPClient = server.Client()
+	urlParts := strings.Split(server.URL, "://")
+	client.scheme = urlParts[0]
+	client.host = urlParts[1]
+
+	models, err := NewModels("forecast", IconSeamless, GfsSeamless)
+	require.NoError(t, err)
+
+	opts := NewOptionsBuilder().
+		Models(models).
+		HourlyMetrics(Metrics{Temperature2m}).
+		DailyMetrics(Metrics{Temperature2mMax}).
+		Build()
+
+	wd, err := client.Get(opts)
+	require.NoError(t, err)
+	require.Len(t, wd.ByModel, 2)
+
+	icon := wd.ByModel["icon_seamless"]
+	require.NotNil(t, icon)
+	assert.Equal(t, []string{"2025-01-01T00:00", "2025-01-01T01:00"}, icon.Hourly.Time)
+	assert.Equal(t, []float64{1.5, 1.0}, icon.Hourly.Temperature2m)
+	assert.Equal(t, "°C", icon.HourlyUnits.Temperature2m)
+	assert.Equal(t, []float64{4.0}, icon.Daily.Temperature2mMax)
+
+	gfs := wd.ByModel["gfs_seamless"]
+	require.NotNil(t, gfs)
+	assert.Equal(t, []float64{2.5, 2.0}, gfs.Hourly.Temperature2m)
+	assert.Equal(t, []float64{5.0}, gfs.Daily.Temperature2mMax)
+}
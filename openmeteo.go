@@ -15,8 +15,11 @@
 package openmeteogo
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"strings"
@@ -28,28 +31,48 @@ const (
 	defaultScheme        = "https"
 	defaultSeasonalHost  = "seasonal-api.open-meteo.com"
 	defaultMarineHost    = "marine-api.open-meteo.com"
+	defaultGeocodingHost = "geocoding-api.open-meteo.com"
 	forecastHistoryLimit = 7 * 24 * time.Hour
 
 	// defaultUserAgent is the default User-Agent string sent with HTTP requests.
 	defaultUserAgent = "OpenMeteoGo-Client"
 )
 
+// The ISO8601 layouts of dates and hours, as used for time-range parameters
+// and in the Time fields of the response sections.
+const (
+	// DateFormat is the layout of dates, e.g. in Daily.Time.
+	DateFormat = "2006-01-02"
+	// HourFormat is the layout of hours, e.g. in Hourly.Time.
+	HourFormat = "2006-01-02T15:04"
+)
+
 // Client is used to interact with the Open-Meteo API.
 type Client struct {
 	// UserAgent is the string sent in the User-Agent header of the request.
 	UserAgent string
 	// HTTPClient allows for a custom http.Client to be used for requests.
-	HTTPClient   *http.Client
-	apiKey       string
-	scheme       string
-	host         string
-	seasonalHost string
-	marineHost   string
+	HTTPClient    *http.Client
+	apiKey        string
+	scheme        string
+	host          string
+	seasonalHost  string
+	marineHost    string
+	geocodingHost string
 }
 
 // Get fetches weather data based on the provided Options.
 func (c *Client) Get(o *Options) (*WeatherData, error) {
-	req, err := http.NewRequest("GET", c.url(o), nil)
+	return c.get(context.Background(), o)
+}
+
+// get fetches weather data, abandoning the request when ctx is done.
+func (c *Client) get(ctx context.Context, o *Options) (*WeatherData, error) {
+	if o.FlatBuffers && (len(o.WeeklyMetrics) > 0 || len(o.MonthlyMetrics) > 0) {
+		return nil, errors.New("weekly and monthly metrics are not available with FlatBuffers")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", c.url(o), nil)
 	if err != nil {
 		return nil, fmt.Errorf("creating request: %w", err)
 	}
@@ -67,36 +90,75 @@ func (c *Client) Get(o *Options) (*WeatherData, error) {
 		return nil, fmt.Errorf("server http error: %d", res.StatusCode)
 	}
 
-	var wd WeatherData
-	if err = json.NewDecoder(res.Body).Decode(&wd); err != nil {
+	decodeFunc := decode
+	if o.FlatBuffers {
+		decodeFunc = decodeFlatBuffers
+	}
+	wd, err := decodeFunc(res.Body, o)
+	if err != nil {
 		return nil, fmt.Errorf("decoding response: %w", err)
 	}
 
+	if u := o.localUnits(); u != (Units{}) {
+		if err := wd.ConvertTo(u); err != nil {
+			return nil, fmt.Errorf("converting units: %w", err)
+		}
+	}
+
+	return wd, nil
+}
+
+// decode reads a JSON response into WeatherData. When several models were
+// requested, the per-model series are also grouped into WeatherData.ByModel.
+func decode(r io.Reader, o *Options) (*WeatherData, error) {
+	var wd WeatherData
+	if len(o.Models) < 2 {
+		if err := json.NewDecoder(r).Decode(&wd); err != nil {
+			return nil, err
+		}
+		return &wd, nil
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &wd); err != nil {
+		return nil, err
+	}
+
+	if wd.ByModel, err = splitModels(data, o.Models); err != nil {
+		return nil, err
+	}
+
 	return &wd, nil
 }
 
 // NewClient creates a new Client with default settings.
 func NewClient() *Client {
 	return &Client{
-		HTTPClient:   http.DefaultClient,
-		UserAgent:    defaultUserAgent,
-		scheme:       defaultScheme,
-		host:         defaultHost,
-		seasonalHost: defaultSeasonalHost,
-		marineHost:   defaultMarineHost,
+		HTTPClient:    http.DefaultClient,
+		UserAgent:     defaultUserAgent,
+		scheme:        defaultScheme,
+		host:          defaultHost,
+		seasonalHost:  defaultSeasonalHost,
+		marineHost:    defaultMarineHost,
+		geocodingHost: defaultGeocodingHost,
 	}
 }
 
 // NewClientWithKey creates a new Client configured with a commercial API key.
 func NewClientWithKey(key string) *Client {
 	return &Client{
-		apiKey:       key,
-		HTTPClient:   http.DefaultClient,
-		UserAgent:    defaultUserAgent,
-		scheme:       defaultScheme,
-		host:         defaultHost,
-		seasonalHost: defaultSeasonalHost,
-		marineHost:   defaultMarineHost,
+		apiKey:        key,
+		HTTPClient:    http.DefaultClient,
+		UserAgent:     defaultUserAgent,
+		scheme:        defaultScheme,
+		host:          defaultHost,
+		seasonalHost:  defaultSeasonalHost,
+		marineHost:    defaultMarineHost,
+		geocodingHost: defaultGeocodingHost,
 	}
 }
 
@@ -105,13 +167,18 @@ func (c *Client) url(o *Options) string {
 	path := "/v1/forecast"
 
 	// Determine if the request is for seasonal data.
-	isSeasonal := o.Seasonal || len(o.Models) > 0 || len(o.WeeklyMetrics) > 0 || len(o.MonthlyMetrics) > 0
+	isSeasonal := o.Seasonal || hasSeasonalModel(o.Models) || len(o.WeeklyMetrics) > 0 || len(o.MonthlyMetrics) > 0
 
 	// Determine if the request is for marine data.
 	isMarine := o.Marine
 
 	// Determine if the request is for data older than the forecast API's history limit.
-	isHistorical := !o.Start.IsZero() && time.Since(o.Start) > forecastHistoryLimit
+	// An hour range without dates counts from its first hour.
+	start := o.Start
+	if start.IsZero() {
+		start = o.StartHour
+	}
+	isHistorical := !start.IsZero() && time.Since(start) > forecastHistoryLimit
 
 	if isMarine {
 		host = c.marineHost
@@ -139,10 +206,11 @@ func (c *Client) url(o *Options) string {
 	// Use common options encoding
 	c.encodeCommonOptions(q, o)
 
+	if len(o.Models) > 0 {
+		q.Set("models", strings.Join(o.Models, ","))
+	}
+
 	if isSeasonal {
-		if len(o.Models) > 0 {
-			q.Set("models", strings.Join(o.Models, ","))
-		}
 		if o.WeeklyMetrics != nil {
 			if val := o.WeeklyMetrics.encode(); val != "" {
 				q.Set("weekly", val)
@@ -161,6 +229,12 @@ func (c *Client) url(o *Options) string {
 		}
 	}
 
+	if o.Minutely15Metrics != nil {
+		if val := o.Minutely15Metrics.encode(); val != "" {
+			q.Set("minutely_15", val)
+		}
+	}
+
 	if o.DailyMetrics != nil {
 		if val := o.DailyMetrics.encode(); val != "" {
 			q.Set("daily", val)
@@ -171,12 +245,32 @@ func (c *Client) url(o *Options) string {
 		q.Set("forecast_days", fmt.Sprintf("%v", o.ForcastDays))
 	}
 
+	if o.PastHours > 0 {
+		q.Set("past_hours", fmt.Sprintf("%v", o.PastHours))
+	}
+
+	if o.ForecastHours > 0 {
+		q.Set("forecast_hours", fmt.Sprintf("%v", o.ForecastHours))
+	}
+
+	if o.PastMinutely15 > 0 {
+		q.Set("past_minutely_15", fmt.Sprintf("%v", o.PastMinutely15))
+	}
+
+	if o.ForecastMinutely15 > 0 {
+		q.Set("forecast_minutely_15", fmt.Sprintf("%v", o.ForecastMinutely15))
+	}
+
 	if o.CurrentMetrics != nil {
 		if val := o.CurrentMetrics.encode(); val != "" {
 			q.Set("current", val)
 		}
 	}
 
+	if o.FlatBuffers {
+		q.Set("format", "flatbuffers")
+	}
+
 	u.RawQuery = q.Encode()
 
 	return u.String()
@@ -195,7 +289,8 @@ func (c *Client) encodeCommonOptions(q url.Values, o *Options) {
 		q.Set("temperature_unit", string(o.TemperatureUnit))
 	}
 
-	if o.WindspeedUnit != "" {
+	// Beaufort is not supported by the API and is converted locally.
+	if o.WindspeedUnit != "" && o.WindspeedUnit != BFT {
 		q.Set("windspeed_unit", string(o.WindspeedUnit))
 	}
 
@@ -203,42 +298,91 @@ func (c *Client) encodeCommonOptions(q url.Values, o *Options) {
 		q.Set("precipitation_unit", string(o.PrecipitationUnit))
 	}
 
+	if o.LengthUnit != "" {
+		q.Set("length_unit", string(o.LengthUnit))
+	}
+
 	if o.Timezone.String() != "" {
 		q.Set("timezone", o.Timezone.String())
 	}
 
 	if !o.Start.IsZero() {
-		q.Set("start_date", o.Start.Format("2006-01-02"))
+		q.Set("start_date", o.Start.Format(DateFormat))
 	}
 
 	if !o.End.IsZero() {
-		q.Set("end_date", o.End.Format("2006-01-02"))
+		q.Set("end_date", o.End.Format(DateFormat))
+	}
+
+	// Hours are sent as wall clock times in the request timezone, which the
+	// API defaults to UTC.
+	loc := time.UTC
+	if o.Timezone.String() != "" {
+		loc = &o.Timezone
+	}
+
+	if !o.StartHour.IsZero() {
+		q.Set("start_hour", formatStep(o.StartHour, loc, 60))
+	}
+
+	if !o.EndHour.IsZero() {
+		q.Set("end_hour", formatStep(o.EndHour, loc, 60))
+	}
+
+	if !o.StartMinutely15.IsZero() {
+		q.Set("start_minutely_15", formatStep(o.StartMinutely15, loc, 15))
+	}
+
+	if !o.EndMinutely15.IsZero() {
+		q.Set("end_minutely_15", formatStep(o.EndMinutely15, loc, 15))
 	}
 
 	if o.PastDays > 0 {
 		q.Set("past_days", fmt.Sprintf("%v", o.PastDays))
 	}
+
+	if o.Tilt != 0 {
+		q.Set("tilt", fmt.Sprintf("%v", o.Tilt))
+	}
+
+	if o.Azimuth != 0 {
+		q.Set("azimuth", fmt.Sprintf("%v", o.Azimuth))
+	}
+}
+
+// formatStep formats t as a wall clock time in loc, truncated to a step of
+// the given number of minutes.
+func formatStep(t time.Time, loc *time.Location, minutes int) string {
+	t = t.In(loc)
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute()-t.Minute()%minutes, 0, 0, loc)
+	return t.Format(HourFormat)
 }
 
 // WeatherData is the main struct that holds all the data returned from the API.
 type WeatherData struct {
-	Latitude             float64      `json:"latitude"`
-	Longitude            float64      `json:"longitude"`
-	GenerationtimeMs     float64      `json:"generationtime_ms"`
-	UtcOffsetSeconds     int          `json:"utc_offset_seconds"`
-	Timezone             string       `json:"timezone"`
-	TimezoneAbbreviation string       `json:"timezone_abbreviation"`
-	Elevation            float64      `json:"elevation"`
-	CurrentUnits         CurrentUnits `json:"current_units"`
-	Current              Current      `json:"current"`
-	HourlyUnits          HourlyUnits  `json:"hourly_units"`
-	Hourly               Hourly       `json:"hourly"`
-	DailyUnits           DailyUnits   `json:"daily_units"`
-	Daily                Daily        `json:"daily"`
-	WeeklyUnits          WeeklyUnits  `json:"weekly_units"`
-	Weekly               Weekly       `json:"weekly"`
-	MonthlyUnits         MonthlyUnits `json:"monthly_units"`
-	Monthly              Monthly      `json:"monthly"`
+	Latitude             float64         `json:"latitude"`
+	Longitude            float64         `json:"longitude"`
+	GenerationtimeMs     float64         `json:"generationtime_ms"`
+	UtcOffsetSeconds     int             `json:"utc_offset_seconds"`
+	Timezone             string          `json:"timezone"`
+	TimezoneAbbreviation string          `json:"timezone_abbreviation"`
+	Elevation            float64         `json:"elevation"`
+	CurrentUnits         CurrentUnits    `json:"current_units"`
+	Current              Current         `json:"current"`
+	Minutely15Units      Minutely15Units `json:"minutely_15_units"`
+	Minutely15           Minutely15      `json:"minutely_15"`
+	HourlyUnits          HourlyUnits     `json:"hourly_units"`
+	Hourly               Hourly          `json:"hourly"`
+	DailyUnits           DailyUnits      `json:"daily_units"`
+	Daily                Daily           `json:"daily"`
+	WeeklyUnits          WeeklyUnits     `json:"weekly_units"`
+	Weekly               Weekly          `json:"weekly"`
+	MonthlyUnits         MonthlyUnits    `json:"monthly_units"`
+	Monthly              Monthly         `json:"monthly"`
+
+	// ByModel holds the series for each requested model, keyed by model
+	// identifier. It is only populated when more than one model is requested.
+	ByModel map[string]*ModelData `json:"-"`
 }
 
 // CurrentUnits describes the units for the current weather data.
@@ -285,138 +429,244 @@ type Current struct {
 
 // HourlyUnits describes the units for the hourly forecast data.
 type HourlyUnits struct {
-	Time                        string `json:"time"`
-	Temperature2m               string `json:"temperature_2m"`
-	RelativeHumidity2m          string `json:"relative_humidity_2m"`
-	DewPoint2m                  string `json:"dew_point_2m"`
-	ApparentTemperature         string `json:"apparent_temperature"`
-	PrecipitationProbability    string `json:"precipitation_probability"`
-	Precipitation               string `json:"precipitation"`
-	Rain                        string `json:"rain"`
-	Showers                     string `json:"showers"`
-	Snowfall                    string `json:"snowfall"`
-	SnowDepth                   string `json:"snow_depth"`
-	WeatherCode                 string `json:"weather_code"`
-	PressureMsl                 string `json:"pressure_msl"`
-	SurfacePressure             string `json:"surface_pressure"`
-	CloudCover                  string `json:"cloud_cover"`
-	CloudCoverLow               string `json:"cloud_cover_low"`
-	CloudCoverMid               string `json:"cloud_cover_mid"`
-	CloudCoverHigh              string `json:"cloud_cover_high"`
-	Evapotranspiration          string `json:"evapotranspiration"`
-	Visibility                  string `json:"visibility"`
-	Et0FaoEvapotranspiration    string `json:"et0_fao_evapotranspiration"`
-	VapourPressureDeficit       string `json:"vapour_pressure_deficit"`
-	WindSpeed10m                string `json:"wind_speed_10m"`
-	WindSpeed80m                string `json:"wind_speed_80m"`
-	WindSpeed120m               string `json:"wind_speed_120m"`
-	WindSpeed180m               string `json:"wind_speed_180m"`
-	WindDirection10m            string `json:"wind_direction_10m"`
-	WindDirection80m            string `json:"wind_direction_80m"`
-	WindDirection120m           string `json:"wind_direction_120m"`
-	WindDirection180m           string `json:"wind_direction_180m"`
-	WindGusts10m                string `json:"wind_gusts_10m"`
-	Temperature80m              string `json:"temperature_80m"`
-	Temperature120m             string `json:"temperature_120m"`
-	Temperature180m             string `json:"temperature_180m"`
-	SoilTemperature0cm          string `json:"soil_temperature_0cm"`
-	SoilTemperature6cm          string `json:"soil_temperature_6cm"`
-	SoilTemperature18cm         string `json:"soil_temperature_18cm"`
-	SoilTemperature54cm         string `json:"soil_temperature_54cm"`
-	SoilMoisture0To1cm          string `json:"soil_moisture_0_to_1cm"`
-	SoilMoisture1To3cm          string `json:"soil_moisture_1_to_3cm"`
-	SoilMoisture9To27cm         string `json:"soil_moisture_9_to_27cm"`
-	SoilMoisture3To9cm          string `json:"soil_moisture_3_to_9cm"`
-	WaveHeight                  string `json:"wave_height"`
-	WaveDirection               string `json:"wave_direction"`
-	WavePeriod                  string `json:"wave_period"`
-	WavePeakPeriod              string `json:"wave_peak_period"`
-	WindWaveHeight              string `json:"wind_wave_height"`
-	WindWaveDirection           string `json:"wind_wave_direction"`
-	WindWavePeriod              string `json:"wind_wave_period"`
-	WindWavePeakPeriod          string `json:"wind_wave_peak_period"`
-	SwellWaveHeight             string `json:"swell_wave_height"`
-	SwellWaveDirection          string `json:"swell_wave_direction"`
-	SwellWavePeriod             string `json:"swell_wave_period"`
-	SwellWavePeakPeriod         string `json:"swell_wave_peak_period"`
-	SecondarySwellWaveHeight    string `json:"secondary_swell_wave_height"`
-	SecondarySwellWaveDirection string `json:"secondary_swell_wave_direction"`
-	SecondarySwellWavePeriod    string `json:"secondary_swell_wave_period"`
-	TertiarySwellWaveHeight     string `json:"tertiary_swell_wave_height"`
-	TertiarySwellWaveDirection  string `json:"tertiary_swell_wave_direction"`
-	TertiarySwellWavePeriod     string `json:"tertiary_swell_wave_period"`
-	SeaLevelHeight              string `json:"sea_level_height"`
-	SeaSurfaceTemperature       string `json:"sea_surface_temperature"`
-	OceanCurrentVelocity        string `json:"ocean_current_velocity"`
-	OceanCurrentDirection       string `json:"ocean_current_direction"`
+	Time                          string `json:"time"`
+	Temperature2m                 string `json:"temperature_2m"`
+	RelativeHumidity2m            string `json:"relative_humidity_2m"`
+	DewPoint2m                    string `json:"dew_point_2m"`
+	ApparentTemperature           string `json:"apparent_temperature"`
+	PrecipitationProbability      string `json:"precipitation_probability"`
+	Precipitation                 string `json:"precipitation"`
+	Rain                          string `json:"rain"`
+	Showers                       string `json:"showers"`
+	Snowfall                      string `json:"snowfall"`
+	SnowDepth                     string `json:"snow_depth"`
+	WeatherCode                   string `json:"weather_code"`
+	PressureMsl                   string `json:"pressure_msl"`
+	SurfacePressure               string `json:"surface_pressure"`
+	CloudCover                    string `json:"cloud_cover"`
+	CloudCoverLow                 string `json:"cloud_cover_low"`
+	CloudCoverMid                 string `json:"cloud_cover_mid"`
+	CloudCoverHigh                string `json:"cloud_cover_high"`
+	Evapotranspiration            string `json:"evapotranspiration"`
+	Visibility                    string `json:"visibility"`
+	Et0FaoEvapotranspiration      string `json:"et0_fao_evapotranspiration"`
+	VapourPressureDeficit         string `json:"vapour_pressure_deficit"`
+	WindSpeed10m                  string `json:"wind_speed_10m"`
+	WindSpeed80m                  string `json:"wind_speed_80m"`
+	WindSpeed120m                 string `json:"wind_speed_120m"`
+	WindSpeed180m                 string `json:"wind_speed_180m"`
+	WindDirection10m              string `json:"wind_direction_10m"`
+	WindDirection80m              string `json:"wind_direction_80m"`
+	WindDirection120m             string `json:"wind_direction_120m"`
+	WindDirection180m             string `json:"wind_direction_180m"`
+	WindGusts10m                  string `json:"wind_gusts_10m"`
+	Temperature80m                string `json:"temperature_80m"`
+	Temperature120m               string `json:"temperature_120m"`
+	Temperature180m               string `json:"temperature_180m"`
+	SoilTemperature0cm            string `json:"soil_temperature_0cm"`
+	SoilTemperature6cm            string `json:"soil_temperature_6cm"`
+	SoilTemperature18cm           string `json:"soil_temperature_18cm"`
+	SoilTemperature54cm           string `json:"soil_temperature_54cm"`
+	SoilMoisture0To1cm            string `json:"soil_moisture_0_to_1cm"`
+	SoilMoisture1To3cm            string `json:"soil_moisture_1_to_3cm"`
+	SoilMoisture9To27cm           string `json:"soil_moisture_9_to_27cm"`
+	SoilMoisture3To9cm            string `json:"soil_moisture_3_to_9cm"`
+	WaveHeight                    string `json:"wave_height"`
+	WaveDirection                 string `json:"wave_direction"`
+	WavePeriod                    string `json:"wave_period"`
+	WavePeakPeriod                string `json:"wave_peak_period"`
+	WindWaveHeight                string `json:"wind_wave_height"`
+	WindWaveDirection             string `json:"wind_wave_direction"`
+	WindWavePeriod                string `json:"wind_wave_period"`
+	WindWavePeakPeriod            string `json:"wind_wave_peak_period"`
+	SwellWaveHeight               string `json:"swell_wave_height"`
+	SwellWaveDirection            string `json:"swell_wave_direction"`
+	SwellWavePeriod               string `json:"swell_wave_period"`
+	SwellWavePeakPeriod           string `json:"swell_wave_peak_period"`
+	SecondarySwellWaveHeight      string `json:"secondary_swell_wave_height"`
+	SecondarySwellWaveDirection   string `json:"secondary_swell_wave_direction"`
+	SecondarySwellWavePeriod      string `json:"secondary_swell_wave_period"`
+	TertiarySwellWaveHeight       string `json:"tertiary_swell_wave_height"`
+	TertiarySwellWaveDirection    string `json:"tertiary_swell_wave_direction"`
+	TertiarySwellWavePeriod       string `json:"tertiary_swell_wave_period"`
+	SeaLevelHeight                string `json:"sea_level_height"`
+	SeaSurfaceTemperature         string `json:"sea_surface_temperature"`
+	OceanCurrentVelocity          string `json:"ocean_current_velocity"`
+	OceanCurrentDirection         string `json:"ocean_current_direction"`
+	ShortwaveRadiation            string `json:"shortwave_radiation"`
+	DirectRadiation               string `json:"direct_radiation"`
+	DiffuseRadiation              string `json:"diffuse_radiation"`
+	DirectNormalIrradiance        string `json:"direct_normal_irradiance"`
+	GlobalTiltedIrradiance        string `json:"global_tilted_irradiance"`
+	TerrestrialRadiation          string `json:"terrestrial_radiation"`
+	ShortwaveRadiationInstant     string `json:"shortwave_radiation_instant"`
+	DirectRadiationInstant        string `json:"direct_radiation_instant"`
+	DiffuseRadiationInstant       string `json:"diffuse_radiation_instant"`
+	DirectNormalIrradianceInstant string `json:"direct_normal_irradiance_instant"`
+	GlobalTiltedIrradianceInstant string `json:"global_tilted_irradiance_instant"`
+	TerrestrialRadiationInstant   string `json:"terrestrial_radiation_instant"`
+
+	// PressureLevels holds the units of pressure level variables, keyed by
+	// level in hPa.
+	PressureLevels map[int]*PressureLevelUnits `json:"-"`
 }
 
 // Hourly holds slices for each hourly forecast metric.
 type Hourly struct {
-	Time                        []string  `json:"time"`
-	Temperature2m               []float64 `json:"temperature_2m"`
-	RelativeHumidity2m          []int     `json:"relative_humidity_2m"`
-	DewPoint2m                  []float64 `json:"dew_point_2m"`
-	ApparentTemperature         []float64 `json:"apparent_temperature"`
-	PrecipitationProbability    []int     `json:"precipitation_probability"`
-	Precipitation               []float64 `json:"precipitation"`
-	Rain                        []float64 `json:"rain"`
-	Showers                     []float64 `json:"showers"`
-	Snowfall                    []float64 `json:"snowfall"`
-	SnowDepth                   []float64 `json:"snow_depth"`
-	WeatherCode                 []int     `json:"weather_code"`
-	PressureMsl                 []float64 `json:"pressure_msl"`
-	SurfacePressure             []float64 `json:"surface_pressure"`
-	CloudCover                  []int     `json:"cloud_cover"`
-	CloudCoverLow               []int     `json:"cloud_cover_low"`
-	CloudCoverMid               []int     `json:"cloud_cover_mid"`
-	CloudCoverHigh              []int     `json:"cloud_cover_high"`
-	Evapotranspiration          []float64 `json:"evapotranspiration"`
-	Visibility                  []float64 `json:"visibility"`
-	Et0FaoEvapotranspiration    []float64 `json:"et0_fao_evapotranspiration"`
-	VapourPressureDeficit       []float64 `json:"vapour_pressure_deficit"`
-	WindSpeed10m                []float64 `json:"wind_speed_10m"`
-	WindSpeed80m                []float64 `json:"wind_speed_80m"`
-	WindSpeed120m               []float64 `json:"wind_speed_120m"`
-	WindSpeed180m               []float64 `json:"wind_speed_180m"`
-	WindDirection10m            []int     `json:"wind_direction_10m"`
-	WindDirection80m            []int     `json:"wind_direction_80m"`
-	WindDirection120m           []int     `json:"wind_direction_120m"`
-	WindDirection180m           []int     `json:"wind_direction_180m"`
-	WindGusts10m                []float64 `json:"wind_gusts_10m"`
-	Temperature80m              []float64 `json:"temperature_80m"`
-	Temperature120m             []float64 `json:"temperature_120m"`
-	Temperature180m             []float64 `json:"temperature_180m"`
-	SoilTemperature0cm          []float64 `json:"soil_temperature_0cm"`
-	SoilTemperature6cm          []float64 `json:"soil_temperature_6cm"`
-	SoilTemperature18cm         []float64 `json:"soil_temperature_18cm"`
-	SoilTemperature54cm         []float64 `json:"soil_temperature_54cm"`
-	SoilMoisture0To1cm          []float64 `json:"soil_moisture_0_to_1cm"`
-	SoilMoisture1To3cm          []float64 `json:"soil_moisture_1_to_3cm"`
-	SoilMoisture9To27cm         []float64 `json:"soil_moisture_9_to_27cm"`
-	SoilMoisture3To9cm          []float64 `json:"soil_moisture_3_to_9cm"`
-	WaveHeight                  []float64 `json:"wave_height"`
-	WaveDirection               []float64 `json:"wave_direction"`
-	WavePeriod                  []float64 `json:"wave_period"`
-	WavePeakPeriod              []float64 `json:"wave_peak_period"`
-	WindWaveHeight              []float64 `json:"wind_wave_height"`
-	WindWaveDirection           []float64 `json:"wind_wave_direction"`
-	WindWavePeriod              []float64 `json:"wind_wave_period"`
-	WindWavePeakPeriod          []float64 `json:"wind_wave_peak_period"`
-	SwellWaveHeight             []float64 `json:"swell_wave_height"`
-	SwellWaveDirection          []float64 `json:"swell_wave_direction"`
-	SwellWavePeriod             []float64 `json:"swell_wave_period"`
-	SwellWavePeakPeriod         []float64 `json:"swell_wave_peak_period"`
-	SecondarySwellWaveHeight    []float64 `json:"secondary_swell_wave_height"`
-	SecondarySwellWaveDirection []float64 `json:"secondary_swell_wave_direction"`
-	SecondarySwellWavePeriod    []float64 `json:"secondary_swell_wave_period"`
-	TertiarySwellWaveHeight     []float64 `json:"tertiary_swell_wave_height"`
-	TertiarySwellWaveDirection  []float64 `json:"tertiary_swell_wave_direction"`
-	TertiarySwellWavePeriod     []float64 `json:"tertiary_swell_wave_period"`
-	SeaLevelHeight              []float64 `json:"sea_level_height"`
-	SeaSurfaceTemperature       []float64 `json:"sea_surface_temperature"`
-	OceanCurrentVelocity        []float64 `json:"ocean_current_velocity"`
-	OceanCurrentDirection       []float64 `json:"ocean_current_direction"`
+	Time                          []string  `json:"time"`
+	Temperature2m                 []float64 `json:"temperature_2m"`
+	RelativeHumidity2m            []int     `json:"relative_humidity_2m"`
+	DewPoint2m                    []float64 `json:"dew_point_2m"`
+	ApparentTemperature           []float64 `json:"apparent_temperature"`
+	PrecipitationProbability      []int     `json:"precipitation_probability"`
+	Precipitation                 []float64 `json:"precipitation"`
+	Rain                          []float64 `json:"rain"`
+	Showers                       []float64 `json:"showers"`
+	Snowfall                      []float64 `json:"snowfall"`
+	SnowDepth                     []float64 `json:"snow_depth"`
+	WeatherCode                   []int     `json:"weather_code"`
+	PressureMsl                   []float64 `json:"pressure_msl"`
+	SurfacePressure               []float64 `json:"surface_pressure"`
+	CloudCover                    []int     `json:"cloud_cover"`
+	CloudCoverLow                 []int     `json:"cloud_cover_low"`
+	CloudCoverMid                 []int     `json:"cloud_cover_mid"`
+	CloudCoverHigh                []int     `json:"cloud_cover_high"`
+	Evapotranspiration            []float64 `json:"evapotranspiration"`
+	Visibility                    []float64 `json:"visibility"`
+	Et0FaoEvapotranspiration      []float64 `json:"et0_fao_evapotranspiration"`
+	VapourPressureDeficit         []float64 `json:"vapour_pressure_deficit"`
+	WindSpeed10m                  []float64 `json:"wind_speed_10m"`
+	WindSpeed80m                  []float64 `json:"wind_speed_80m"`
+	WindSpeed120m                 []float64 `json:"wind_speed_120m"`
+	WindSpeed180m                 []float64 `json:"wind_speed_180m"`
+	WindDirection10m              []int     `json:"wind_direction_10m"`
+	WindDirection80m              []int     `json:"wind_direction_80m"`
+	WindDirection120m             []int     `json:"wind_direction_120m"`
+	WindDirection180m             []int     `json:"wind_direction_180m"`
+	WindGusts10m                  []float64 `json:"wind_gusts_10m"`
+	Temperature80m                []float64 `json:"temperature_80m"`
+	Temperature120m               []float64 `json:"temperature_120m"`
+	Temperature180m               []float64 `json:"temperature_180m"`
+	SoilTemperature0cm            []float64 `json:"soil_temperature_0cm"`
+	SoilTemperature6cm            []float64 `json:"soil_temperature_6cm"`
+	SoilTemperature18cm           []float64 `json:"soil_temperature_18cm"`
+	SoilTemperature54cm           []float64 `json:"soil_temperature_54cm"`
+	SoilMoisture0To1cm            []float64 `json:"soil_moisture_0_to_1cm"`
+	SoilMoisture1To3cm            []float64 `json:"soil_moisture_1_to_3cm"`
+	SoilMoisture9To27cm           []float64 `json:"soil_moisture_9_to_27cm"`
+	SoilMoisture3To9cm            []float64 `json:"soil_moisture_3_to_9cm"`
+	WaveHeight                    []float64 `json:"wave_height"`
+	WaveDirection                 []float64 `json:"wave_direction"`
+	WavePeriod                    []float64 `json:"wave_period"`
+	WavePeakPeriod                []float64 `json:"wave_peak_period"`
+	WindWaveHeight                []float64 `json:"wind_wave_height"`
+	WindWaveDirection             []float64 `json:"wind_wave_direction"`
+	WindWavePeriod                []float64 `json:"wind_wave_period"`
+	WindWavePeakPeriod            []float64 `json:"wind_wave_peak_period"`
+	SwellWaveHeight               []float64 `json:"swell_wave_height"`
+	SwellWaveDirection            []float64 `json:"swell_wave_direction"`
+	SwellWavePeriod               []float64 `json:"swell_wave_period"`
+	SwellWavePeakPeriod           []float64 `json:"swell_wave_peak_period"`
+	SecondarySwellWaveHeight      []float64 `json:"secondary_swell_wave_height"`
+	SecondarySwellWaveDirection   []float64 `json:"secondary_swell_wave_direction"`
+	SecondarySwellWavePeriod      []float64 `json:"secondary_swell_wave_period"`
+	TertiarySwellWaveHeight       []float64 `json:"tertiary_swell_wave_height"`
+	TertiarySwellWaveDirection    []float64 `json:"tertiary_swell_wave_direction"`
+	TertiarySwellWavePeriod       []float64 `json:"tertiary_swell_wave_period"`
+	SeaLevelHeight                []float64 `json:"sea_level_height"`
+	SeaSurfaceTemperature         []float64 `json:"sea_surface_temperature"`
+	OceanCurrentVelocity          []float64 `json:"ocean_current_velocity"`
+	OceanCurrentDirection         []float64 `json:"ocean_current_direction"`
+	ShortwaveRadiation            []float64 `json:"shortwave_radiation"`
+	DirectRadiation               []float64 `json:"direct_radiation"`
+	DiffuseRadiation              []float64 `json:"diffuse_radiation"`
+	DirectNormalIrradiance        []float64 `json:"direct_normal_irradiance"`
+	GlobalTiltedIrradiance        []float64 `json:"global_tilted_irradiance"`
+	TerrestrialRadiation          []float64 `json:"terrestrial_radiation"`
+	ShortwaveRadiationInstant     []float64 `json:"shortwave_radiation_instant"`
+	DirectRadiationInstant        []float64 `json:"direct_radiation_instant"`
+	DiffuseRadiationInstant       []float64 `json:"diffuse_radiation_instant"`
+	DirectNormalIrradianceInstant []float64 `json:"direct_normal_irradiance_instant"`
+	GlobalTiltedIrradianceInstant []float64 `json:"global_tilted_irradiance_instant"`
+	TerrestrialRadiationInstant   []float64 `json:"terrestrial_radiation_instant"`
+
+	// PressureLevels holds pressure level variables such as
+	// "temperature_850hPa", keyed by level in hPa.
+	PressureLevels map[int]*PressureLevel `json:"-"`
+}
+
+// Minutely15Units describes the units for the 15-minutely forecast data.
+type Minutely15Units struct {
+	Time                          string `json:"time"`
+	Temperature2m                 string `json:"temperature_2m"`
+	RelativeHumidity2m            string `json:"relative_humidity_2m"`
+	DewPoint2m                    string `json:"dew_point_2m"`
+	ApparentTemperature           string `json:"apparent_temperature"`
+	Precipitation                 string `json:"precipitation"`
+	Rain                          string `json:"rain"`
+	Showers                       string `json:"showers"`
+	Snowfall                      string `json:"snowfall"`
+	SnowfallHeight                string `json:"snowfall_height"`
+	FreezingLevelHeight           string `json:"freezing_level_height"`
+	SunshineDuration              string `json:"sunshine_duration"`
+	LightningPotential            string `json:"lightning_potential"`
+	WeatherCode                   string `json:"weather_code"`
+	WindSpeed10m                  string `json:"wind_speed_10m"`
+	WindSpeed80m                  string `json:"wind_speed_80m"`
+	WindDirection10m              string `json:"wind_direction_10m"`
+	WindDirection80m              string `json:"wind_direction_80m"`
+	WindGusts10m                  string `json:"wind_gusts_10m"`
+	Visibility                    string `json:"visibility"`
+	IsDay                         string `json:"is_day"`
+	ShortwaveRadiation            string `json:"shortwave_radiation"`
+	DirectRadiation               string `json:"direct_radiation"`
+	DiffuseRadiation              string `json:"diffuse_radiation"`
+	DirectNormalIrradiance        string `json:"direct_normal_irradiance"`
+	GlobalTiltedIrradiance        string `json:"global_tilted_irradiance"`
+	TerrestrialRadiation          string `json:"terrestrial_radiation"`
+	ShortwaveRadiationInstant     string `json:"shortwave_radiation_instant"`
+	DirectRadiationInstant        string `json:"direct_radiation_instant"`
+	DiffuseRadiationInstant       string `json:"diffuse_radiation_instant"`
+	DirectNormalIrradianceInstant string `json:"direct_normal_irradiance_instant"`
+	GlobalTiltedIrradianceInstant string `json:"global_tilted_irradiance_instant"`
+	TerrestrialRadiationInstant   string `json:"terrestrial_radiation_instant"`
+}
+
+// Minutely15 holds slices for each 15-minutely forecast metric.
+type Minutely15 struct {
+	Time                          []string  `json:"time"`
+	Temperature2m                 []float64 `json:"temperature_2m"`
+	RelativeHumidity2m            []int     `json:"relative_humidity_2m"`
+	DewPoint2m                    []float64 `json:"dew_point_2m"`
+	ApparentTemperature           []float64 `json:"apparent_temperature"`
+	Precipitation                 []float64 `json:"precipitation"`
+	Rain                          []float64 `json:"rain"`
+	Showers                       []float64 `json:"showers"`
+	Snowfall                      []float64 `json:"snowfall"`
+	SnowfallHeight                []float64 `json:"snowfall_height"`
+	FreezingLevelHeight           []float64 `json:"freezing_level_height"`
+	SunshineDuration              []float64 `json:"sunshine_duration"`
+	LightningPotential            []float64 `json:"lightning_potential"`
+	WeatherCode                   []int     `json:"weather_code"`
+	WindSpeed10m                  []float64 `json:"wind_speed_10m"`
+	WindSpeed80m                  []float64 `json:"wind_speed_80m"`
+	WindDirection10m              []int     `json:"wind_direction_10m"`
+	WindDirection80m              []int     `json:"wind_direction_80m"`
+	WindGusts10m                  []float64 `json:"wind_gusts_10m"`
+	Visibility                    []float64 `json:"visibility"`
+	IsDay                         []int     `json:"is_day"`
+	ShortwaveRadiation            []float64 `json:"shortwave_radiation"`
+	DirectRadiation               []float64 `json:"direct_radiation"`
+	DiffuseRadiation              []float64 `json:"diffuse_radiation"`
+	DirectNormalIrradiance        []float64 `json:"direct_normal_irradiance"`
+	GlobalTiltedIrradiance        []float64 `json:"global_tilted_irradiance"`
+	TerrestrialRadiation          []float64 `json:"terrestrial_radiation"`
+	ShortwaveRadiationInstant     []float64 `json:"shortwave_radiation_instant"`
+	DirectRadiationInstant        []float64 `json:"direct_radiation_instant"`
+	DiffuseRadiationInstant       []float64 `json:"diffuse_radiation_instant"`
+	DirectNormalIrradianceInstant []float64 `json:"direct_normal_irradiance_instant"`
+	GlobalTiltedIrradianceInstant []float64 `json:"global_tilted_irradiance_instant"`
+	TerrestrialRadiationInstant   []float64 `json:"terrestrial_radiation_instant"`
 }
 
 // DailyUnits describes the units for the daily forecast data.
@@ -162,7 +162,33 @@ func TestClient_Get_ErrorConditions(t *testing.T) {
 	}
 }
 
+func TestClient_Get_LocalUnits(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Write([]byte(`{
+			"current_units": {"wind_speed_10m": "km/h", "pressure_msl": "hPa"},
+			"current": {"wind_speed_10m": 30.0, "pressure_msl": 1013.25}
+		}`))
+	}))
+	defer server.Close()
+
+	client := NewClient()
+	client.HTTPClient = server.Client()
+	urlParts := strings.Split(server.URL, "://")
+	client.scheme = urlParts[0]
+	client.host = urlParts[1]
+
+	opts := NewOptionsBuilder().WindspeedUnit(BFT).PressureUnit(INHG).Build()
+	wd, err := client.Get(opts)
+	require.NoError(t, err)
+	assert.Equal(t, "bft", wd.CurrentUnits.WindSpeed10m)
+	assert.Equal(t, 5.0, wd.Current.WindSpeed10m)
+	assert.Equal(t, "inHg", wd.CurrentUnits.PressureMsl)
+	assert.InDelta(t, 29.92, wd.Current.PressureMsl, 0.01)
+}
+
 func TestURL(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
 
 	tests := map[string]struct {
 		client  *Client
@@ -207,6 +233,54 @@ func TestURL(t *testing.T) {
 			options: *NewOptionsBuilder().Timezone(*time.UTC).PastDays(1).ForcastDays(2).Build(),
 			want:    "https://api.open-meteo.com/v1/forecast?forecast_days=2&latitude=0&longitude=0&past_days=1&timezone=UTC",
 		},
+		"length unit": {
+			client:  NewClient(),
+			options: *NewOptionsBuilder().Marine(true).LengthUnit(LengthImperial).Build(),
+			want:    "https://marine-api.open-meteo.com/v1/marine?latitude=0&length_unit=imperial&longitude=0",
+		},
+		"locally converted units": {
+			client:  NewClient(),
+			options: *NewOptionsBuilder().WindspeedUnit(BFT).PressureUnit(INHG).VisibilityUnit(Miles).Build(),
+			want:    "https://api.open-meteo.com/v1/forecast?latitude=0&longitude=0",
+		},
+		"hour counts": {
+			client:  NewClient(),
+			options: *NewOptionsBuilder().PastHours(3).ForecastHours(12).Build(),
+			want:    "https://api.open-meteo.com/v1/forecast?forecast_hours=12&latitude=0&longitude=0&past_hours=3",
+		},
+		"hour range": {
+			client: NewClient(),
+			options: *NewOptionsBuilder().
+				StartHour(time.Date(2099, 7, 27, 6, 0, 0, 0, time.UTC)).
+				EndHour(time.Date(2099, 7, 27, 18, 0, 0, 0, time.UTC)).
+				Build(),
+			want: "https://api.open-meteo.com/v1/forecast?end_hour=2099-07-27T18%3A00&latitude=0&longitude=0&start_hour=2099-07-27T06%3A00",
+		},
+		"hour range in the request timezone": {
+			client: NewClient(),
+			options: *NewOptionsBuilder().
+				Timezone(*berlin).
+				StartHour(time.Date(2099, 7, 27, 4, 30, 0, 0, time.UTC)).
+				EndHour(time.Date(2099, 7, 27, 16, 0, 0, 0, time.UTC)).
+				Build(),
+			want: "https://api.open-meteo.com/v1/forecast?end_hour=2099-07-27T18%3A00&latitude=0&longitude=0&start_hour=2099-07-27T06%3A00&timezone=Europe%2FBerlin",
+		},
+		"historical hour range": {
+			client: NewClient(),
+			options: *NewOptionsBuilder().
+				StartHour(time.Date(2000, 1, 1, 6, 0, 0, 0, time.UTC)).
+				EndHour(time.Date(2000, 1, 1, 18, 0, 0, 0, time.UTC)).
+				Build(),
+			want: "https://archive-api.open-meteo.com/v1/archive?end_hour=2000-01-01T18%3A00&latitude=0&longitude=0&start_hour=2000-01-01T06%3A00",
+		},
+		"minutely_15 range": {
+			client: NewClient(),
+			options: *NewOptionsBuilder().
+				StartMinutely15(time.Date(2025, 7, 27, 6, 15, 0, 0, time.UTC)).
+				EndMinutely15(time.Date(2025, 7, 27, 7, 45, 0, 0, time.UTC)).
+				Build(),
+			want: "https://api.open-meteo.com/v1/forecast?end_minutely_15=2025-07-27T07%3A45&latitude=0&longitude=0&start_minutely_15=2025-07-27T06%3A15",
+		},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
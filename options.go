@@ -34,20 +34,48 @@ type Options struct {
 	WindspeedUnit WindSpeedUnit
 	// PrecipitationUnit sets the unit for precipitation values. Default is millimeters.
 	PrecipitationUnit PrecipitationUnit
+	// LengthUnit sets the unit system for marine lengths such as wave heights. Default is metric.
+	LengthUnit LengthUnit
+	// PressureUnit sets the unit for pressure values, converted locally. Default is hPa.
+	PressureUnit PressureUnit
+	// VisibilityUnit sets the unit for visibility values, converted locally.
+	VisibilityUnit VisibilityUnit
 	// Timezone for the forecast data. Default is UTC.
 	Timezone time.Location
 	// PastDays specifies how many days of historical data to retrieve. Default is 0.
 	PastDays int
 	// ForcastDays specifies how many days of forecast data to retrieve.
 	ForcastDays int
+	// PastHours specifies how many hours of past data to retrieve.
+	PastHours int
+	// ForecastHours specifies how many hours of forecast data to retrieve.
+	ForecastHours int
+	// PastMinutely15 specifies how many 15-minute steps of past data to retrieve.
+	PastMinutely15 int
+	// ForecastMinutely15 specifies how many 15-minute steps of forecast data to retrieve.
+	ForecastMinutely15 int
 	// Start date for the historical data query.
 	Start time.Time
 	// End date for the historical data query.
 	End time.Time
+	// StartHour is the first hour of hourly data to return. It is sent in
+	// the request Timezone, truncated to the hour. If Start is zero and
+	// StartHour is older than the forecast API keeps, the request goes to
+	// the archive API.
+	StartHour time.Time
+	// EndHour is the last hour of hourly data to return, sent as StartHour.
+	EndHour time.Time
+	// StartMinutely15 is the first 15-minute step of minutely_15 data to
+	// return. It is sent in the request Timezone, truncated to the step.
+	StartMinutely15 time.Time
+	// EndMinutely15 is the last 15-minute step of minutely_15 data to return.
+	EndMinutely15 time.Time
 	// Models specifies the weather models to use (e.g. "ecmwf_seas5").
 	Models []string
 	// HourlyMetrics specifies which hourly weather variables to retrieve.
 	HourlyMetrics Metrics
+	// Minutely15Metrics specifies which 15-minutely weather variables to retrieve.
+	Minutely15Metrics Metrics
 	// DailyMetrics specifies which daily weather variables to retrieve.
 	DailyMetrics Metrics
 	// WeeklyMetrics specifies which weekly weather variables to retrieve (Seasonal API).
@@ -56,10 +84,31 @@ type Options struct {
 	MonthlyMetrics Metrics
 	// CurrentMetrics specifies which current weather variables to retrieve.
 	CurrentMetrics Metrics
+	// Tilt is the panel inclination in degrees used for global_tilted_irradiance.
+	// 0° is horizontal, 90° is vertical.
+	Tilt float64
+	// Azimuth is the panel orientation in degrees used for global_tilted_irradiance.
+	// 0° is south, -90° is east, 90° is west.
+	Azimuth float64
 	// Seasonal forces the request to use the seasonal API endpoint.
 	Seasonal bool
 	// Marine forces the request to use the marine API endpoint.
 	Marine bool
+	// FlatBuffers requests the response in the API's binary FlatBuffers
+	// format, which decodes much faster than JSON for long or multi-model
+	// requests. Weekly and monthly metrics are not available. Missing float
+	// values decode as NaN rather than zero.
+	FlatBuffers bool
+}
+
+// localUnits returns the units the API cannot serve, which are converted
+// after the response is decoded.
+func (o *Options) localUnits() Units {
+	u := Units{Pressure: o.PressureUnit, Visibility: o.VisibilityUnit}
+	if o.WindspeedUnit == BFT {
+		u.WindSpeed = BFT
+	}
+	return u
 }
 
 // OptionsBuilder provides a fluent interface for constructing an Options object.
@@ -102,6 +151,24 @@ func (b *OptionsBuilder) PrecipitationUnit(unit PrecipitationUnit) *OptionsBuild
 	return b
 }
 
+// LengthUnit sets the desired unit system for marine lengths.
+func (b *OptionsBuilder) LengthUnit(unit LengthUnit) *OptionsBuilder {
+	b.options.LengthUnit = unit
+	return b
+}
+
+// PressureUnit sets the desired unit for pressure measurements.
+func (b *OptionsBuilder) PressureUnit(unit PressureUnit) *OptionsBuilder {
+	b.options.PressureUnit = unit
+	return b
+}
+
+// VisibilityUnit sets the desired unit for visibility measurements.
+func (b *OptionsBuilder) VisibilityUnit(unit VisibilityUnit) *OptionsBuilder {
+	b.options.VisibilityUnit = unit
+	return b
+}
+
 // Timezone sets the timezone for the returned data.
 func (b *OptionsBuilder) Timezone(tz time.Location) *OptionsBuilder {
 	b.options.Timezone = tz
@@ -120,6 +187,30 @@ func (b *OptionsBuilder) ForcastDays(days int) *OptionsBuilder {
 	return b
 }
 
+// PastHours sets the number of past hours to retrieve data for.
+func (b *OptionsBuilder) PastHours(hours int) *OptionsBuilder {
+	b.options.PastHours = hours
+	return b
+}
+
+// ForecastHours sets the number of future hours to retrieve data for.
+func (b *OptionsBuilder) ForecastHours(hours int) *OptionsBuilder {
+	b.options.ForecastHours = hours
+	return b
+}
+
+// PastMinutely15 sets the number of past 15-minute steps to retrieve data for.
+func (b *OptionsBuilder) PastMinutely15(steps int) *OptionsBuilder {
+	b.options.PastMinutely15 = steps
+	return b
+}
+
+// ForecastMinutely15 sets the number of future 15-minute steps to retrieve data for.
+func (b *OptionsBuilder) ForecastMinutely15(steps int) *OptionsBuilder {
+	b.options.ForecastMinutely15 = steps
+	return b
+}
+
 // Start sets the start date for a specific time-range query.
 func (b *OptionsBuilder) Start(start time.Time) *OptionsBuilder {
 	b.options.Start = start
@@ -132,6 +223,30 @@ func (b *OptionsBuilder) End(end time.Time) *OptionsBuilder {
 	return b
 }
 
+// StartHour sets the first hour for an hourly time-range query.
+func (b *OptionsBuilder) StartHour(start time.Time) *OptionsBuilder {
+	b.options.StartHour = start
+	return b
+}
+
+// EndHour sets the last hour for an hourly time-range query.
+func (b *OptionsBuilder) EndHour(end time.Time) *OptionsBuilder {
+	b.options.EndHour = end
+	return b
+}
+
+// StartMinutely15 sets the first 15-minute step for a minutely_15 time-range query.
+func (b *OptionsBuilder) StartMinutely15(start time.Time) *OptionsBuilder {
+	b.options.StartMinutely15 = start
+	return b
+}
+
+// EndMinutely15 sets the last 15-minute step for a minutely_15 time-range query.
+func (b *OptionsBuilder) EndMinutely15(end time.Time) *OptionsBuilder {
+	b.options.EndMinutely15 = end
+	return b
+}
+
 // Models sets the specific weather models to be used.
 func (b *OptionsBuilder) Models(models []string) *OptionsBuilder {
 	b.options.Models = models
@@ -144,6 +259,12 @@ func (b *OptionsBuilder) HourlyMetrics(metrics Metrics) *OptionsBuilder {
 	return b
 }
 
+// Minutely15Metrics sets the specific 15-minutely metrics to be fetched.
+func (b *OptionsBuilder) Minutely15Metrics(metrics Metrics) *OptionsBuilder {
+	b.options.Minutely15Metrics = metrics
+	return b
+}
+
 // DailyMetrics sets the specific daily metrics to be fetched.
 func (b *OptionsBuilder) DailyMetrics(metrics Metrics) *OptionsBuilder {
 	b.options.DailyMetrics = metrics
@@ -168,6 +289,18 @@ func (b *OptionsBuilder) CurrentMetrics(metrics Metrics) *OptionsBuilder {
 	return b
 }
 
+// Tilt sets the panel inclination used for global tilted irradiance.
+func (b *OptionsBuilder) Tilt(degrees float64) *OptionsBuilder {
+	b.options.Tilt = degrees
+	return b
+}
+
+// Azimuth sets the panel orientation used for global tilted irradiance.
+func (b *OptionsBuilder) Azimuth(degrees float64) *OptionsBuilder {
+	b.options.Azimuth = degrees
+	return b
+}
+
 // Seasonal forces the request to use the seasonal API endpoint.
 func (b *OptionsBuilder) Seasonal(seasonal bool) *OptionsBuilder {
 	b.options.Seasonal = seasonal
@@ -180,6 +313,13 @@ func (b *OptionsBuilder) Marine(marine bool) *OptionsBuilder {
 	return b
 }
 
+// FlatBuffers requests the response in the API's FlatBuffers format instead
+// of JSON.
+func (b *OptionsBuilder) FlatBuffers(flatBuffers bool) *OptionsBuilder {
+	b.options.FlatBuffers = flatBuffers
+	return b
+}
+
 // Build finalizes the construction and returns the configured Options object.
 func (b *OptionsBuilder) Build() *Options {
 	return b.options
@@ -261,6 +401,23 @@ const (
 	WindGusts10mMax             Metric = "wind_gusts_10m_max"
 	WindDirection10mDominant    Metric = "wind_direction_10m_dominant"
 	ShortwaveRadiationSum       Metric = "shortwave_radiation_sum"
+	SnowfallHeight              Metric = "snowfall_height"
+	FreezingLevelHeight         Metric = "freezing_level_height"
+	LightningPotential          Metric = "lightning_potential"
+
+	// Solar Radiation Metrics (Hourly & Minutely15)
+	ShortwaveRadiation            Metric = "shortwave_radiation"
+	DirectRadiation               Metric = "direct_radiation"
+	DiffuseRadiation              Metric = "diffuse_radiation"
+	DirectNormalIrradiance        Metric = "direct_normal_irradiance"
+	GlobalTiltedIrradiance        Metric = "global_tilted_irradiance"
+	TerrestrialRadiation          Metric = "terrestrial_radiation"
+	ShortwaveRadiationInstant     Metric = "shortwave_radiation_instant"
+	DirectRadiationInstant        Metric = "direct_radiation_instant"
+	DiffuseRadiationInstant       Metric = "diffuse_radiation_instant"
+	DirectNormalIrradianceInstant Metric = "direct_normal_irradiance_instant"
+	GlobalTiltedIrradianceInstant Metric = "global_tilted_irradiance_instant"
+	TerrestrialRadiationInstant   Metric = "terrestrial_radiation_instant"
 
 	// Seasonal Metrics (Weekly & Monthly)
 	Temperature2mMean          Metric = "temperature_2m_mean"
@@ -355,6 +512,18 @@ var hourlyMetrics = []Metric{
 	SoilMoisture1To3cm,
 	SoilMoisture9To27cm,
 	SoilMoisture3To9cm,
+	ShortwaveRadiation,
+	DirectRadiation,
+	DiffuseRadiation,
+	DirectNormalIrradiance,
+	GlobalTiltedIrradiance,
+	TerrestrialRadiation,
+	ShortwaveRadiationInstant,
+	DirectRadiationInstant,
+	DiffuseRadiationInstant,
+	DirectNormalIrradianceInstant,
+	GlobalTiltedIrradianceInstant,
+	TerrestrialRadiationInstant,
 }
 
 func NewMetrics(metricType string, Metrics ...Metric) (Metrics, error) {
@@ -364,6 +533,8 @@ func NewMetrics(metricType string, Metrics ...Metric) (Metrics, error) {
 	switch metricType {
 	case "hourly":
 		allowed = hourlyMetrics
+	case "minutely_15":
+		allowed = minutely15Metrics
 	case "daily":
 		allowed = dailyMetrics
 	case "current":
@@ -378,6 +549,13 @@ func NewMetrics(metricType string, Metrics ...Metric) (Metrics, error) {
 
 	for _, metric := range Metrics {
 
+		if metricType == "hourly" {
+			if _, _, ok := parsePressureLevelMetric(string(metric)); ok {
+				result = append(result, metric)
+				continue
+			}
+		}
+
 		if !slices.Contains(allowed, metric) {
 			return nil, fmt.Errorf("invalid for %s metrics: %s ", metricType, metric)
 		}
@@ -406,6 +584,43 @@ var currentMetrics = []Metric{
 	WindGusts10m,
 }
 
+// minutely15Metrics lists the variables the forecast API offers at 15-minute
+// resolution.
+var minutely15Metrics = []Metric{
+	Temperature2m,
+	RelativeHumidity2m,
+	DewPoint2m,
+	ApparentTemperature,
+	Precipitation,
+	Rain,
+	Showers,
+	Snowfall,
+	SnowfallHeight,
+	FreezingLevelHeight,
+	SunshineDuration,
+	LightningPotential,
+	WeatherCode,
+	WindSpeed10m,
+	WindSpeed80m,
+	WindDirection10m,
+	WindDirection80m,
+	WindGusts10m,
+	Visibility,
+	IsDay,
+	ShortwaveRadiation,
+	DirectRadiation,
+	DiffuseRadiation,
+	DirectNormalIrradiance,
+	GlobalTiltedIrradiance,
+	TerrestrialRadiation,
+	ShortwaveRadiationInstant,
+	DirectRadiationInstant,
+	DiffuseRadiationInstant,
+	DirectNormalIrradianceInstant,
+	GlobalTiltedIrradianceInstant,
+	TerrestrialRadiationInstant,
+}
+
 var dailyMetrics = []Metric{
 	WeatherCode,
 	Temperature2mMax,
@@ -0,0 +1,253 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openmeteogo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// PressureVariable is an upper-air variable available on pressure levels.
+type PressureVariable string
+
+const (
+	PressureTemperature        PressureVariable = "temperature"
+	PressureRelativeHumidity   PressureVariable = "relative_humidity"
+	PressureDewPoint           PressureVariable = "dew_point"
+	PressureCloudCover         PressureVariable = "cloud_cover"
+	PressureWindSpeed          PressureVariable = "wind_speed"
+	PressureWindDirection      PressureVariable = "wind_direction"
+	PressureGeopotentialHeight PressureVariable = "geopotential_height"
+)
+
+var pressureVariables = []PressureVariable{
+	PressureTemperature,
+	PressureRelativeHumidity,
+	PressureDewPoint,
+	PressureCloudCover,
+	PressureWindSpeed,
+	PressureWindDirection,
+	PressureGeopotentialHeight,
+}
+
+// PressureLevels lists the supported pressure levels in hPa, from the surface
+// upwards.
+var PressureLevels = []int{1000, 975, 950, 925, 900, 850, 800, 700, 600, 500, 400, 300, 250, 200, 150, 100, 70, 50, 30}
+
+// PressureLevelMetric returns the hourly metric for a variable at a pressure
+// level, e.g. "temperature_850hPa".
+func PressureLevelMetric(variable PressureVariable, level int) (Metric, error) {
+	if !slices.Contains(pressureVariables, variable) {
+		return "", fmt.Errorf("invalid pressure level variable: %s", variable)
+	}
+	if !slices.Contains(PressureLevels, level) {
+		return "", fmt.Errorf("invalid pressure level: %dhPa", level)
+	}
+	return Metric(fmt.Sprintf("%s_%dhPa", variable, level)), nil
+}
+
+// PressureLevelMetrics returns the hourly metrics for every combination of
+// the given variables and levels.
+func PressureLevelMetrics(levels []int, variables ...PressureVariable) (Metrics, error) {
+	result := Metrics{}
+	for _, level := range levels {
+		for _, variable := range variables {
+			m, err := PressureLevelMetric(variable, level)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, m)
+		}
+	}
+	return result, nil
+}
+
+// parsePressureLevelMetric splits a metric such as "wind_speed_500hPa" into
+// its variable and level. It reports false for anything else.
+func parsePressureLevelMetric(name string) (PressureVariable, int, bool) {
+	if !strings.HasSuffix(name, "hPa") {
+		return "", 0, false
+	}
+	i := strings.LastIndex(name, "_")
+	if i < 0 {
+		return "", 0, false
+	}
+	level, err := strconv.Atoi(strings.TrimSuffix(name[i+1:], "hPa"))
+	if err != nil {
+		return "", 0, false
+	}
+	variable := PressureVariable(name[:i])
+	if !slices.Contains(pressureVariables, variable) || !slices.Contains(PressureLevels, level) {
+		return "", 0, false
+	}
+	return variable, level, true
+}
+
+// PressureLevelUnits describes the units for the variables at one pressure level.
+type PressureLevelUnits struct {
+	Temperature        string `json:"temperature,omitempty"`
+	RelativeHumidity   string `json:"relative_humidity,omitempty"`
+	DewPoint           string `json:"dew_point,omitempty"`
+	CloudCover         string `json:"cloud_cover,omitempty"`
+	WindSpeed          string `json:"wind_speed,omitempty"`
+	WindDirection      string `json:"wind_direction,omitempty"`
+	GeopotentialHeight string `json:"geopotential_height,omitempty"`
+}
+
+// PressureLevel holds the hourly series for the variables at one pressure level.
+type PressureLevel struct {
+	Temperature        []float64 `json:"temperature,omitempty"`
+	RelativeHumidity   []int     `json:"relative_humidity,omitempty"`
+	DewPoint           []float64 `json:"dew_point,omitempty"`
+	CloudCover         []int     `json:"cloud_cover,omitempty"`
+	WindSpeed          []float64 `json:"wind_speed,omitempty"`
+	WindDirection      []int     `json:"wind_direction,omitempty"`
+	GeopotentialHeight []float64 `json:"geopotential_height,omitempty"`
+}
+
+// groupPressureLevels collects the pressure level fields of a JSON object
+// into one object per level, keyed by the bare variable name.
+func groupPressureLevels(data []byte) (map[int]map[string]json.RawMessage, error) {
+	if !bytes.Contains(data, []byte(`hPa"`)) {
+		return nil, nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	levels := map[int]map[string]json.RawMessage{}
+	for key, val := range fields {
+		variable, level, ok := parsePressureLevelMetric(key)
+		if !ok {
+			continue
+		}
+		if levels[level] == nil {
+			levels[level] = map[string]json.RawMessage{}
+		}
+		levels[level][string(variable)] = val
+	}
+
+	if len(levels) == 0 {
+		return nil, nil
+	}
+	return levels, nil
+}
+
+// decodePressureLevels decodes the grouped level objects into T.
+func decodePressureLevels[T any](data []byte) (map[int]*T, error) {
+	levels, err := groupPressureLevels(data)
+	if err != nil || levels == nil {
+		return nil, err
+	}
+
+	result := map[int]*T{}
+	for level, fields := range levels {
+		buf, err := json.Marshal(fields)
+		if err != nil {
+			return nil, err
+		}
+		var v T
+		if err := json.Unmarshal(buf, &v); err != nil {
+			return nil, fmt.Errorf("pressure level %dhPa: %w", level, err)
+		}
+		result[level] = &v
+	}
+	return result, nil
+}
+
+// encodePressureLevels adds the level fields back onto an encoded JSON object
+// using the API's "<variable>_<level>hPa" naming.
+func encodePressureLevels[T any](data []byte, levels map[int]*T) ([]byte, error) {
+	if len(levels) == 0 {
+		return data, nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	for level, v := range levels {
+		buf, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		var vars map[string]json.RawMessage
+		if err := json.Unmarshal(buf, &vars); err != nil {
+			return nil, err
+		}
+		for name, val := range vars {
+			fields[fmt.Sprintf("%s_%dhPa", name, level)] = val
+		}
+	}
+
+	return json.Marshal(fields)
+}
+
+type hourlyAlias Hourly
+
+// UnmarshalJSON decodes the hourly section, grouping any pressure level
+// variables by level into PressureLevels.
+func (h *Hourly) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, (*hourlyAlias)(h)); err != nil {
+		return err
+	}
+	levels, err := decodePressureLevels[PressureLevel](data)
+	if err != nil {
+		return err
+	}
+	h.PressureLevels = levels
+	return nil
+}
+
+// MarshalJSON encodes the hourly section, including pressure level variables.
+func (h Hourly) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(hourlyAlias(h))
+	if err != nil {
+		return nil, err
+	}
+	return encodePressureLevels(data, h.PressureLevels)
+}
+
+type hourlyUnitsAlias HourlyUnits
+
+// UnmarshalJSON decodes the hourly units, grouping any pressure level
+// variables by level into PressureLevels.
+func (u *HourlyUnits) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, (*hourlyUnitsAlias)(u)); err != nil {
+		return err
+	}
+	levels, err := decodePressureLevels[PressureLevelUnits](data)
+	if err != nil {
+		return err
+	}
+	u.PressureLevels = levels
+	return nil
+}
+
+// MarshalJSON encodes the hourly units, including pressure level variables.
+func (u HourlyUnits) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(hourlyUnitsAlias(u))
+	if err != nil {
+		return nil, err
+	}
+	return encodePressureLevels(data, u.PressureLevels)
+}
@@ -0,0 +1,131 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openmeteogo
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPressureLevelMetric(t *testing.T) {
+	tests := map[string]struct {
+		variable PressureVariable
+		level    int
+		want     Metric
+		wantErr  bool
+	}{
+		"temperature 850": {
+			variable: PressureTemperature,
+			level:    850,
+			want:     "temperature_850hPa",
+		},
+		"geopotential height 300": {
+			variable: PressureGeopotentialHeight,
+			level:    300,
+			want:     "geopotential_height_300hPa",
+		},
+		"unsupported level": {
+			variable: PressureTemperature,
+			level:    825,
+			wantErr:  true,
+		},
+		"unsupported variable": {
+			variable: "vorticity",
+			level:    500,
+			wantErr:  true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := PressureLevelMetric(tc.variable, tc.level)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestPressureLevelMetrics(t *testing.T) {
+	got, err := PressureLevelMetrics([]int{850, 500}, PressureTemperature, PressureWindSpeed)
+	require.NoError(t, err)
+	assert.Equal(t, "temperature_850hPa,wind_speed_850hPa,temperature_500hPa,wind_speed_500hPa", got.encode())
+
+	hourly, err := NewMetrics("hourly", append(got, Temperature2m)...)
+	require.NoError(t, err)
+	assert.Len(t, hourly, 5)
+
+	_, err = NewMetrics("daily", got...)
+	assert.Error(t, err)
+}
+
+func TestClient_Get_PressureLevels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		rw.Write([]byte(`{
+			"latitude": 52.52,
+			"longitude": 13.41,
+			"hourly_units": {
+				"time": "iso8601",
+				"temperature_2m": "°C",
+				"temperature_850hPa": "°C",
+				"wind_speed_500hPa": "km/h",
+				"geopotential_height_300hPa": "m"
+			},
+			"hourly": {
+				"time": ["2025-01-01T00:00", "2025-01-01T01:00"],
+				"temperature_2m": [3.0, 2.5],
+				"temperature_850hPa": [-4.2, -4.6],
+				"wind_speed_500hPa": [80.1, 82.3],
+				"geopotential_height_300hPa": [9120.0, 9115.0]
+			}
+		}`))
+	}))
+	defer server.Close()
+
+	client := NewClient()
+	client.HTTPClient = server.Client()
+	urlParts := strings.Split(server.URL, "://")
+	client.scheme = urlParts[0]
+	client.host = urlParts[1]
+
+	wd, err := client.Get(NewOptionsBuilder().Build())
+	require.NoError(t, err)
+
+	assert.Equal(t, []float64{3.0, 2.5}, wd.Hourly.Temperature2m)
+	require.Len(t, wd.Hourly.PressureLevels, 3)
+	assert.Equal(t, []float64{-4.2, -4.6}, wd.Hourly.PressureLevels[850].Temperature)
+	assert.Equal(t, []float64{80.1, 82.3}, wd.Hourly.PressureLevels[500].WindSpeed)
+	assert.Equal(t, []float64{9120.0, 9115.0}, wd.Hourly.PressureLevels[300].GeopotentialHeight)
+	assert.Equal(t, "°C", wd.HourlyUnits.PressureLevels[850].Temperature)
+	assert.Equal(t, "km/h", wd.HourlyUnits.PressureLevels[500].WindSpeed)
+
+	// Pressure levels survive a round trip through JSON.
+	data, err := json.Marshal(wd)
+	require.NoError(t, err)
+	var got WeatherData
+	require.NoError(t, json.Unmarshal(data, &got))
+	assert.Equal(t, wd.Hourly.PressureLevels, got.Hourly.PressureLevels)
+	assert.Equal(t, wd.HourlyUnits.PressureLevels, got.HourlyUnits.PressureLevels)
+}
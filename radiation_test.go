@@ -0,0 +1,108 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openmeteogo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestURL_Radiation(t *testing.T) {
+	tests := map[string]struct {
+		client  *Client
+		options Options
+		want    string
+	}{
+		"tilted irradiance": {
+			client: NewClient(),
+			options: *NewOptionsBuilder().
+				HourlyMetrics(Metrics{GlobalTiltedIrradiance}).
+				Tilt(35).
+				Azimuth(-15.5).
+				Build(),
+			want: "https://api.open-meteo.com/v1/forecast?azimuth=-15.5&hourly=global_tilted_irradiance&latitude=0&longitude=0&tilt=35",
+		},
+		"minutely_15 radiation": {
+			client:  NewClient(),
+			options: *NewOptionsBuilder().Minutely15Metrics(Metrics{DirectNormalIrradianceInstant}).Build(),
+			want:    "https://api.open-meteo.com/v1/forecast?latitude=0&longitude=0&minutely_15=direct_normal_irradiance_instant",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := tc.client.url(&tc.options)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestNewMetrics_Radiation(t *testing.T) {
+	for _, metricType := range []string{"hourly", "minutely_15"} {
+		_, err := NewMetrics(metricType,
+			ShortwaveRadiation, DirectRadiation, DiffuseRadiation,
+			DirectNormalIrradiance, GlobalTiltedIrradiance, TerrestrialRadiation,
+			ShortwaveRadiationInstant, GlobalTiltedIrradianceInstant,
+		)
+		assert.NoError(t, err, metricType)
+	}
+}
+
+func TestClient_Get_Radiation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		rw.Write([]byte(`{
+			"latitude": 52.52,
+			"longitude": 13.41,
+			"hourly_units": {
+				"time": "iso8601",
+				"shortwave_radiation": "W/m²",
+				"global_tilted_irradiance": "W/m²"
+			},
+			"hourly": {
+				"time": ["2025-06-01T12:00"],
+				"shortwave_radiation": [812.0],
+				"global_tilted_irradiance": [901.5]
+			},
+			"minutely_15_units": {
+				"time": "iso8601",
+				"direct_normal_irradiance_instant": "W/m²"
+			},
+			"minutely_15": {
+				"time": ["2025-06-01T12:00"],
+				"direct_normal_irradiance_instant": [870.2]
+			}
+		}`))
+	}))
+	defer server.Close()
+
+	client := NewClient()
+	client.HTTPClient = server.Client()
+	urlParts := strings.Split(server.URL, "://")
+	client.scheme = urlParts[0]
+	client.host = urlParts[1]
+
+	wd, err := client.Get(NewOptionsBuilder().Tilt(30).Build())
+	require.NoError(t, err)
+	assert.Equal(t, "W/m²", wd.HourlyUnits.ShortwaveRadiation)
+	assert.Equal(t, []float64{812.0}, wd.Hourly.ShortwaveRadiation)
+	assert.Equal(t, []float64{901.5}, wd.Hourly.GlobalTiltedIrradiance)
+	assert.Equal(t, []float64{870.2}, wd.Minutely15.DirectNormalIrradianceInstant)
+}
@@ -0,0 +1,401 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openmeteogo
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Values returns the hourly series for a metric as float64 values, whether
+// the field holds floats or integers. Pressure level metrics such as
+// "temperature_850hPa" are supported. It returns an error if the metric is
+// unknown or was not returned.
+func (h *Hourly) Values(m Metric) ([]float64, error) {
+	var data any = h
+	name := string(m)
+	if variable, level, ok := parsePressureLevelMetric(name); ok {
+		pl, ok := h.PressureLevels[level]
+		if !ok {
+			return nil, fmt.Errorf("no data for metric: %s", m)
+		}
+		data, name = pl, string(variable)
+	}
+	values, ok := seriesValues(data, name)
+	if !ok {
+		return nil, fmt.Errorf("unknown hourly metric: %s", m)
+	}
+	if values == nil {
+		return nil, fmt.Errorf("no data for metric: %s", m)
+	}
+	return values, nil
+}
+
+// Values returns the daily series for a metric as float64 values, whether
+// the field holds floats or integers. It returns an error if the metric is
+// unknown or was not returned.
+func (d *Daily) Values(m Metric) ([]float64, error) {
+	values, ok := seriesValues(d, string(m))
+	if !ok {
+		return nil, fmt.Errorf("unknown daily metric: %s", m)
+	}
+	if values == nil {
+		return nil, fmt.Errorf("no data for metric: %s", m)
+	}
+	return values, nil
+}
+
+// Value returns the current value of a metric as a float64, whether the
+// field holds a float or an integer. It returns an error if the metric is
+// unknown.
+func (c *Current) Value(m Metric) (float64, error) {
+	v := reflect.ValueOf(c).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if jsonName(v.Type().Field(i)) != string(m) {
+			continue
+		}
+		switch field := v.Field(i); field.Kind() {
+		case reflect.Float64:
+			return field.Float(), nil
+		case reflect.Int:
+			return float64(field.Int()), nil
+		}
+		break
+	}
+	return 0, fmt.Errorf("unknown current metric: %s", m)
+}
+
+// seriesValues returns the []float64 or []int field of data, a pointer to a
+// section struct, with the given JSON name. A field without data returns nil.
+func seriesValues(data any, name string) ([]float64, bool) {
+	v := reflect.ValueOf(data).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if jsonName(v.Type().Field(i)) != name {
+			continue
+		}
+		field := v.Field(i)
+		if field.Kind() != reflect.Slice {
+			return nil, false
+		}
+		if field.Len() == 0 {
+			return nil, true
+		}
+		values := make([]float64, field.Len())
+		for j := range values {
+			switch e := field.Index(j); e.Kind() {
+			case reflect.Float64:
+				values[j] = e.Float()
+			case reflect.Int:
+				values[j] = float64(e.Int())
+			default:
+				return nil, false
+			}
+		}
+		return values, true
+	}
+	return nil, false
+}
+
+// Period groups timestamps into aggregation periods.
+type Period interface {
+	// Start returns the start of the period containing t, or false if t
+	// falls outside every period. Resample passes the wall clock time of
+	// each timestamp in the response's timezone, with the location UTC.
+	Start(t time.Time) (time.Time, bool)
+}
+
+// wallClock returns the wall clock time of t with the location UTC, as
+// Resample passes timestamps to a Period.
+func wallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
+
+type dayPeriod struct {
+	offset time.Duration
+}
+
+func (p dayPeriod) Start(t time.Time) (time.Time, bool) {
+	s := t.Add(-p.offset)
+	return time.Date(s.Year(), s.Month(), s.Day(), 0, 0, 0, 0, s.Location()).Add(p.offset), true
+}
+
+// Days returns a Period of calendar days that start offset after midnight.
+// Days(6*time.Hour) groups hours from 06:00 to 06:00 the next day.
+func Days(offset time.Duration) Period {
+	return dayPeriod{offset}
+}
+
+type weekPeriod struct {
+	first time.Weekday
+}
+
+func (p weekPeriod) Start(t time.Time) (time.Time, bool) {
+	day, _ := dayPeriod{}.Start(t)
+	back := (int(day.Weekday()) - int(p.first) + 7) % 7
+	return day.AddDate(0, 0, -back), true
+}
+
+// Weeks returns a Period of weeks starting at midnight on the given
+// weekday. Weeks(time.Monday) gives ISO weeks.
+func Weeks(first time.Weekday) Period {
+	return weekPeriod{first}
+}
+
+type everyPeriod struct {
+	length time.Duration
+	origin time.Time
+}
+
+func (p everyPeriod) Start(t time.Time) (time.Time, bool) {
+	// Work in whole seconds: the span between t and a zero origin does not
+	// fit in a time.Duration.
+	length := int64(p.length / time.Second)
+	if length <= 0 {
+		return time.Time{}, false
+	}
+	origin := wallClock(p.origin).Unix()
+	n := (t.Unix() - origin) / length
+	if t.Unix() < origin+n*length {
+		n--
+	}
+	return time.Unix(origin+n*length, 0).In(t.Location()), true
+}
+
+// Every returns a Period of fixed length windows aligned to origin, such as
+// Every(3*time.Hour, time.Time{}) for three-hourly periods. The origin is
+// taken as a wall clock time, whatever its location. The length is
+// truncated to whole seconds.
+func Every(length time.Duration, origin time.Time) Period {
+	return everyPeriod{length, origin}
+}
+
+// Window is a time range that includes Start and excludes End. Start and
+// End are taken as wall clock times, whatever their location, so a window
+// from 06:00 to 08:00 in Europe/Berlin matches those hours of a response
+// in that timezone.
+type Window struct {
+	Start, End time.Time
+}
+
+type windowPeriod []Window
+
+func (p windowPeriod) Start(t time.Time) (time.Time, bool) {
+	for _, w := range p {
+		if !t.Before(wallClock(w.Start)) && t.Before(wallClock(w.End)) {
+			return w.Start, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Windows returns a Period made of arbitrary windows. Hours outside every
+// window are ignored, and an hour inside overlapping windows belongs to the
+// first of them.
+func Windows(windows ...Window) Period {
+	return windowPeriod(windows)
+}
+
+// Aggregation reduces the values in a period to a single value.
+type Aggregation struct {
+	name       string
+	percentile float64
+}
+
+// The aggregations supported by Resample.
+var (
+	AggregateSum  = Aggregation{name: "sum"}
+	AggregateMean = Aggregation{name: "mean"}
+	AggregateMin  = Aggregation{name: "min"}
+	AggregateMax  = Aggregation{name: "max"}
+	// AggregateVectorMean averages directions in degrees as unit vectors, so
+	// 350° and 10° average to 0° rather than 180°.
+	AggregateVectorMean = Aggregation{name: "vector_mean"}
+)
+
+// AggregatePercentile returns the Aggregation for the p-th percentile, with
+// p between 0 and 100. Values between ranks are interpolated linearly.
+func AggregatePercentile(p float64) Aggregation {
+	return Aggregation{name: "p" + strconv.FormatFloat(p, 'f', -1, 64), percentile: p}
+}
+
+// String returns the aggregation's name, e.g. "max" or "p90".
+func (a Aggregation) String() string {
+	return a.name
+}
+
+func (a Aggregation) apply(values []float64) float64 {
+	switch a.name {
+	case "sum", "mean":
+		sum := 0.0
+		for _, v := range values {
+			sum += v
+		}
+		if a.name == "mean" {
+			return sum / float64(len(values))
+		}
+		return sum
+	case "min":
+		return slices.Min(values)
+	case "max":
+		return slices.Max(values)
+	case "vector_mean":
+		var x, y float64
+		for _, v := range values {
+			rad := v * math.Pi / 180
+			x += math.Sin(rad)
+			y += math.Cos(rad)
+		}
+		// Round away floating point noise so that north is 0°, not 359.99…°.
+		deg := math.Round(math.Atan2(x, y)*180/math.Pi*1e9) / 1e9
+		if deg < 0 {
+			deg += 360
+		}
+		return deg
+	}
+	sorted := slices.Clone(values)
+	sort.Float64s(sorted)
+	rank := a.percentile / 100 * float64(len(sorted)-1)
+	lo := int(math.Floor(rank))
+	hi := int(math.Ceil(rank))
+	return sorted[lo] + (sorted[hi]-sorted[lo])*(rank-float64(lo))
+}
+
+func (a Aggregation) validate() error {
+	if !strings.HasPrefix(a.name, "p") {
+		if a.name == "" {
+			return fmt.Errorf("missing aggregation")
+		}
+		return nil
+	}
+	if a.percentile < 0 || a.percentile > 100 {
+		return fmt.Errorf("percentile out of range: %v", a.percentile)
+	}
+	return nil
+}
+
+// Aggregate pairs a metric with the aggregation to apply to it.
+type Aggregate struct {
+	Metric      Metric
+	Aggregation Aggregation
+}
+
+// Name returns the key the aggregate's values are stored under in a
+// Bucket, the metric name followed by the aggregation, e.g.
+// "cloud_cover_max".
+func (a Aggregate) Name() string {
+	return string(a.Metric) + "_" + a.Aggregation.String()
+}
+
+// Bucket holds the aggregated values for one period.
+type Bucket struct {
+	// Start is the start of the period.
+	Start time.Time
+	// Hours is the number of hourly values in the period.
+	Hours int
+	// Values holds the aggregated values, keyed by Aggregate.Name.
+	Values map[string]float64
+}
+
+// Resample aggregates the hourly data into periods, returning one Bucket
+// per period that contains data, ordered by start. Timestamps are treated
+// as wall clock times in the response's timezone, so a Days period follows
+// local midnight and a Window from 06:00 to 08:00 in that timezone covers
+// those local hours. Periods only partly covered by the data are aggregated
+// over the hours available; check Bucket.Hours to detect them.
+func (h *Hourly) Resample(p Period, aggregates ...Aggregate) ([]Bucket, error) {
+	if len(aggregates) == 0 {
+		return nil, fmt.Errorf("no aggregates")
+	}
+
+	series := make([][]float64, len(aggregates))
+	for i, a := range aggregates {
+		if err := a.Aggregation.validate(); err != nil {
+			return nil, fmt.Errorf("%s: %w", a.Metric, err)
+		}
+		values, err := h.Values(a.Metric)
+		if err != nil {
+			return nil, err
+		}
+		if len(values) != len(h.Time) {
+			return nil, fmt.Errorf("%s: need %d values, got %d", a.Metric, len(h.Time), len(values))
+		}
+		series[i] = values
+	}
+
+	keys := make([]string, len(aggregates))
+	aggregations := make([]Aggregation, len(aggregates))
+	for i, a := range aggregates {
+		keys[i], aggregations[i] = a.Name(), a.Aggregation
+	}
+	return resample(h.Time, p, keys, aggregations, series)
+}
+
+// ResampleSeries aggregates an hourly series that is not a metric, such as
+// one computed from several metrics, into periods as Hourly.Resample does.
+// times holds the timestamp of each value, as in Hourly.Time. Each Bucket
+// holds the aggregated value under the aggregation's name, e.g. "mean".
+func ResampleSeries(times []string, values []float64, p Period, a Aggregation) ([]Bucket, error) {
+	if err := a.validate(); err != nil {
+		return nil, err
+	}
+	if len(values) != len(times) {
+		return nil, fmt.Errorf("need %d values, got %d", len(times), len(values))
+	}
+	return resample(times, p, []string{a.String()}, []Aggregation{a}, [][]float64{values})
+}
+
+// resample groups the timestamps into periods and applies each aggregation
+// to its series over every period, storing the result under its key.
+func resample(times []string, p Period, keys []string, aggregations []Aggregation, series [][]float64) ([]Bucket, error) {
+	var starts []time.Time
+	members := map[time.Time][]int{}
+	for i, ts := range times {
+		t, err := time.Parse(HourFormat, ts)
+		if err != nil {
+			return nil, fmt.Errorf("parsing time %q: %w", ts, err)
+		}
+		start, ok := p.Start(t)
+		if !ok {
+			continue
+		}
+		if _, seen := members[start]; !seen {
+			starts = append(starts, start)
+		}
+		members[start] = append(members[start], i)
+	}
+	slices.SortFunc(starts, func(a, b time.Time) int { return a.Compare(b) })
+
+	buckets := make([]Bucket, len(starts))
+	values := make([]float64, 0, len(times))
+	for i, start := range starts {
+		idx := members[start]
+		b := Bucket{Start: start, Hours: len(idx), Values: make(map[string]float64, len(keys))}
+		for j, a := range aggregations {
+			values = values[:0]
+			for _, k := range idx {
+				values = append(values, series[j][k])
+			}
+			b.Values[keys[j]] = a.apply(values)
+		}
+		buckets[i] = b
+	}
+	return buckets, nil
+}
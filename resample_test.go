@@ -0,0 +1,263 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openmeteogo
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hourlyRange returns n hourly timestamps starting at start.
+func hourlyRange(start string, n int) []string {
+	t, _ := time.Parse(HourFormat, start)
+	times := make([]string, n)
+	for i := range times {
+		times[i] = t.Add(time.Duration(i) * time.Hour).Format(HourFormat)
+	}
+	return times
+}
+
+func date(s string) time.Time {
+	t, _ := time.Parse(HourFormat, s)
+	return t
+}
+
+// berlin returns the time s on the wall clock in Europe/Berlin.
+func berlin(s string) time.Time {
+	loc, _ := time.LoadLocation("Europe/Berlin")
+	t, _ := time.ParseInLocation(HourFormat, s, loc)
+	return t
+}
+
+func TestHourly_Values(t *testing.T) {
+	h := &Hourly{
+		Temperature2m:      []float64{1.5, 2.5},
+		RelativeHumidity2m: []int{80, 90},
+		PressureLevels:     map[int]*PressureLevel{850: {CloudCover: []int{10, 20}}},
+	}
+
+	got, err := h.Values(Temperature2m)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{1.5, 2.5}, got)
+
+	got, err = h.Values(RelativeHumidity2m)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{80, 90}, got)
+
+	got, err = h.Values("cloud_cover_850hPa")
+	require.NoError(t, err)
+	assert.Equal(t, []float64{10, 20}, got)
+
+	_, err = h.Values(Snowfall)
+	assert.Error(t, err, "not returned")
+	_, err = h.Values("cloud_cover_500hPa")
+	assert.Error(t, err, "level not returned")
+	_, err = h.Values("bogus")
+	assert.Error(t, err, "unknown metric")
+}
+
+func TestCurrent_Value(t *testing.T) {
+	c := &Current{Time: "2025-01-01T12:00", Temperature2m: 3.5, WeatherCode: 61}
+
+	got, err := c.Value(Temperature2m)
+	require.NoError(t, err)
+	assert.Equal(t, 3.5, got)
+
+	got, err = c.Value(WeatherCode)
+	require.NoError(t, err)
+	assert.Equal(t, 61.0, got)
+
+	_, err = c.Value("time")
+	assert.Error(t, err, "not numeric")
+	_, err = c.Value("bogus")
+	assert.Error(t, err, "unknown metric")
+}
+
+func TestDaily_Values(t *testing.T) {
+	d := &Daily{
+		Temperature2mMax:         []float64{20.5},
+		WindDirection10mDominant: []int{270},
+		Sunrise:                  []string{"2025-01-01T08:00"},
+	}
+
+	got, err := d.Values(Temperature2mMax)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{20.5}, got)
+
+	got, err = d.Values(WindDirection10mDominant)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{270}, got)
+
+	_, err = d.Values(Sunrise)
+	assert.Error(t, err, "not numeric")
+	_, err = d.Values(RainSum)
+	assert.Error(t, err, "not returned")
+}
+
+func TestPeriods(t *testing.T) {
+	tests := map[string]struct {
+		period Period
+		t      string
+		want   string
+		wantOK bool
+	}{
+		"day":                   {Days(0), "2025-03-05T17:00", "2025-03-05T00:00", true},
+		"business day after 6":  {Days(6 * time.Hour), "2025-03-05T06:00", "2025-03-05T06:00", true},
+		"business day before 6": {Days(6 * time.Hour), "2025-03-05T05:00", "2025-03-04T06:00", true},
+		"week from Monday":      {Weeks(time.Monday), "2025-03-09T23:00", "2025-03-03T00:00", true},
+		"week on Monday":        {Weeks(time.Monday), "2025-03-10T00:00", "2025-03-10T00:00", true},
+		"week from Sunday":      {Weeks(time.Sunday), "2025-03-08T12:00", "2025-03-02T00:00", true},
+		"three hours":           {Every(3*time.Hour, time.Time{}), "2025-03-05T17:00", "2025-03-05T15:00", true},
+		"three hours before":    {Every(3*time.Hour, date("2025-03-05T01:00")), "2025-03-04T23:00", "2025-03-04T22:00", true},
+		"inside window":         {Windows(Window{date("2025-03-05T08:00"), date("2025-03-05T18:00")}), "2025-03-05T17:00", "2025-03-05T08:00", true},
+		"at end of window":      {Windows(Window{date("2025-03-05T08:00"), date("2025-03-05T18:00")}), "2025-03-05T18:00", "0001-01-01T00:00", false},
+		"outside every window":  {Windows(), "2025-03-05T18:00", "0001-01-01T00:00", false},
+		"local window":          {Windows(Window{berlin("2026-07-01T06:00"), berlin("2026-07-01T08:00")}), "2026-07-01T07:00", "2026-07-01T06:00", true},
+		"after local window":    {Windows(Window{berlin("2026-07-01T06:00"), berlin("2026-07-01T08:00")}), "2026-07-01T08:00", "0001-01-01T00:00", false},
+		"local origin":          {Every(3*time.Hour, berlin("2026-07-01T07:00")), "2026-07-01T09:00", "2026-07-01T07:00", true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, ok := tc.period.Start(date(tc.t))
+			assert.Equal(t, tc.wantOK, ok)
+			assert.Equal(t, tc.want, got.Format(HourFormat))
+		})
+	}
+}
+
+func TestHourly_Resample(t *testing.T) {
+	h := &Hourly{
+		Time:          hourlyRange("2025-03-05T00:00", 48),
+		Temperature2m: make([]float64, 48),
+		CloudCover:    make([]int, 48),
+		Precipitation: make([]float64, 48),
+	}
+	for i := range h.Time {
+		h.Temperature2m[i] = float64(i)
+		h.CloudCover[i] = i % 10
+		h.Precipitation[i] = 0.5
+	}
+
+	got, err := h.Resample(Days(6*time.Hour),
+		Aggregate{Temperature2m, AggregateMean},
+		Aggregate{Temperature2m, AggregatePercentile(50)},
+		Aggregate{CloudCover, AggregateMax},
+		Aggregate{Precipitation, AggregateSum},
+		Aggregate{Temperature2m, AggregateMin},
+	)
+	require.NoError(t, err)
+	require.Len(t, got, 3)
+
+	assert.Equal(t, date("2025-03-04T06:00"), got[0].Start)
+	assert.Equal(t, 6, got[0].Hours)
+	assert.Equal(t, 2.5, got[0].Values["temperature_2m_mean"])
+	assert.Equal(t, 3.0, got[0].Values["precipitation_sum"])
+
+	assert.Equal(t, date("2025-03-05T06:00"), got[1].Start)
+	assert.Equal(t, 24, got[1].Hours)
+	assert.Equal(t, 6.0, got[1].Values["temperature_2m_min"])
+	assert.Equal(t, 17.5, got[1].Values["temperature_2m_p50"])
+	assert.Equal(t, 9.0, got[1].Values["cloud_cover_max"])
+	assert.Equal(t, 12.0, got[1].Values["precipitation_sum"])
+
+	assert.Equal(t, 18, got[2].Hours)
+}
+
+func TestHourly_Resample_LocalWindows(t *testing.T) {
+	// A response in Europe/Berlin, whose timestamps are local wall clock times.
+	h := &Hourly{Time: hourlyRange("2026-07-01T05:00", 4), Temperature2m: []float64{1, 2, 4, 8}}
+
+	start := berlin("2026-07-01T06:00")
+	got, err := h.Resample(Windows(Window{start, berlin("2026-07-01T08:00")}), Aggregate{Temperature2m, AggregateSum})
+	require.NoError(t, err)
+	require.Len(t, got, 1, "06:00 and 07:00 in Berlin")
+	assert.Equal(t, start, got[0].Start)
+	assert.Equal(t, 2, got[0].Hours)
+	assert.Equal(t, 6.0, got[0].Values["temperature_2m_sum"])
+}
+
+func TestHourly_Resample_VectorMean(t *testing.T) {
+	h := &Hourly{
+		Time:             hourlyRange("2025-03-03T00:00", 4),
+		WindDirection10m: []int{350, 10, 80, 100},
+	}
+
+	got, err := h.Resample(Every(2*time.Hour, time.Time{}), Aggregate{WindDirection10m, AggregateVectorMean})
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.InDelta(t, 0, got[0].Values["wind_direction_10m_vector_mean"], 1e-9)
+	assert.InDelta(t, 90, got[1].Values["wind_direction_10m_vector_mean"], 1e-9)
+}
+
+func TestHourly_Resample_Weeks(t *testing.T) {
+	wd := loadWeatherData(t, "test_data/all_params.json")
+
+	got, err := wd.Hourly.Resample(Weeks(time.Monday), Aggregate{Temperature2m, AggregateMax})
+	require.NoError(t, err)
+	hours := 0
+	for _, b := range got {
+		assert.Equal(t, time.Monday, b.Start.Weekday())
+		hours += b.Hours
+	}
+	assert.Equal(t, len(wd.Hourly.Time), hours)
+}
+
+func TestResampleSeries(t *testing.T) {
+	times := hourlyRange("2025-01-01T22:00", 4)
+	buckets, err := ResampleSeries(times, []float64{1, 3, 5, 7}, Days(0), AggregateMean)
+	require.NoError(t, err)
+	require.Len(t, buckets, 2)
+	assert.Equal(t, date("2025-01-01T00:00"), buckets[0].Start)
+	assert.Equal(t, 2, buckets[0].Hours)
+	assert.Equal(t, map[string]float64{"mean": 2}, buckets[0].Values)
+	assert.Equal(t, map[string]float64{"mean": 6}, buckets[1].Values)
+
+	_, err = ResampleSeries(times, []float64{1}, Days(0), AggregateSum)
+	assert.EqualError(t, err, "need 4 values, got 1")
+	_, err = ResampleSeries(times, []float64{1, 3, 5, 7}, Days(0), AggregatePercentile(101))
+	assert.EqualError(t, err, "percentile out of range: 101")
+}
+
+func TestHourly_Resample_Errors(t *testing.T) {
+	h := &Hourly{
+		Time:          hourlyRange("2025-03-05T00:00", 2),
+		Temperature2m: []float64{1, 2},
+		Rain:          []float64{1},
+	}
+
+	tests := map[string][]Aggregate{
+		"no aggregates":          nil,
+		"missing metric":         {{Snowfall, AggregateSum}},
+		"misaligned metric":      {{Rain, AggregateSum}},
+		"percentile too large":   {{Temperature2m, AggregatePercentile(101)}},
+		"zero value aggregation": {{Temperature2m, Aggregation{}}},
+	}
+
+	for name, aggregates := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := h.Resample(Days(0), aggregates...)
+			assert.Error(t, err)
+		})
+	}
+
+	h.Time[1] = "yesterday"
+	_, err := h.Resample(Days(0), Aggregate{Temperature2m, AggregateSum})
+	assert.Error(t, err, fmt.Sprintf("time %q", h.Time[1]))
+}
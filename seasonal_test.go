@@ -54,8 +54,8 @@ func TestURL_Seasonal(t *testing.T) {
 		},
 		"with api key": {
 			client:  NewClientWithKey("testkey"),
-			options: *NewOptionsBuilder().Models([]string{"modelA"}).Latitude(0).Longitude(0).Build(),
-			want:    "https://customer-seasonal-api.open-meteo.com/v1/seasonal?apikey=testkey&latitude=0&longitude=0&models=modelA",
+			options: *NewOptionsBuilder().Models([]string{"ecmwf_seas5"}).Latitude(0).Longitude(0).Build(),
+			want:    "https://customer-seasonal-api.open-meteo.com/v1/seasonal?apikey=testkey&latitude=0&longitude=0&models=ecmwf_seas5",
 		},
 	}
 
@@ -0,0 +1,358 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openmeteogo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+)
+
+const (
+	// defaultChunkSize is the number of rows in a HourlyChunk when
+	// StreamConfig.ChunkSize is not set, four weeks of hours.
+	defaultChunkSize = 672
+
+	// defaultWindow is the number of days fetched per request when
+	// StreamConfig.Window is not set.
+	defaultWindow = 31
+)
+
+// HourlyChunk is a run of consecutive rows of the hourly section of a
+// streamed response.
+type HourlyChunk struct {
+	// Data holds the rest of the response: the location, the units and
+	// the other sections. Its Hourly section is empty.
+	Data *WeatherData
+	// Metrics names the columns as in the response, e.g.
+	// "temperature_850hPa" or "temperature_2m_gfs_seamless".
+	Metrics Metrics
+	// Units holds the unit label of each column.
+	Units []string
+	// Time holds the timestamp of each row.
+	Time []string
+	// Values holds a series per column, in the order of Metrics. Nulls in
+	// the response are NaN.
+	Values [][]float64
+}
+
+// StreamConfig controls how StreamHourly fetches and splits the hourly
+// series.
+type StreamConfig struct {
+	// ChunkSize is the number of rows per chunk. The default is 672, four
+	// weeks of hours.
+	ChunkSize int
+	// Window is the number of days fetched per request when the Options set
+	// Start and End. Memory use is bounded by the size of one window. The
+	// default is 31 days.
+	Window int
+}
+
+// StreamHourly fetches the hourly series for o and passes it to fn in
+// chunks of rows, in time order, without building a WeatherData for the
+// whole response. It stops at the first error fn returns.
+//
+// The API sends each variable as a single array, so the rows of a response
+// are complete only once its last array has been read. StreamHourly keeps
+// the arrays of one response as float64 columns, without the raw JSON or a
+// WeatherData, and limits the response size for long archive ranges by
+// fetching StreamConfig.Window days at a time. Every window is fetched from
+// the API the whole range would be. StartHour, EndHour and PastDays cannot
+// be combined with a Start and End range, as they would apply to every
+// window.
+//
+// Sinks such as CSVHourlyWriter and export.HourlyWriter write each chunk
+// as it arrives. The FlatBuffers option is not supported.
+func (c *Client) StreamHourly(ctx context.Context, o *Options, cfg StreamConfig, fn func(*HourlyChunk) error) error {
+	if o.FlatBuffers {
+		return errors.New("streaming is not supported with FlatBuffers")
+	}
+	if cfg.ChunkSize <= 0 {
+		cfg.ChunkSize = defaultChunkSize
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = defaultWindow
+	}
+
+	u, err := url.Parse(c.url(o))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	windows := [][2]time.Time{{o.Start, o.End}}
+	if !o.Start.IsZero() && !o.End.IsZero() {
+		if !o.StartHour.IsZero() || !o.EndHour.IsZero() || o.PastDays != 0 {
+			return errors.New("start hour, end hour and past days cannot be streamed with a start and end date")
+		}
+		windows = nil
+		for start := o.Start; !start.After(o.End); {
+			end := start.AddDate(0, 0, cfg.Window-1)
+			if end.After(o.End) {
+				end = o.End
+			}
+			windows = append(windows, [2]time.Time{start, end})
+			start = end.AddDate(0, 0, 1)
+		}
+	}
+
+	local := o.localUnits()
+	var converted *WeatherData
+	for _, w := range windows {
+		q := u.Query()
+		if !w[0].IsZero() {
+			q.Set("start_date", w[0].Format(DateFormat))
+		}
+		if !w[1].IsZero() {
+			q.Set("end_date", w[1].Format(DateFormat))
+		}
+		u.RawQuery = q.Encode()
+
+		err := c.stream(ctx, u.String(), cfg.ChunkSize, func(chunk *HourlyChunk) error {
+			if local != (Units{}) {
+				if chunk.Data != converted {
+					if err := chunk.Data.ConvertTo(local); err != nil {
+						return fmt.Errorf("converting units: %w", err)
+					}
+					converted = chunk.Data
+				}
+				if err := chunk.convert(local); err != nil {
+					return fmt.Errorf("converting units: %w", err)
+				}
+			}
+			return fn(chunk)
+		})
+		if err != nil {
+			if len(windows) > 1 {
+				return fmt.Errorf("%s to %s: %w", w[0].Format(DateFormat), w[1].Format(DateFormat), err)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// stream fetches a single response and decodes it with DecodeHourlyStream.
+func (c *Client) stream(ctx context.Context, u string, size int, fn func(*HourlyChunk) error) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", c.UserAgent)
+
+	res, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("sending request: %w", err)
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("server http error: %d", res.StatusCode)
+	}
+
+	return DecodeHourlyStream(res.Body, size, fn)
+}
+
+// DecodeHourlyStream reads a JSON response from r, such as a stored
+// response, and passes its hourly series to fn in chunks of size rows, as
+// StreamHourly does. The chunks are passed once the whole response has been
+// read. It returns an error if the response has no hourly data.
+func DecodeHourlyStream(r io.Reader, size int, fn func(*HourlyChunk) error) error {
+	if size <= 0 {
+		size = defaultChunkSize
+	}
+
+	dec := json.NewDecoder(r)
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+
+	var names []string
+	var times []string
+	var columns [][]float64
+	seen := false
+	rest := map[string]json.RawMessage{}
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		name, _ := key.(string)
+		if name != "hourly" {
+			var raw json.RawMessage
+			if err := dec.Decode(&raw); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			rest[name] = raw
+			continue
+		}
+		if names, times, columns, err = decodeHourlyColumns(dec); err != nil {
+			return fmt.Errorf("hourly: %w", err)
+		}
+		seen = true
+	}
+	if err := expectDelim(dec, '}'); err != nil {
+		return err
+	}
+	if !seen || times == nil {
+		return errors.New("no hourly data")
+	}
+
+	buf, err := json.Marshal(rest)
+	if err != nil {
+		return err
+	}
+	var wd WeatherData
+	if err := json.Unmarshal(buf, &wd); err != nil {
+		return err
+	}
+	labels := map[string]string{}
+	if raw, ok := rest["hourly_units"]; ok {
+		if err := json.Unmarshal(raw, &labels); err != nil {
+			return fmt.Errorf("hourly_units: %w", err)
+		}
+	}
+
+	metrics := make(Metrics, len(names))
+	units := make([]string, len(names))
+	for i, name := range names {
+		metrics[i], units[i] = Metric(name), labels[name]
+		if len(columns[i]) != len(times) {
+			return fmt.Errorf("hourly: %s has %d values for %d times", name, len(columns[i]), len(times))
+		}
+	}
+
+	for start := 0; start < len(times); start += size {
+		end := min(start+size, len(times))
+		chunk := &HourlyChunk{
+			Data:    &wd,
+			Metrics: metrics,
+			Units:   units,
+			Time:    times[start:end],
+			Values:  make([][]float64, len(columns)),
+		}
+		for i, c := range columns {
+			chunk.Values[i] = c[start:end]
+		}
+		if err := fn(chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeHourlyColumns reads the hourly object token by token into the time
+// array and a float64 column per variable, in the order of the response.
+func decodeHourlyColumns(dec *json.Decoder) (names, times []string, columns [][]float64, err error) {
+	if err := expectDelim(dec, '{'); err != nil {
+		return nil, nil, nil, err
+	}
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		name, _ := key.(string)
+		if err := expectDelim(dec, '['); err != nil {
+			return nil, nil, nil, fmt.Errorf("%s: %w", name, err)
+		}
+		var column []float64
+		if name == "time" {
+			times = []string{}
+		}
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return nil, nil, nil, fmt.Errorf("%s: %w", name, err)
+			}
+			switch v := tok.(type) {
+			case string:
+				if name != "time" {
+					return nil, nil, nil, fmt.Errorf("%s: unexpected string %q", name, v)
+				}
+				times = append(times, v)
+			case float64:
+				if name == "time" {
+					return nil, nil, nil, fmt.Errorf("time: unexpected number %v", v)
+				}
+				column = append(column, v)
+			case nil:
+				column = append(column, math.NaN())
+			default:
+				return nil, nil, nil, fmt.Errorf("%s: unexpected %v", name, v)
+			}
+		}
+		if err := expectDelim(dec, ']'); err != nil {
+			return nil, nil, nil, fmt.Errorf("%s: %w", name, err)
+		}
+		if name != "time" {
+			names = append(names, name)
+			columns = append(columns, column)
+		}
+	}
+	if err := expectDelim(dec, '}'); err != nil {
+		return nil, nil, nil, err
+	}
+	return names, times, columns, nil
+}
+
+// expectDelim reads the next token, which must be the delimiter d.
+func expectDelim(dec *json.Decoder, d json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != d {
+		return fmt.Errorf("expected %v, got %v", d, tok)
+	}
+	return nil
+}
+
+// convert converts the columns of the chunk to the given units, as
+// WeatherData.ConvertTo does, and updates their unit labels. The labels are
+// copied first, as the chunks of a response share them.
+func (c *HourlyChunk) convert(u Units) error {
+	c.Units = slices.Clone(c.Units)
+	for i, m := range c.Metrics {
+		name := string(m)
+		if variable, _, ok := parsePressureLevelMetric(name); ok {
+			name = string(variable)
+		}
+		to, convert, err := u.conversion(name, c.Units[i])
+		if err != nil {
+			return err
+		}
+		if convert == nil {
+			continue
+		}
+		for j, v := range c.Values[i] {
+			if math.IsNaN(v) {
+				continue
+			}
+			if c.Values[i][j], err = convert(v); err != nil {
+				return fmt.Errorf("%s: %w", m, err)
+			}
+		}
+		c.Units[i] = to.String()
+	}
+	return nil
+}
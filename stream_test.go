@@ -0,0 +1,262 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openmeteogo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const streamResponse = `{
+	"latitude": 52.52, "longitude": 13.419998, "elevation": 38, "utc_offset_seconds": 3600,
+	"timezone": "Europe/Berlin", "timezone_abbreviation": "GMT+1",
+	"hourly_units": {"time": "iso8601", "temperature_2m": "°C", "temperature_850hPa": "°C"},
+	"hourly": {"time": ["2025-01-01T00:00", "2025-01-01T01:00", "2025-01-01T02:00"],
+		"temperature_2m": [1.5, null, 2], "temperature_850hPa": [-4.2, -4.5, -4.7]},
+	"daily_units": {"time": "iso8601", "temperature_2m_max": "°C"},
+	"daily": {"time": ["2025-01-01"], "temperature_2m_max": [4.2]}
+}`
+
+// collect returns a function that appends the chunks it is passed.
+func collect(chunks *[]*HourlyChunk) func(*HourlyChunk) error {
+	return func(c *HourlyChunk) error {
+		*chunks = append(*chunks, c)
+		return nil
+	}
+}
+
+func TestDecodeHourlyStream(t *testing.T) {
+	var chunks []*HourlyChunk
+	require.NoError(t, DecodeHourlyStream(strings.NewReader(streamResponse), 2, collect(&chunks)))
+	require.Len(t, chunks, 2)
+
+	first, second := chunks[0], chunks[1]
+	assert.Equal(t, Metrics{Temperature2m, "temperature_850hPa"}, first.Metrics)
+	assert.Equal(t, []string{"°C", "°C"}, first.Units)
+	assert.Equal(t, []string{"2025-01-01T00:00", "2025-01-01T01:00"}, first.Time)
+	assert.Equal(t, 1.5, first.Values[0][0])
+	assert.True(t, math.IsNaN(first.Values[0][1]), "nulls are NaN")
+	assert.Equal(t, []float64{-4.2, -4.5}, first.Values[1])
+	assert.Equal(t, []string{"2025-01-01T02:00"}, second.Time)
+	assert.Equal(t, [][]float64{{2}, {-4.7}}, second.Values)
+
+	assert.Equal(t, "Europe/Berlin", first.Data.Timezone)
+	assert.Equal(t, []float64{4.2}, first.Data.Daily.Temperature2mMax)
+	assert.Equal(t, "°C", first.Data.HourlyUnits.Temperature2m)
+	assert.Empty(t, first.Data.Hourly.Time)
+}
+
+func TestDecodeHourlyStream_Errors(t *testing.T) {
+	stop := errors.New("stop")
+	tests := map[string]struct {
+		input   string
+		fn      func(*HourlyChunk) error
+		wantErr string
+	}{
+		"not an object": {
+			input:   `[1, 2]`,
+			wantErr: "expected {, got [",
+		},
+		"no hourly data": {
+			input:   `{"latitude": 52.52, "daily": {"time": ["2025-01-01"]}}`,
+			wantErr: "no hourly data",
+		},
+		"length mismatch": {
+			input:   `{"hourly": {"time": ["2025-01-01T00:00", "2025-01-01T01:00"], "temperature_2m": [1.5]}}`,
+			wantErr: "hourly: temperature_2m has 1 values for 2 times",
+		},
+		"string value": {
+			input:   `{"hourly": {"time": ["2025-01-01T00:00"], "temperature_2m": ["warm"]}}`,
+			wantErr: `hourly: temperature_2m: unexpected string "warm"`,
+		},
+		"numeric time": {
+			input:   `{"hourly": {"time": [1735689600], "temperature_2m": [1.5]}}`,
+			wantErr: "hourly: time: unexpected number",
+		},
+		"truncated": {
+			input:   `{"hourly": {"time": ["2025-01-01T00:00"], "temperature_2m": [1.5`,
+			wantErr: "hourly: temperature_2m: unexpected end of JSON input",
+		},
+		"callback error": {
+			input:   streamResponse,
+			fn:      func(*HourlyChunk) error { return stop },
+			wantErr: "stop",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			fn := tc.fn
+			if fn == nil {
+				fn = func(*HourlyChunk) error { return nil }
+			}
+			err := DecodeHourlyStream(strings.NewReader(tc.input), 2, fn)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.wantErr)
+		})
+	}
+}
+
+// streamClient returns a client for a server that answers with two hourly
+// pressure_msl values for each day requested, and records the path and date
+// range of each request in requests.
+func streamClient(t *testing.T, requests *[]string) *Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		q := req.URL.Query()
+		*requests = append(*requests, req.URL.Path+" "+q.Get("start_date")+" "+q.Get("end_date"))
+		start, err := time.Parse(DateFormat, q.Get("start_date"))
+		require.NoError(t, err)
+		end, err := time.Parse(DateFormat, q.Get("end_date"))
+		require.NoError(t, err)
+
+		var times []string
+		var pressure []float64
+		for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+			times = append(times, d.Format(DateFormat)+"T00:00", d.Format(DateFormat)+"T12:00")
+			pressure = append(pressure, 1000, 1010)
+		}
+		json.NewEncoder(rw).Encode(map[string]any{
+			"timezone":     "GMT",
+			"hourly_units": map[string]string{"time": "iso8601", "pressure_msl": "hPa"},
+			"hourly":       map[string]any{"time": times, "pressure_msl": pressure},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	// Archive requests go to another host, so every host dials the server.
+	transport := server.Client().Transport.(*http.Transport).Clone()
+	transport.DialContext = func(ctx context.Context, network, _ string) (net.Conn, error) {
+		return (&net.Dialer{}).DialContext(ctx, network, server.Listener.Addr().String())
+	}
+
+	client := NewClient()
+	client.HTTPClient = &http.Client{Transport: transport}
+	urlParts := strings.Split(server.URL, "://")
+	client.scheme = urlParts[0]
+	client.host = urlParts[1]
+	return client
+}
+
+func TestClient_StreamHourly(t *testing.T) {
+	var requests []string
+	client := streamClient(t, &requests)
+
+	start := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -4)
+	opts := NewOptionsBuilder().
+		Start(start).
+		End(start.AddDate(0, 0, 4)).
+		HourlyMetrics(Metrics{PressureMsl}).
+		PressureUnit(KPA).
+		Build()
+
+	var chunks []*HourlyChunk
+	require.NoError(t, client.StreamHourly(context.Background(), opts, StreamConfig{ChunkSize: 3, Window: 2}, collect(&chunks)))
+
+	day := func(n int) string { return start.AddDate(0, 0, n).Format(DateFormat) }
+	assert.Equal(t, []string{
+		"/v1/forecast " + day(0) + " " + day(1),
+		"/v1/forecast " + day(2) + " " + day(3),
+		"/v1/forecast " + day(4) + " " + day(4),
+	}, requests, "the range is fetched in windows of two days")
+
+	var times []string
+	for _, c := range chunks {
+		times = append(times, c.Time...)
+		assert.Equal(t, []string{"kPa"}, c.Units, "every chunk is converted")
+		for _, v := range c.Values[0] {
+			assert.Contains(t, []float64{100, 101}, v)
+		}
+	}
+	assert.Len(t, chunks, 5, "two chunks for each full window and one for the last")
+	assert.Len(t, times, 10)
+	assert.Equal(t, day(0)+"T00:00", times[0])
+	assert.Equal(t, day(4)+"T12:00", times[9])
+
+}
+
+func TestClient_StreamHourly_DefaultWindow(t *testing.T) {
+	var requests []string
+	client := streamClient(t, &requests)
+
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	opts := NewOptionsBuilder().
+		Start(start).
+		End(time.Date(2020, 12, 31, 0, 0, 0, 0, time.UTC)).
+		HourlyMetrics(Metrics{PressureMsl}).
+		Build()
+
+	rows, largest := 0, 0
+	require.NoError(t, client.StreamHourly(context.Background(), opts, StreamConfig{}, func(c *HourlyChunk) error {
+		rows += len(c.Time)
+		largest = max(largest, len(c.Time))
+		return nil
+	}))
+	assert.Len(t, requests, 12, "a year is fetched in windows of 31 days")
+	assert.Equal(t, "/v1/archive 2020-01-01 2020-01-31", requests[0])
+	assert.Equal(t, "/v1/archive 2020-12-07 2020-12-31", requests[11])
+	assert.Equal(t, 2*366, rows)
+	assert.Equal(t, 2*31, largest, "no chunk holds more than one window")
+}
+
+func TestClient_StreamHourly_Errors(t *testing.T) {
+	var requests []string
+	client := streamClient(t, &requests)
+
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	ranged := func() *OptionsBuilder {
+		return NewOptionsBuilder().Start(start).End(start.AddDate(0, 1, 0)).HourlyMetrics(Metrics{PressureMsl})
+	}
+	tests := map[string]struct {
+		opts    *Options
+		wantErr string
+	}{
+		"flatbuffers": {
+			opts:    NewOptionsBuilder().HourlyMetrics(Metrics{PressureMsl}).FlatBuffers(true).Build(),
+			wantErr: "streaming is not supported with FlatBuffers",
+		},
+		"start hour": {
+			opts:    ranged().StartHour(start.Add(6 * time.Hour)).Build(),
+			wantErr: "start hour, end hour and past days cannot be streamed with a start and end date",
+		},
+		"end hour": {
+			opts:    ranged().EndHour(start.Add(18 * time.Hour)).Build(),
+			wantErr: "start hour, end hour and past days cannot be streamed with a start and end date",
+		},
+		"past days": {
+			opts:    ranged().PastDays(3).Build(),
+			wantErr: "start hour, end hour and past days cannot be streamed with a start and end date",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := client.StreamHourly(context.Background(), tc.opts, StreamConfig{}, func(*HourlyChunk) error { return nil })
+			assert.EqualError(t, err, tc.wantErr)
+		})
+	}
+	assert.Empty(t, requests, "nothing is fetched")
+}
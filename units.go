@@ -14,6 +14,12 @@
 
 package openmeteogo
 
+import (
+	"fmt"
+	"math"
+	"reflect"
+)
+
 // TemperatureUnit defines the unit for temperature values.
 type TemperatureUnit string
 
@@ -36,6 +42,9 @@ const (
 	MPH WindSpeedUnit = "mph"
 	// KN is knots.
 	KN WindSpeedUnit = "kn"
+	// BFT is the Beaufort wind force scale. The API does not support it, so
+	// values are fetched in km/h and converted locally.
+	BFT WindSpeedUnit = "bft"
 )
 
 // PrecipitationUnit defines the unit for precipitation values.
@@ -47,3 +56,453 @@ const (
 	// IN is inches.
 	IN PrecipitationUnit = "inch"
 )
+
+// LengthUnit defines the unit system for marine lengths such as wave heights.
+type LengthUnit string
+
+const (
+	// LengthMetric is the default length unit system (meters).
+	LengthMetric LengthUnit = "metric"
+	// LengthImperial reports lengths in feet.
+	LengthImperial LengthUnit = "imperial"
+)
+
+// PressureUnit defines the unit for atmospheric pressure values. The API only
+// returns hPa, so other units are converted locally.
+type PressureUnit string
+
+const (
+	// HPA is the default pressure unit (hectopascals).
+	HPA PressureUnit = "hPa"
+	// KPA is kilopascals.
+	KPA PressureUnit = "kPa"
+	// INHG is inches of mercury.
+	INHG PressureUnit = "inHg"
+)
+
+// VisibilityUnit defines the unit for visibility values. Units other than
+// those returned by the API are converted locally.
+type VisibilityUnit string
+
+const (
+	// Meters is the default visibility unit.
+	Meters VisibilityUnit = "m"
+	// Kilometers is kilometers.
+	Kilometers VisibilityUnit = "km"
+	// Feet is feet.
+	Feet VisibilityUnit = "ft"
+	// Miles is statute miles.
+	Miles VisibilityUnit = "mi"
+)
+
+// Unit is a physical unit as reported by the API in the *Units structs.
+// Its value is the label the API uses, e.g. "°C" or "km/h".
+type Unit string
+
+const (
+	UnitDimensionless           Unit = ""
+	UnitCelsius                 Unit = "°C"
+	UnitFahrenheit              Unit = "°F"
+	UnitKmh                     Unit = "km/h"
+	UnitMs                      Unit = "m/s"
+	UnitMph                     Unit = "mp/h"
+	UnitKnots                   Unit = "kn"
+	UnitBeaufort                Unit = "bft"
+	UnitMillimetre              Unit = "mm"
+	UnitCentimetre              Unit = "cm"
+	UnitInch                    Unit = "inch"
+	UnitMetre                   Unit = "m"
+	UnitFoot                    Unit = "ft"
+	UnitKilometre               Unit = "km"
+	UnitMile                    Unit = "mi"
+	UnitHectopascal             Unit = "hPa"
+	UnitKilopascal              Unit = "kPa"
+	UnitInchMercury             Unit = "inHg"
+	UnitPercent                 Unit = "%"
+	UnitCubicMetrePerCubicMetre Unit = "m³/m³"
+	UnitDegree                  Unit = "°"
+	UnitSecond                  Unit = "s"
+	UnitHour                    Unit = "h"
+	UnitWattPerSquareMetre      Unit = "W/m²"
+	UnitMegajoulePerSquareMetre Unit = "MJ/m²"
+	UnitJoulePerKilogram        Unit = "J/kg"
+	UnitGramPerCubicMetre       Unit = "g/m³"
+	UnitWMOCode                 Unit = "wmo code"
+	UnitISO8601                 Unit = "iso8601"
+	UnitUnixTime                Unit = "unixtime"
+)
+
+// Dimension is the physical dimension measured by a Unit or Metric.
+type Dimension string
+
+const (
+	DimensionNone        Dimension = "none"
+	DimensionTemperature Dimension = "temperature"
+	// DimensionTemperatureDifference is a difference between temperatures,
+	// such as an anomaly. Its values take temperature units but no offset.
+	DimensionTemperatureDifference Dimension = "temperature_difference"
+	DimensionSpeed                 Dimension = "speed"
+	DimensionLength                Dimension = "length"
+	DimensionPressure              Dimension = "pressure"
+	DimensionRatio                 Dimension = "ratio"
+	DimensionAngle                 Dimension = "angle"
+	DimensionDuration              Dimension = "duration"
+	DimensionIrradiance            Dimension = "irradiance"
+	DimensionRadiantExposure       Dimension = "radiant_exposure"
+	DimensionSpecificEnergy        Dimension = "specific_energy"
+	DimensionDensity               Dimension = "density"
+	DimensionWeatherCode           Dimension = "weather_code"
+	DimensionTime                  Dimension = "time"
+)
+
+// unitInfo describes a Unit: its dimension and, for units that can be
+// converted, how to take a value to the base unit of its dimension
+// (°C, m/s, m, Pa, ratio or s) as (v + shift) * num / den. Keeping the
+// factor as a fraction keeps round trips such as 100°C to °F exact.
+type unitInfo struct {
+	dimension Dimension
+	shift     float64
+	num, den  float64
+}
+
+var unitInfos = map[Unit]unitInfo{
+	UnitDimensionless:           {dimension: DimensionNone},
+	UnitCelsius:                 {DimensionTemperature, 0, 1, 1},
+	UnitFahrenheit:              {DimensionTemperature, -32, 5, 9},
+	UnitKmh:                     {DimensionSpeed, 0, 1000, 3600},
+	UnitMs:                      {DimensionSpeed, 0, 1, 1},
+	UnitMph:                     {DimensionSpeed, 0, 1609.344, 3600},
+	UnitKnots:                   {DimensionSpeed, 0, 1852, 3600},
+	UnitBeaufort:                {dimension: DimensionSpeed},
+	UnitMillimetre:              {DimensionLength, 0, 1, 1000},
+	UnitCentimetre:              {DimensionLength, 0, 1, 100},
+	UnitInch:                    {DimensionLength, 0, 254, 10000},
+	UnitMetre:                   {DimensionLength, 0, 1, 1},
+	UnitFoot:                    {DimensionLength, 0, 3048, 10000},
+	UnitKilometre:               {DimensionLength, 0, 1000, 1},
+	UnitMile:                    {DimensionLength, 0, 1609.344, 1},
+	UnitHectopascal:             {DimensionPressure, 0, 100, 1},
+	UnitKilopascal:              {DimensionPressure, 0, 1000, 1},
+	UnitInchMercury:             {DimensionPressure, 0, 3386.389, 1},
+	UnitPercent:                 {DimensionRatio, 0, 1, 100},
+	UnitCubicMetrePerCubicMetre: {DimensionRatio, 0, 1, 1},
+	UnitDegree:                  {dimension: DimensionAngle},
+	UnitSecond:                  {DimensionDuration, 0, 1, 1},
+	UnitHour:                    {DimensionDuration, 0, 3600, 1},
+	UnitWattPerSquareMetre:      {dimension: DimensionIrradiance},
+	UnitMegajoulePerSquareMetre: {dimension: DimensionRadiantExposure},
+	UnitJoulePerKilogram:        {dimension: DimensionSpecificEnergy},
+	UnitGramPerCubicMetre:       {dimension: DimensionDensity},
+	UnitWMOCode:                 {dimension: DimensionWeatherCode},
+	UnitISO8601:                 {dimension: DimensionTime},
+	UnitUnixTime:                {dimension: DimensionTime},
+}
+
+// unitAliases maps alternative spellings seen in responses to their Unit.
+var unitAliases = map[string]Unit{
+	"mph":     UnitMph,
+	"seconds": UnitSecond,
+}
+
+// ParseUnit returns the Unit for a label from a *Units struct. It returns an
+// error for labels it does not recognise, which usually means the API has
+// added or changed a unit.
+func ParseUnit(label string) (Unit, error) {
+	if u, ok := unitAliases[label]; ok {
+		return u, nil
+	}
+	if _, ok := unitInfos[Unit(label)]; !ok {
+		return "", fmt.Errorf("unrecognised unit: %q", label)
+	}
+	return Unit(label), nil
+}
+
+// String returns the label the API uses for the unit.
+func (u Unit) String() string {
+	return string(u)
+}
+
+// Dimension returns the physical dimension measured by the unit.
+func (u Unit) Dimension() Dimension {
+	if info, ok := unitInfos[u]; ok {
+		return info.dimension
+	}
+	return DimensionNone
+}
+
+// beaufortLimits holds the upper wind speed limit in m/s of each Beaufort
+// force from 0 to 11. Anything faster is force 12.
+var beaufortLimits = []float64{0.5, 1.5, 3.3, 5.5, 7.9, 10.7, 13.8, 17.1, 20.7, 24.4, 28.4, 32.6}
+
+// toBeaufort returns the Beaufort force for a wind speed in m/s.
+func toBeaufort(ms float64) float64 {
+	for force, limit := range beaufortLimits {
+		if ms < limit {
+			return float64(force)
+		}
+	}
+	return 12
+}
+
+// fromBeaufort returns a representative wind speed in m/s for a Beaufort force.
+func fromBeaufort(force float64) float64 {
+	return 0.836 * math.Pow(force, 1.5)
+}
+
+// Convert converts a value from one unit to another of the same dimension.
+// Conversions to Beaufort yield whole forces, so they cannot be reversed exactly.
+// Use Metric.Convert for values that may be differences, such as anomalies.
+func Convert(v float64, from, to Unit) (float64, error) {
+	if from == to {
+		return v, nil
+	}
+	if from == UnitBeaufort || to == UnitBeaufort {
+		if from.Dimension() != DimensionSpeed || to.Dimension() != DimensionSpeed {
+			return 0, fmt.Errorf("cannot convert %q to %q", from, to)
+		}
+		if from == UnitBeaufort {
+			return Convert(fromBeaufort(v), UnitMs, to)
+		}
+		ms, err := Convert(v, from, UnitMs)
+		if err != nil {
+			return 0, err
+		}
+		return toBeaufort(ms), nil
+	}
+	fi, fok := unitInfos[from]
+	ti, tok := unitInfos[to]
+	if !fok || !tok || fi.dimension != ti.dimension || fi.num == 0 || ti.num == 0 {
+		return 0, fmt.Errorf("cannot convert %q to %q", from, to)
+	}
+	base := (v + fi.shift) * fi.num / fi.den
+	return base*ti.den/ti.num - ti.shift, nil
+}
+
+// ConvertDifference converts a difference between two values, such as a
+// temperature anomaly, from one unit to another of the same dimension. Only
+// the scale of the units applies, so a difference of 2°C is 3.6°F.
+func ConvertDifference(v float64, from, to Unit) (float64, error) {
+	if from == to {
+		return v, nil
+	}
+	fi, fok := unitInfos[from]
+	ti, tok := unitInfos[to]
+	if !fok || !tok || fi.dimension != ti.dimension || fi.num == 0 || ti.num == 0 {
+		return 0, fmt.Errorf("cannot convert %q to %q", from, to)
+	}
+	return v * fi.num / fi.den * ti.den / ti.num, nil
+}
+
+// metricDimensions maps each Metric to the physical dimension of its values.
+var metricDimensions = map[Metric]Dimension{}
+
+func init() {
+	groups := map[Dimension][]Metric{
+		DimensionTemperature: {
+			Temperature2m, DewPoint2m, ApparentTemperature, Temperature80m,
+			Temperature120m, Temperature180m, SoilTemperature0cm, SoilTemperature6cm,
+			SoilTemperature18cm, SoilTemperature54cm, Temperature2mMax, Temperature2mMin,
+			ApparentTemperatureMax, ApparentTemperatureMin, Temperature2mMean,
+			Temperature2mMaxMean, Temperature2mMinMean, DewPoint2mMean,
+			SeaSurfaceTemperature,
+		},
+		DimensionTemperatureDifference: {Temperature2mAnomaly},
+		DimensionSpeed: {
+			WindSpeed10m, WindSpeed80m, WindSpeed120m, WindSpeed180m, WindGusts10m,
+			WindSpeed10mMax, WindGusts10mMax, OceanCurrentVelocity,
+		},
+		DimensionLength: {
+			Precipitation, Rain, Showers, Snowfall, SnowDepth, Evapotranspiration,
+			Visibility, Et0FaoEvapotranspiration, RainSum, ShowersSum, SnowfallSum,
+			PrecipitationSum, SnowfallHeight, FreezingLevelHeight, PrecipitationMean,
+			PrecipitationAnomaly, WaveHeight, WindWaveHeight, SwellWaveHeight,
+			SecondarySwellWaveHeight, TertiarySwellWaveHeight, SeaLevelHeight,
+			WaveHeightMax, WindWaveHeightMax, SwellWaveHeightMax,
+		},
+		DimensionPressure: {
+			PressureMsl, SurfacePressure, VapourPressureDeficit, PressureMslMean,
+			PressureMslAnomaly,
+		},
+		DimensionRatio: {
+			RelativeHumidity2m, PrecipitationProbability, CloudCover, CloudCoverLow,
+			CloudCoverMid, CloudCoverHigh, SoilMoisture0To1cm, SoilMoisture1To3cm,
+			SoilMoisture9To27cm, SoilMoisture3To9cm, PrecipitationProbabilityMax,
+			SoilMoisture0To10cmMean, SoilMoisture0To10cmAnomaly,
+		},
+		DimensionAngle: {
+			WindDirection10m, WindDirection80m, WindDirection120m, WindDirection180m,
+			WindDirection10mDominant, WaveDirection, WindWaveDirection,
+			SwellWaveDirection, SecondarySwellWaveDirection, TertiarySwellWaveDirection,
+			OceanCurrentDirection, WaveDirectionDominant, WindWaveDirectionDominant,
+			SwellWaveDirectionDominant,
+		},
+		DimensionDuration: {
+			SunshineDuration, DaylightDuration, PrecipitationHours, WavePeriod,
+			WavePeakPeriod, WindWavePeriod, WindWavePeakPeriod, SwellWavePeriod,
+			SwellWavePeakPeriod, SecondarySwellWavePeriod, TertiarySwellWavePeriod,
+			WavePeriodMax, WindWavePeriodMax, WindWavePeakPeriodMax,
+			SwellWavePeriodMax, SwellWavePeakPeriodMax,
+		},
+		DimensionIrradiance: {
+			ShortwaveRadiation, DirectRadiation, DiffuseRadiation,
+			DirectNormalIrradiance, GlobalTiltedIrradiance, TerrestrialRadiation,
+			ShortwaveRadiationInstant, DirectRadiationInstant, DiffuseRadiationInstant,
+			DirectNormalIrradianceInstant, GlobalTiltedIrradianceInstant,
+			TerrestrialRadiationInstant,
+		},
+		DimensionRadiantExposure: {ShortwaveRadiationSum},
+		DimensionSpecificEnergy:  {LightningPotential},
+		DimensionWeatherCode:     {WeatherCode},
+		DimensionTime:            {Sunrise, Sunset},
+		DimensionNone:            {IsDay, UvIndexMax, UvIndexClearSkyMax},
+	}
+	for dim, metrics := range groups {
+		for _, m := range metrics {
+			metricDimensions[m] = dim
+		}
+	}
+}
+
+// pressureDimensions maps pressure level variables to their dimension.
+var pressureDimensions = map[PressureVariable]Dimension{
+	PressureTemperature:        DimensionTemperature,
+	PressureRelativeHumidity:   DimensionRatio,
+	PressureDewPoint:           DimensionTemperature,
+	PressureCloudCover:         DimensionRatio,
+	PressureWindSpeed:          DimensionSpeed,
+	PressureWindDirection:      DimensionAngle,
+	PressureGeopotentialHeight: DimensionLength,
+}
+
+// Dimension returns the physical dimension of the metric's values, or false
+// if the metric is unknown.
+func (m Metric) Dimension() (Dimension, bool) {
+	if dim, ok := metricDimensions[m]; ok {
+		return dim, true
+	}
+	if variable, _, ok := parsePressureLevelMetric(string(m)); ok {
+		return pressureDimensions[variable], true
+	}
+	return "", false
+}
+
+// Convert converts a value of the metric from one unit to another. Values of
+// DimensionTemperatureDifference metrics are converted with
+// ConvertDifference, so no offset is applied to them.
+func (m Metric) Convert(v float64, from, to Unit) (float64, error) {
+	if dim, _ := m.Dimension(); dim == DimensionTemperatureDifference {
+		return ConvertDifference(v, from, to)
+	}
+	return Convert(v, from, to)
+}
+
+// parseUnits parses every non-empty label of a *Units struct into a Unit,
+// keyed by metric. The time label is included under the "time" key.
+func parseUnits(units any) (map[Metric]Unit, error) {
+	v := reflect.ValueOf(units)
+	result := map[Metric]Unit{}
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		if field.Kind() != reflect.String || field.String() == "" {
+			continue
+		}
+		name := jsonName(v.Type().Field(i))
+		u, err := ParseUnit(field.String())
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		result[Metric(name)] = u
+	}
+	return result, nil
+}
+
+// parseUnit parses the label of the field of a *Units struct with the given
+// JSON name. An empty label is UnitDimensionless.
+func parseUnit(units any, name string) (Unit, error) {
+	v := reflect.ValueOf(units)
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		if field.Kind() != reflect.String || jsonName(v.Type().Field(i)) != name {
+			continue
+		}
+		u, err := ParseUnit(field.String())
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", name, err)
+		}
+		return u, nil
+	}
+	return "", fmt.Errorf("unknown metric: %s", name)
+}
+
+// Units parses the unit labels into typed Units, keyed by metric.
+func (u CurrentUnits) Units() (map[Metric]Unit, error) { return parseUnits(u) }
+
+// Unit parses the unit label of a single metric. Unlike Units, it ignores
+// the labels of other metrics.
+func (u CurrentUnits) Unit(m Metric) (Unit, error) { return parseUnit(u, string(m)) }
+
+// Units parses the unit labels into typed Units, keyed by metric.
+func (u Minutely15Units) Units() (map[Metric]Unit, error) { return parseUnits(u) }
+
+// Unit parses the unit label of a single metric. Unlike Units, it ignores
+// the labels of other metrics.
+func (u Minutely15Units) Unit(m Metric) (Unit, error) { return parseUnit(u, string(m)) }
+
+// Units parses the unit labels into typed Units, keyed by metric. Pressure
+// level labels are included under their full metric name, e.g.
+// "temperature_850hPa".
+func (u HourlyUnits) Units() (map[Metric]Unit, error) {
+	result, err := parseUnits(u)
+	if err != nil {
+		return nil, err
+	}
+	for level, lu := range u.PressureLevels {
+		levelUnits, err := parseUnits(*lu)
+		if err != nil {
+			return nil, fmt.Errorf("%dhPa: %w", level, err)
+		}
+		for name, unit := range levelUnits {
+			result[Metric(fmt.Sprintf("%s_%dhPa", name, level))] = unit
+		}
+	}
+	return result, nil
+}
+
+// Unit parses the unit label of a single metric, which may be a pressure
+// level metric. Unlike Units, it ignores the labels of other metrics.
+func (u HourlyUnits) Unit(m Metric) (Unit, error) {
+	variable, level, ok := parsePressureLevelMetric(string(m))
+	if !ok {
+		return parseUnit(u, string(m))
+	}
+	lu, ok := u.PressureLevels[level]
+	if !ok {
+		return UnitDimensionless, nil
+	}
+	unit, err := parseUnit(*lu, string(variable))
+	if err != nil {
+		return "", fmt.Errorf("%dhPa: %w", level, err)
+	}
+	return unit, nil
+}
+
+// Units parses the unit labels into typed Units, keyed by metric.
+func (u DailyUnits) Units() (map[Metric]Unit, error) { return parseUnits(u) }
+
+// Unit parses the unit label of a single metric. Unlike Units, it ignores
+// the labels of other metrics.
+func (u DailyUnits) Unit(m Metric) (Unit, error) { return parseUnit(u, string(m)) }
+
+// Units parses the unit labels into typed Units, keyed by metric.
+func (u WeeklyUnits) Units() (map[Metric]Unit, error) { return parseUnits(u) }
+
+// Unit parses the unit label of a single metric. Unlike Units, it ignores
+// the labels of other metrics.
+func (u WeeklyUnits) Unit(m Metric) (Unit, error) { return parseUnit(u, string(m)) }
+
+// Units parses the unit labels into typed Units, keyed by metric.
+func (u MonthlyUnits) Units() (map[Metric]Unit, error) { return parseUnits(u) }
+
+// Unit parses the unit label of a single metric. Unlike Units, it ignores
+// the labels of other metrics.
+func (u MonthlyUnits) Unit(m Metric) (Unit, error) { return parseUnit(u, string(m)) }
@@ -15,9 +15,12 @@
 package openmeteogo
 
 import (
+	"encoding/json"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestTemperatureUnitString(t *testing.T) {
@@ -95,6 +98,10 @@ func TestWindSpeedUnitString(t *testing.T) {
 			input: KN,
 			want:  "kn",
 		},
+		"bft": {
+			input: BFT,
+			want:  "bft",
+		},
 		"unknown": {
 			input: "unknown",
 			want:  "unknown",
@@ -108,3 +115,172 @@ func TestWindSpeedUnitString(t *testing.T) {
 	}
 
 }
+
+func TestParseUnit(t *testing.T) {
+	tests := map[string]struct {
+		input   string
+		want    Unit
+		wantDim Dimension
+		wantErr bool
+	}{
+		"celsius":       {input: "°C", want: UnitCelsius, wantDim: DimensionTemperature},
+		"kmh":           {input: "km/h", want: UnitKmh, wantDim: DimensionSpeed},
+		"mph alias":     {input: "mph", want: UnitMph, wantDim: DimensionSpeed},
+		"snow depth":    {input: "ft", want: UnitFoot, wantDim: DimensionLength},
+		"weather code":  {input: "wmo code", want: UnitWMOCode, wantDim: DimensionWeatherCode},
+		"time":          {input: "iso8601", want: UnitISO8601, wantDim: DimensionTime},
+		"dimensionless": {input: "", want: UnitDimensionless, wantDim: DimensionNone},
+		"unknown":       {input: "furlongs", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseUnit(tc.input)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+			assert.Equal(t, tc.wantDim, got.Dimension())
+		})
+	}
+}
+
+func TestConvert(t *testing.T) {
+	tests := map[string]struct {
+		value    float64
+		from, to Unit
+		want     float64
+		wantErr  bool
+	}{
+		"celsius to fahrenheit": {value: 100, from: UnitCelsius, to: UnitFahrenheit, want: 212},
+		"fahrenheit to celsius": {value: 32, from: UnitFahrenheit, to: UnitCelsius, want: 0},
+		"kmh to ms":             {value: 36, from: UnitKmh, to: UnitMs, want: 10},
+		"knots to kmh":          {value: 10, from: UnitKnots, to: UnitKmh, want: 18.52},
+		"inch to mm":            {value: 1, from: UnitInch, to: UnitMillimetre, want: 25.4},
+		"inch to cm":            {value: 1, from: UnitInch, to: UnitCentimetre, want: 2.54},
+		"hPa to kPa":            {value: 1013, from: UnitHectopascal, to: UnitKilopascal, want: 101.3},
+		"hPa to inHg":           {value: 1013.25, from: UnitHectopascal, to: UnitInchMercury, want: 29.9213},
+		"metres to miles":       {value: 16093.44, from: UnitMetre, to: UnitMile, want: 10},
+		"kmh to beaufort":       {value: 20, from: UnitKmh, to: UnitBeaufort, want: 4},
+		"calm to beaufort":      {value: 0.2, from: UnitMs, to: UnitBeaufort, want: 0},
+		"hurricane to beaufort": {value: 40, from: UnitMs, to: UnitBeaufort, want: 12},
+		"beaufort to ms":        {value: 4, from: UnitBeaufort, to: UnitMs, want: 6.688},
+		"beaufort to celsius":   {value: 4, from: UnitBeaufort, to: UnitCelsius, wantErr: true},
+		"same unit":             {value: 5, from: UnitDegree, to: UnitDegree, want: 5},
+		"different dimension":   {value: 1, from: UnitCelsius, to: UnitKmh, wantErr: true},
+		"not convertible":       {value: 1, from: UnitWMOCode, to: UnitISO8601, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := Convert(tc.value, tc.from, tc.to)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.InDelta(t, tc.want, got, 1e-4)
+		})
+	}
+}
+
+func TestConvertDifference(t *testing.T) {
+	got, err := ConvertDifference(2, UnitCelsius, UnitFahrenheit)
+	require.NoError(t, err)
+	assert.InDelta(t, 3.6, got, 1e-9, "no offset for a difference")
+
+	got, err = ConvertDifference(-9, UnitFahrenheit, UnitCelsius)
+	require.NoError(t, err)
+	assert.InDelta(t, -5, got, 1e-9)
+
+	_, err = ConvertDifference(1, UnitBeaufort, UnitMs)
+	assert.Error(t, err)
+}
+
+func TestMetricDimension(t *testing.T) {
+	all := [][]Metric{hourlyMetrics, dailyMetrics, currentMetrics, minutely15Metrics}
+	for _, metrics := range all {
+		for _, m := range metrics {
+			_, ok := m.Dimension()
+			assert.True(t, ok, "no dimension registered for %s", m)
+		}
+	}
+
+	dim, ok := Metric("wind_speed_500hPa").Dimension()
+	assert.True(t, ok)
+	assert.Equal(t, DimensionSpeed, dim)
+
+	_, ok = Metric("not_a_metric").Dimension()
+	assert.False(t, ok)
+
+	dim, _ = Temperature2mAnomaly.Dimension()
+	assert.Equal(t, DimensionTemperatureDifference, dim)
+}
+
+func TestMetric_Convert(t *testing.T) {
+	got, err := Temperature2mAnomaly.Convert(2, UnitCelsius, UnitFahrenheit)
+	require.NoError(t, err)
+	assert.InDelta(t, 3.6, got, 1e-9, "anomalies are differences")
+
+	got, err = Temperature2mMean.Convert(2, UnitCelsius, UnitFahrenheit)
+	require.NoError(t, err)
+	assert.InDelta(t, 35.6, got, 1e-9)
+}
+
+func TestSectionUnits(t *testing.T) {
+	dat, err := os.ReadFile("test_data/all_params.json")
+	require.NoError(t, err)
+	var wd WeatherData
+	require.NoError(t, json.Unmarshal(dat, &wd))
+
+	hourly, err := wd.HourlyUnits.Units()
+	require.NoError(t, err)
+	assert.Equal(t, UnitCelsius, hourly[Temperature2m])
+	assert.Equal(t, UnitCubicMetrePerCubicMetre, hourly[SoilMoisture0To1cm])
+
+	daily, err := wd.DailyUnits.Units()
+	require.NoError(t, err)
+	assert.Equal(t, UnitMegajoulePerSquareMetre, daily[ShortwaveRadiationSum])
+	assert.Equal(t, UnitHour, daily[PrecipitationHours])
+
+	current, err := wd.CurrentUnits.Units()
+	require.NoError(t, err)
+	assert.Equal(t, UnitWMOCode, current[WeatherCode])
+
+	levels := HourlyUnits{PressureLevels: map[int]*PressureLevelUnits{850: {Temperature: "°C"}}}
+	got, err := levels.Units()
+	require.NoError(t, err)
+	assert.Equal(t, UnitCelsius, got["temperature_850hPa"])
+
+	_, err = HourlyUnits{Temperature2m: "°K"}.Units()
+	assert.ErrorContains(t, err, "temperature_2m")
+}
+
+func TestSectionUnit(t *testing.T) {
+	hu := HourlyUnits{
+		Temperature2m:  "°C",
+		WindSpeed10m:   "furlong/fortnight",
+		PressureLevels: map[int]*PressureLevelUnits{850: {Temperature: "°F"}},
+	}
+
+	got, err := hu.Unit(Temperature2m)
+	require.NoError(t, err, "other labels are not parsed")
+	assert.Equal(t, UnitCelsius, got)
+
+	got, err = hu.Unit("temperature_850hPa")
+	require.NoError(t, err)
+	assert.Equal(t, UnitFahrenheit, got)
+
+	got, err = hu.Unit(Precipitation)
+	require.NoError(t, err)
+	assert.Equal(t, UnitDimensionless, got, "no label")
+
+	_, err = hu.Unit(WindSpeed10m)
+	assert.EqualError(t, err, `wind_speed_10m: unrecognised unit: "furlong/fortnight"`)
+	_, err = hu.Unit("not_a_metric")
+	assert.EqualError(t, err, "unknown metric: not_a_metric")
+
+	got, err = DailyUnits{PrecipitationSum: "inch"}.Unit(PrecipitationSum)
+	require.NoError(t, err)
+	assert.Equal(t, UnitInch, got)
+}
@@ -0,0 +1,322 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package verify scores stored forecasts against observed data, such as
+// responses from the archive API, per model and lead time.
+package verify
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/tpryan/openmeteogo"
+)
+
+// Forecast is a stored forecast response.
+type Forecast struct {
+	// Issued is when the forecast was made. Its wall clock time is compared
+	// with the hourly timestamps, which are in the response's timezone. If
+	// zero, the first hourly timestamp of the data is used.
+	Issued time.Time
+	// Model names the model when Data holds a single model. Models in
+	// Data.ByModel are scored under their own names.
+	Model string
+	// Data is the forecast response.
+	Data *openmeteogo.WeatherData
+}
+
+// Config describes what to verify.
+type Config struct {
+	// Metric is the hourly metric to verify, e.g. Temperature2m.
+	Metric openmeteogo.Metric
+	// LeadBucket groups lead times. The default is 24 hours, so scores are
+	// reported for day 1, day 2 and so on.
+	LeadBucket time.Duration
+	// Thresholds are the event thresholds for categorical scores, in the
+	// units of the observations. An event occurs when the value is at or
+	// above the threshold, e.g. 1 mm of precipitation.
+	Thresholds []float64
+}
+
+// Contingency counts forecast and observed events for one threshold.
+type Contingency struct {
+	// Threshold is the event threshold.
+	Threshold float64
+	// Hits are events that were forecast and observed.
+	Hits int
+	// Misses are events that were observed but not forecast.
+	Misses int
+	// FalseAlarms are events that were forecast but not observed.
+	FalseAlarms int
+	// CorrectNegatives are non-events that were forecast as such.
+	CorrectNegatives int
+}
+
+// HitRate returns the fraction of observed events that were forecast, or
+// NaN if no events were observed.
+func (c Contingency) HitRate() float64 {
+	return ratio(c.Hits, c.Hits+c.Misses)
+}
+
+// FalseAlarmRatio returns the fraction of forecast events that did not
+// occur, or NaN if no events were forecast.
+func (c Contingency) FalseAlarmRatio() float64 {
+	return ratio(c.FalseAlarms, c.Hits+c.FalseAlarms)
+}
+
+func ratio(n, d int) float64 {
+	if d == 0 {
+		return math.NaN()
+	}
+	return float64(n) / float64(d)
+}
+
+// Scores holds the verification scores for one model and lead time.
+type Scores struct {
+	// Model is the model name.
+	Model string
+	// Lead is the start of the lead time bucket, e.g. 24h for day 2 with
+	// the default bucket. It is -1 for scores combined over all lead times.
+	Lead time.Duration
+	// Count is the number of forecast and observation pairs.
+	Count int
+	// MAE is the mean absolute error.
+	MAE float64
+	// RMSE is the root mean square error.
+	RMSE float64
+	// Bias is the mean error, forecast minus observation.
+	Bias float64
+	// Contingency holds the categorical counts for each threshold.
+	Contingency []Contingency
+
+	sumAbs, sumSq, sumErr float64
+}
+
+func (s *Scores) add(forecast, observed float64) {
+	err := forecast - observed
+	s.Count++
+	s.sumAbs += math.Abs(err)
+	s.sumSq += err * err
+	s.sumErr += err
+	for i := range s.Contingency {
+		c := &s.Contingency[i]
+		f, o := forecast >= c.Threshold, observed >= c.Threshold
+		switch {
+		case f && o:
+			c.Hits++
+		case o:
+			c.Misses++
+		case f:
+			c.FalseAlarms++
+		default:
+			c.CorrectNegatives++
+		}
+	}
+}
+
+func (s *Scores) merge(o *Scores) {
+	s.Count += o.Count
+	s.sumAbs += o.sumAbs
+	s.sumSq += o.sumSq
+	s.sumErr += o.sumErr
+	for i := range s.Contingency {
+		s.Contingency[i].Hits += o.Contingency[i].Hits
+		s.Contingency[i].Misses += o.Contingency[i].Misses
+		s.Contingency[i].FalseAlarms += o.Contingency[i].FalseAlarms
+		s.Contingency[i].CorrectNegatives += o.Contingency[i].CorrectNegatives
+	}
+}
+
+func (s *Scores) finish() {
+	n := float64(s.Count)
+	s.MAE = s.sumAbs / n
+	s.RMSE = math.Sqrt(s.sumSq / n)
+	s.Bias = s.sumErr / n
+}
+
+func newScores(model string, lead time.Duration, thresholds []float64) *Scores {
+	s := &Scores{Model: model, Lead: lead, Contingency: make([]Contingency, len(thresholds))}
+	for i, t := range thresholds {
+		s.Contingency[i].Threshold = t
+	}
+	return s
+}
+
+// series is one model's hourly values for the verified metric.
+type series struct {
+	model  string
+	issued time.Time
+	times  []string
+	values []float64
+}
+
+// Verify aligns each forecast with the observations on hourly time and
+// returns scores per model and lead time bucket, ordered by model then lead.
+// Forecast values are converted to the units of the observations, and
+// timestamps without an observation are skipped.
+func Verify(forecasts []Forecast, observed *openmeteogo.WeatherData, cfg Config) ([]Scores, error) {
+	if cfg.Metric == "" {
+		return nil, fmt.Errorf("no metric to verify")
+	}
+	bucket := cfg.LeadBucket
+	if bucket <= 0 {
+		bucket = 24 * time.Hour
+	}
+
+	obsValues, err := observed.Hourly.Values(cfg.Metric)
+	if err != nil {
+		return nil, fmt.Errorf("observations: %w", err)
+	}
+	if len(obsValues) != len(observed.Hourly.Time) {
+		return nil, fmt.Errorf("observations: %s: need %d values, got %d", cfg.Metric, len(observed.Hourly.Time), len(obsValues))
+	}
+	obsUnit, err := observed.HourlyUnits.Unit(cfg.Metric)
+	if err != nil {
+		return nil, fmt.Errorf("observations: %w", err)
+	}
+	obs := make(map[string]float64, len(obsValues))
+	for i, ts := range observed.Hourly.Time {
+		obs[ts] = obsValues[i]
+	}
+
+	scores := map[string]map[time.Duration]*Scores{}
+	for i, f := range forecasts {
+		all, err := forecastSeries(f, cfg.Metric, obsUnit)
+		if err != nil {
+			return nil, fmt.Errorf("forecast %d: %w", i, err)
+		}
+		for _, s := range all {
+			for j, ts := range s.times {
+				o, ok := obs[ts]
+				if !ok {
+					continue
+				}
+				valid, err := time.Parse(openmeteogo.HourFormat, ts)
+				if err != nil {
+					return nil, fmt.Errorf("forecast %d: parsing time %q: %w", i, ts, err)
+				}
+				lead := valid.Sub(s.issued)
+				if lead < 0 {
+					continue
+				}
+				lead = lead / bucket * bucket
+				if scores[s.model] == nil {
+					scores[s.model] = map[time.Duration]*Scores{}
+				}
+				sc, ok := scores[s.model][lead]
+				if !ok {
+					sc = newScores(s.model, lead, cfg.Thresholds)
+					scores[s.model][lead] = sc
+				}
+				sc.add(s.values[j], o)
+			}
+		}
+	}
+
+	var result []Scores
+	for _, byLead := range scores {
+		for _, sc := range byLead {
+			sc.finish()
+			result = append(result, *sc)
+		}
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("no forecast times match the observations")
+	}
+	slices.SortFunc(result, func(a, b Scores) int {
+		return cmp.Or(cmp.Compare(a.Model, b.Model), cmp.Compare(a.Lead, b.Lead))
+	})
+	return result, nil
+}
+
+// ByModel combines scores over all lead times, returning one Scores per
+// model with Lead set to -1, ordered from the lowest RMSE to the highest.
+func ByModel(scores []Scores) []Scores {
+	combined := map[string]*Scores{}
+	var models []string
+	for i := range scores {
+		s := &scores[i]
+		c, ok := combined[s.Model]
+		if !ok {
+			thresholds := make([]float64, len(s.Contingency))
+			for j, ct := range s.Contingency {
+				thresholds[j] = ct.Threshold
+			}
+			c = newScores(s.Model, -1, thresholds)
+			combined[s.Model] = c
+			models = append(models, s.Model)
+		}
+		c.merge(s)
+	}
+	result := make([]Scores, 0, len(models))
+	for _, m := range models {
+		combined[m].finish()
+		result = append(result, *combined[m])
+	}
+	slices.SortStableFunc(result, func(a, b Scores) int { return cmp.Compare(a.RMSE, b.RMSE) })
+	return result
+}
+
+// forecastSeries returns the series for metric in a forecast, one per
+// model, converted to unit.
+func forecastSeries(f Forecast, metric openmeteogo.Metric, to openmeteogo.Unit) ([]series, error) {
+	if f.Data == nil {
+		return nil, fmt.Errorf("no data")
+	}
+	type section struct {
+		model string
+		units openmeteogo.HourlyUnits
+		data  *openmeteogo.Hourly
+	}
+	var sections []section
+	if len(f.Data.ByModel) > 0 {
+		for model, md := range f.Data.ByModel {
+			sections = append(sections, section{model, md.HourlyUnits, &md.Hourly})
+		}
+	} else {
+		sections = append(sections, section{f.Model, f.Data.HourlyUnits, &f.Data.Hourly})
+	}
+
+	var result []series
+	for _, sec := range sections {
+		values, err := sec.data.Values(metric)
+		if err != nil {
+			return nil, err
+		}
+		if len(values) != len(sec.data.Time) {
+			return nil, fmt.Errorf("%s: need %d values, got %d", metric, len(sec.data.Time), len(values))
+		}
+		from, err := sec.units.Unit(metric)
+		if err != nil {
+			return nil, err
+		}
+		for i, v := range values {
+			if values[i], err = openmeteogo.Convert(v, from, to); err != nil {
+				return nil, fmt.Errorf("%s: %w", metric, err)
+			}
+		}
+		issued := time.Date(f.Issued.Year(), f.Issued.Month(), f.Issued.Day(),
+			f.Issued.Hour(), f.Issued.Minute(), 0, 0, time.UTC)
+		if f.Issued.IsZero() {
+			if issued, err = time.Parse(openmeteogo.HourFormat, sec.data.Time[0]); err != nil {
+				return nil, fmt.Errorf("parsing time %q: %w", sec.data.Time[0], err)
+			}
+		}
+		result = append(result, series{model: sec.model, issued: issued, times: sec.data.Time, values: values})
+	}
+	return result, nil
+}
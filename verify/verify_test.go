@@ -0,0 +1,164 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tpryan/openmeteogo"
+)
+
+// hourly returns hourly data starting at start with the given values.
+func hourly(start string, unit string, values ...float64) *openmeteogo.WeatherData {
+	t, _ := time.Parse(openmeteogo.HourFormat, start)
+	wd := &openmeteogo.WeatherData{
+		HourlyUnits: openmeteogo.HourlyUnits{Temperature2m: unit, Precipitation: "mm"},
+	}
+	for i, v := range values {
+		wd.Hourly.Time = append(wd.Hourly.Time, t.Add(time.Duration(i)*time.Hour).Format(openmeteogo.HourFormat))
+		wd.Hourly.Temperature2m = append(wd.Hourly.Temperature2m, v)
+		wd.Hourly.Precipitation = append(wd.Hourly.Precipitation, v)
+	}
+	return wd
+}
+
+func TestVerify(t *testing.T) {
+	observed := hourly("2025-01-01T00:00", "°C", 0, 1, 2, 3, 4, 5)
+	forecasts := []Forecast{
+		// Issued at midnight: errors +1 and -1 in the first two hours,
+		// +2 in the next two.
+		{Model: "a", Data: hourly("2025-01-01T00:00", "°C", 1, 0, 4, 5)},
+		// Issued at 02:00 in °F: exact for two hours.
+		{Model: "a", Data: hourly("2025-01-01T02:00", "°F", 35.6, 37.4)},
+		// Issued two hours before its data starts: errors of 3.
+		{Model: "b", Issued: time.Date(2025, 1, 1, 2, 0, 0, 0, time.UTC), Data: hourly("2025-01-01T04:00", "°C", 7, 8)},
+	}
+
+	got, err := Verify(forecasts, observed, Config{Metric: openmeteogo.Temperature2m, LeadBucket: 2 * time.Hour})
+	require.NoError(t, err)
+	require.Len(t, got, 3)
+
+	assert.Equal(t, "a", got[0].Model)
+	assert.Equal(t, time.Duration(0), got[0].Lead)
+	assert.Equal(t, 4, got[0].Count)
+	assert.InDelta(t, 0.5, got[0].MAE, 1e-9)
+	assert.InDelta(t, math.Sqrt(0.5), got[0].RMSE, 1e-9)
+	assert.InDelta(t, 0, got[0].Bias, 1e-9)
+
+	assert.Equal(t, "a", got[1].Model)
+	assert.Equal(t, 2*time.Hour, got[1].Lead)
+	assert.Equal(t, 2, got[1].Count)
+	assert.InDelta(t, 2, got[1].MAE, 1e-9)
+	assert.InDelta(t, 2, got[1].Bias, 1e-9)
+
+	assert.Equal(t, "b", got[2].Model)
+	assert.Equal(t, 2*time.Hour, got[2].Lead)
+	assert.InDelta(t, 3, got[2].RMSE, 1e-9)
+	assert.InDelta(t, 3, got[2].Bias, 1e-9)
+
+	models := ByModel(got)
+	require.Len(t, models, 2)
+	assert.Equal(t, "a", models[0].Model)
+	assert.Equal(t, time.Duration(-1), models[0].Lead)
+	assert.Equal(t, 6, models[0].Count)
+	assert.InDelta(t, 1, models[0].MAE, 1e-9)
+	assert.InDelta(t, math.Sqrt(10.0/6), models[0].RMSE, 1e-9)
+	assert.Equal(t, "b", models[1].Model)
+}
+
+func TestVerify_Categorical(t *testing.T) {
+	observed := hourly("2025-01-01T00:00", "°C", 0, 2, 0, 5, 0.2)
+	forecast := Forecast{Model: "gfs", Data: hourly("2025-01-01T00:00", "°C", 1, 3, 0, 0, 2)}
+
+	got, err := Verify([]Forecast{forecast}, observed, Config{Metric: openmeteogo.Precipitation, Thresholds: []float64{1, 10}})
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+
+	c := got[0].Contingency[0]
+	assert.Equal(t, Contingency{Threshold: 1, Hits: 1, Misses: 1, FalseAlarms: 2, CorrectNegatives: 1}, c)
+	assert.InDelta(t, 0.5, c.HitRate(), 1e-9)
+	assert.InDelta(t, 2.0/3, c.FalseAlarmRatio(), 1e-9)
+
+	none := got[0].Contingency[1]
+	assert.Equal(t, 5, none.CorrectNegatives)
+	assert.True(t, math.IsNaN(none.HitRate()))
+	assert.True(t, math.IsNaN(none.FalseAlarmRatio()))
+}
+
+func TestVerify_ByModelData(t *testing.T) {
+	observed := hourly("2025-01-01T00:00", "°C", 10, 10)
+	multi := &openmeteogo.WeatherData{
+		ByModel: map[string]*openmeteogo.ModelData{
+			"icon_seamless": {HourlyUnits: observed.HourlyUnits, Hourly: hourly("2025-01-01T00:00", "°C", 11, 11).Hourly},
+			"gfs_seamless":  {HourlyUnits: observed.HourlyUnits, Hourly: hourly("2025-01-01T00:00", "°C", 10, 10).Hourly},
+		},
+	}
+
+	got, err := Verify([]Forecast{{Data: multi}}, observed, Config{Metric: openmeteogo.Temperature2m})
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "gfs_seamless", got[0].Model)
+	assert.Equal(t, 0.0, got[0].MAE)
+	assert.Equal(t, "icon_seamless", got[1].Model)
+	assert.Equal(t, 1.0, got[1].MAE)
+}
+
+func TestVerify_UnrelatedUnit(t *testing.T) {
+	observed := hourly("2025-01-01T00:00", "°C", 1, 2)
+	observed.HourlyUnits.WindSpeed10m = "furlong/fortnight"
+	forecast := hourly("2025-01-01T00:00", "°C", 2, 3)
+	forecast.HourlyUnits.WindSpeed10m = "furlong/fortnight"
+
+	got, err := Verify([]Forecast{{Data: forecast}}, observed, Config{Metric: openmeteogo.Temperature2m})
+	require.NoError(t, err, "only the label of temperature_2m is parsed")
+	require.Len(t, got, 1)
+	assert.InDelta(t, 1, got[0].Bias, 1e-9)
+}
+
+func TestVerify_Errors(t *testing.T) {
+	observed := hourly("2025-01-01T00:00", "°C", 1, 2)
+
+	tests := map[string]struct {
+		forecasts []Forecast
+		cfg       Config
+	}{
+		"no metric": {
+			forecasts: []Forecast{{Data: observed}},
+		},
+		"metric not observed": {
+			forecasts: []Forecast{{Data: observed}},
+			cfg:       Config{Metric: openmeteogo.Snowfall},
+		},
+		"no forecast data": {
+			forecasts: []Forecast{{}},
+			cfg:       Config{Metric: openmeteogo.Temperature2m},
+		},
+		"no overlap": {
+			forecasts: []Forecast{{Data: hourly("2026-01-01T00:00", "°C", 1)}},
+			cfg:       Config{Metric: openmeteogo.Temperature2m},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Verify(tc.forecasts, observed, tc.cfg)
+			assert.Error(t, err)
+		})
+	}
+}
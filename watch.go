@@ -0,0 +1,133 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openmeteogo
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+)
+
+const (
+	// defaultWatchInterval is used when Watch is given no interval. Most
+	// models behind the forecast API update hourly.
+	defaultWatchInterval = time.Hour
+
+	// watchJitter is the largest random delay added to each poll, as a
+	// fraction of the interval, so that many watchers do not poll at once.
+	watchJitter = 10
+
+	// watchBackoff is the first retry delay after a failed poll, as a
+	// fraction of the interval. It doubles with each failure, up to the
+	// interval itself.
+	watchBackoff = 16
+)
+
+// Update is a message delivered by Watch.
+type Update struct {
+	// Data is the latest response. It is nil when Err is set.
+	Data *WeatherData
+	// Changes lists what changed since the previous update, as reported by
+	// Diff. It is nil for the first update.
+	Changes []Change
+	// Err is set when a poll failed. Watch keeps going, retrying with
+	// backoff until a poll succeeds.
+	Err error
+}
+
+// Watch polls for weather data with the given Options and delivers an Update
+// on the returned channel whenever the response changes, until ctx is
+// cancelled, at which point the channel is closed.
+//
+// The first poll is made straight away. After that, polls are aligned to
+// multiples of interval on the UTC clock, so an hourly watch polls shortly
+// after each hour when models publish their updates, plus a random jitter of
+// up to a tenth of the interval. Responses for which Diff finds no changes
+// are skipped. An interval of zero or less polls hourly.
+//
+// Polls are not aligned to the run schedule of the models requested, as
+// the forecast API does not report when a model last updated; choose an
+// interval that suits them. The Client neither retries nor caches
+// requests, so failed polls are only retried by Watch's own backoff, and
+// every poll is sent to the API.
+func (c *Client) Watch(ctx context.Context, o *Options, interval time.Duration) <-chan Update {
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+	updates := make(chan Update)
+	go func() {
+		defer close(updates)
+		var last *WeatherData
+		var wait time.Duration
+		failures := 0
+		for {
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+
+			var u Update
+			wd, err := c.get(ctx, o)
+			if err == nil && last != nil {
+				u.Changes, err = Diff(last, wd, nil)
+			}
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				failures++
+				wait = backoff(interval, failures)
+				u.Err = err
+			} else {
+				failures = 0
+				wait = untilNextPoll(time.Now(), interval)
+				if last != nil && len(u.Changes) == 0 {
+					continue
+				}
+				last, u.Data = wd, wd
+			}
+
+			select {
+			case updates <- u:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return updates
+}
+
+// untilNextPoll returns how long to wait from now until the next multiple of
+// interval, plus jitter.
+func untilNextPoll(now time.Time, interval time.Duration) time.Duration {
+	d := now.Truncate(interval).Add(interval).Sub(now)
+	if j := interval / watchJitter; j > 0 {
+		d += rand.N(j)
+	}
+	return d
+}
+
+// backoff returns the delay before retrying after the given number of
+// consecutive failures.
+func backoff(interval time.Duration, failures int) time.Duration {
+	d := max(interval/watchBackoff, time.Millisecond)
+	for i := 1; i < failures && d < interval; i++ {
+		d *= 2
+	}
+	return min(d, interval)
+}
@@ -0,0 +1,123 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openmeteogo
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Watch(t *testing.T) {
+	responses := []string{
+		`{"current_units": {"temperature_2m": "°C"}, "current": {"time": "2025-01-01T10:00", "temperature_2m": 5}}`,
+		// Unchanged apart from the time, so skipped.
+		`{"current_units": {"temperature_2m": "°C"}, "current": {"time": "2025-01-01T10:15", "temperature_2m": 5}}`,
+		`{"current_units": {"temperature_2m": "°C"}, "current": {"time": "2025-01-01T10:30", "temperature_2m": 6}}`,
+		"",
+		`{"current_units": {"temperature_2m": "°C"}, "current": {"time": "2025-01-01T10:45", "temperature_2m": 4}}`,
+	}
+	var mu sync.Mutex
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		body := responses[min(polls, len(responses)-1)]
+		polls++
+		if body == "" {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		rw.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	client := NewClient()
+	client.HTTPClient = server.Client()
+	urlParts := strings.Split(server.URL, "://")
+	client.scheme = urlParts[0]
+	client.host = urlParts[1]
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	updates := client.Watch(ctx, NewOptionsBuilder().Build(), 10*time.Millisecond)
+
+	next := func() Update {
+		t.Helper()
+		select {
+		case u := <-updates:
+			return u
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "no update")
+		}
+		return Update{}
+	}
+
+	first := next()
+	require.NoError(t, first.Err)
+	assert.Equal(t, 5.0, first.Data.Current.Temperature2m)
+	assert.Nil(t, first.Changes)
+
+	second := next()
+	require.NoError(t, second.Err)
+	assert.Equal(t, 6.0, second.Data.Current.Temperature2m)
+	require.Len(t, second.Changes, 1)
+	assert.Equal(t, Change{Section: "current", Metric: Temperature2m, Time: "2025-01-01T10:30", Kind: ChangeValue, Old: 5, New: 6, Unit: UnitCelsius}, second.Changes[0])
+
+	failed := next()
+	assert.ErrorContains(t, failed.Err, "503")
+	assert.Nil(t, failed.Data)
+
+	// Changes are relative to the last delivered data.
+	third := next()
+	require.NoError(t, third.Err)
+	require.Len(t, third.Changes, 1)
+	assert.Equal(t, 6.0, third.Changes[0].Old)
+	assert.Equal(t, 4.0, third.Changes[0].New)
+
+	cancel()
+	for range updates {
+	}
+}
+
+func TestUntilNextPoll(t *testing.T) {
+	now := time.Date(2025, 1, 1, 10, 20, 0, 0, time.UTC)
+	for i := 0; i < 100; i++ {
+		d := untilNextPoll(now, time.Hour)
+		assert.GreaterOrEqual(t, d, 40*time.Minute)
+		assert.Less(t, d, 46*time.Minute)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := map[int]time.Duration{
+		1: 225 * time.Second,
+		2: 450 * time.Second,
+		3: 15 * time.Minute,
+		5: time.Hour,
+		9: time.Hour,
+	}
+
+	for failures, want := range tests {
+		assert.Equal(t, want, backoff(time.Hour, failures), "%d failures", failures)
+	}
+}